@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IAnswerController interface {
+	GetAnswers(c echo.Context) error
+	CreateAnswer(c echo.Context) error
+	DeleteAnswer(c echo.Context) error
+	VoteAnswer(c echo.Context) error
+	DeleteVote(c echo.Context) error
+	AcceptAnswer(c echo.Context) error
+	UnacceptAnswer(c echo.Context) error
+	GetUnansweredQuestions(c echo.Context) error
+}
+
+type answerController struct {
+	au usecase.IAnswerUsecase
+}
+
+func NewAnswerController(au usecase.IAnswerUsecase) IAnswerController {
+	return &answerController{au}
+}
+
+func (ac *answerController) GetAnswers(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	postId, err := strconv.ParseUint(c.Param("postId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid post ID"})
+	}
+	res, err := ac.au.GetAnswers(userId, uint(postId))
+	if err != nil {
+		return answerError(c, err)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (ac *answerController) CreateAnswer(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	postId, err := strconv.ParseUint(c.Param("postId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid post ID"})
+	}
+	req := model.AnswerRequest{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	res, err := ac.au.CreateAnswer(userId, uint(postId), req.Content)
+	if err != nil {
+		return answerError(c, err)
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (ac *answerController) DeleteAnswer(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	answerId, err := strconv.ParseUint(c.Param("answerId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid answer ID"})
+	}
+	if err := ac.au.DeleteAnswer(userId, uint(answerId)); err != nil {
+		return answerError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// リクエストボディ: {"value": 1} または {"value": -1}
+func (ac *answerController) VoteAnswer(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	answerId, err := strconv.ParseUint(c.Param("answerId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid answer ID"})
+	}
+	req := model.AnswerVoteRequest{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	res, err := ac.au.VoteAnswer(userId, uint(answerId), req.Value)
+	if err != nil {
+		return answerError(c, err)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (ac *answerController) DeleteVote(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	answerId, err := strconv.ParseUint(c.Param("answerId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid answer ID"})
+	}
+	res, err := ac.au.DeleteVote(userId, uint(answerId))
+	if err != nil {
+		return answerError(c, err)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// リクエストボディ: {"answer_id": 1}
+func (ac *answerController) AcceptAnswer(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	postId, err := strconv.ParseUint(c.Param("postId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid post ID"})
+	}
+	req := model.AcceptAnswerRequest{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	res, err := ac.au.AcceptAnswer(userId, uint(postId), req.AnswerID)
+	if err != nil {
+		return answerError(c, err)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (ac *answerController) UnacceptAnswer(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	postId, err := strconv.ParseUint(c.Param("postId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid post ID"})
+	}
+	res, err := ac.au.UnacceptAnswer(userId, uint(postId))
+	if err != nil {
+		return answerError(c, err)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// クエリ: university_id, department_id, offset, limit
+func (ac *answerController) GetUnansweredQuestions(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	var universityId, departmentId *uint
+	if v := c.QueryParam("university_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid university ID"})
+		}
+		u := uint(id)
+		universityId = &u
+	}
+	if v := c.QueryParam("department_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid department ID"})
+		}
+		d := uint(id)
+		departmentId = &d
+	}
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+	res, err := ac.au.GetUnansweredQuestions(userId, universityId, departmentId, offset, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func answerError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Not found"})
+	case errors.Is(err, usecase.ErrForbidden):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrContentRejected):
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+}
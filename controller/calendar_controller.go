@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ICalendarController interface {
+	GetPlanCalendar(c echo.Context) error
+	GetFeedCalendar(c echo.Context) error
+	CreateCalendarFeed(c echo.Context) error
+	RevokeCalendarFeeds(c echo.Context) error
+	GetPeriodTimes(c echo.Context) error
+	ReplacePeriodTimes(c echo.Context) error
+	GetHolidays(c echo.Context) error
+	CreateHoliday(c echo.Context) error
+	DeleteHolidayByID(c echo.Context) error
+}
+
+type calendarController struct {
+	cu usecase.ICalendarUsecase
+}
+
+func NewCalendarController(cu usecase.ICalendarUsecase) ICalendarController {
+	return &calendarController{cu}
+}
+
+const icsContentType = "text/calendar; charset=utf-8"
+
+func (cc *calendarController) GetPlanCalendar(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	ics, err := cc.cu.GetPlanCalendar(userId, uint(planId))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"plan-%d.ics\"", planId))
+	return c.Blob(http.StatusOK, icsContentType, ics)
+}
+
+// カレンダーアプリから購読されるため認証は行わずトークンで判定する
+func (cc *calendarController) GetFeedCalendar(c echo.Context) error {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	ics, err := cc.cu.GetFeedCalendar(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Feed not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.Blob(http.StatusOK, icsContentType, ics)
+}
+
+func (cc *calendarController) CreateCalendarFeed(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	res, err := cc.cu.CreateCalendarFeed(userId, uint(planId))
+	if err != nil {
+		return calendarFeedErrorResponse(c, err)
+	}
+	res.URL = fmt.Sprintf("%s://%s/calendar/feeds/%s.ics", c.Scheme(), c.Request().Host, res.Token)
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (cc *calendarController) RevokeCalendarFeeds(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	if err := cc.cu.RevokeCalendarFeeds(userId, uint(planId)); err != nil {
+		return calendarFeedErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (cc *calendarController) GetPeriodTimes(c echo.Context) error {
+	universityId, err := strconv.ParseUint(c.QueryParam("university_id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid university ID"})
+	}
+	res, err := cc.cu.GetPeriodTimes(uint(universityId))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (cc *calendarController) ReplacePeriodTimes(c echo.Context) error {
+	universityId, err := strconv.ParseUint(c.Param("universityId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid university ID"})
+	}
+	var periods []model.PeriodTime
+	if err := c.Bind(&periods); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := cc.cu.ReplacePeriodTimes(uint(universityId), periods)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// クエリ: university_id, from, to (YYYY-MM-DD)。期間の指定がない場合は今年度分
+func (cc *calendarController) GetHolidays(c echo.Context) error {
+	universityId, err := strconv.ParseUint(c.QueryParam("university_id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid university ID"})
+	}
+	now := time.Now()
+	from := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(now.Year()+1, time.March, 31, 0, 0, 0, 0, time.UTC)
+	if v := c.QueryParam("from"); v != "" {
+		if from, err = time.Parse("2006-01-02", v); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid from date"})
+		}
+	}
+	if v := c.QueryParam("to"); v != "" {
+		if to, err = time.Parse("2006-01-02", v); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid to date"})
+		}
+	}
+	res, err := cc.cu.GetHolidays(uint(universityId), from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (cc *calendarController) CreateHoliday(c echo.Context) error {
+	holiday := &model.AcademicHoliday{}
+	if err := c.Bind(holiday); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := cc.cu.CreateHoliday(holiday)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (cc *calendarController) DeleteHolidayByID(c echo.Context) error {
+	holidayId, err := strconv.ParseUint(c.Param("holidayId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid holiday ID"})
+	}
+	if err := cc.cu.DeleteHolidayByID(uint(holidayId)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Holiday not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func calendarFeedErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+	case errors.Is(err, usecase.ErrForbidden):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "You are not the owner of this plan"})
+	default:
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ICatalogController interface {
+	SearchCatalogCourses(c echo.Context) error
+	GetCatalogCourseByID(c echo.Context) error
+	CreateCatalogCourse(c echo.Context) error
+	UpdateCatalogCourse(c echo.Context) error
+	DeleteCatalogCourseByID(c echo.Context) error
+	ImportCatalogCourses(c echo.Context) error
+}
+
+type catalogController struct {
+	cu  usecase.ICatalogUsecase
+	ciu usecase.ICatalogImportUsecase
+}
+
+func NewCatalogController(cu usecase.ICatalogUsecase, ciu usecase.ICatalogImportUsecase) ICatalogController {
+	return &catalogController{cu, ciu}
+}
+
+// 1回の検索で返す件数の上限
+const maxCatalogSearchLimit = 100
+
+// クエリ: university_id, q(科目名・コード・担当教員), term, day_of_week, period, offset, limit
+func (cc *catalogController) SearchCatalogCourses(c echo.Context) error {
+	universityId, _ := strconv.Atoi(c.QueryParam("university_id"))
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > maxCatalogSearchLimit {
+		limit = maxCatalogSearchLimit
+	}
+
+	params := model.CatalogCourseSearchParams{
+		UniversityID: uint(universityId),
+		Query:        c.QueryParam("q"),
+		Term:         c.QueryParam("term"),
+	}
+	if v := c.QueryParam("day_of_week"); v != "" {
+		day, err := strconv.Atoi(v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid day_of_week"})
+		}
+		params.DayOfWeek = &day
+	}
+	if v := c.QueryParam("period"); v != "" {
+		period, err := strconv.Atoi(v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid period"})
+		}
+		params.Period = &period
+	}
+
+	res, err := cc.cu.SearchCatalogCourses(params, offset, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (cc *catalogController) GetCatalogCourseByID(c echo.Context) error {
+	catalogCourseId, err := strconv.ParseUint(c.Param("catalogCourseId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid catalog course ID"})
+	}
+	res, err := cc.cu.GetCatalogCourseByID(uint(catalogCourseId))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Catalog course not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (cc *catalogController) CreateCatalogCourse(c echo.Context) error {
+	course := &model.CatalogCourse{}
+	if err := c.Bind(course); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := cc.cu.CreateCatalogCourse(course)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (cc *catalogController) UpdateCatalogCourse(c echo.Context) error {
+	catalogCourseId, err := strconv.ParseUint(c.Param("catalogCourseId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid catalog course ID"})
+	}
+	course := &model.CatalogCourse{}
+	if err := c.Bind(course); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := cc.cu.UpdateCatalogCourse(course, uint(catalogCourseId))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Catalog course not found"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (cc *catalogController) DeleteCatalogCourseByID(c echo.Context) error {
+	catalogCourseId, err := strconv.ParseUint(c.Param("catalogCourseId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid catalog course ID"})
+	}
+	if err := cc.cu.DeleteCatalogCourseByID(uint(catalogCourseId)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Catalog course not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// multipart/form-data: file, university_id, encoding, delimiter, mapping(JSON), dry_run
+func (cc *catalogController) ImportCatalogCourses(c echo.Context) error {
+	universityId, err := strconv.ParseUint(c.FormValue("university_id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid university ID"})
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "File is required"})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	defer file.Close()
+
+	options := model.CatalogImportOptions{
+		UniversityID: uint(universityId),
+		Encoding:     c.FormValue("encoding"),
+		Delimiter:    c.FormValue("delimiter"),
+		DryRun:       c.FormValue("dry_run") == "true",
+	}
+	if options.Delimiter == "" && strings.HasSuffix(strings.ToLower(fileHeader.Filename), ".tsv") {
+		options.Delimiter = "tsv"
+	}
+	if v := c.FormValue("mapping"); v != "" {
+		if err := json.Unmarshal([]byte(v), &options.Mapping); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid mapping"})
+		}
+	}
+
+	report, err := cc.ciu.ImportCatalogCourses(file, options)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if report.Errors > 0 && !report.DryRun {
+		return c.JSON(http.StatusUnprocessableEntity, report)
+	}
+	return c.JSON(http.StatusOK, report)
+}
@@ -1,19 +1,25 @@
 package controller
 
 import (
+	"backend/middleware"
 	"backend/model"
 	"backend/usecase"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type ICommentController interface {
 	CreateComment(c echo.Context) error
 	GetCommentsByPlanID(c echo.Context) error
+	GetReplies(c echo.Context) error
 	GetMyComments(c echo.Context) error
+	UpdateComment(c echo.Context) error
+	GetCommentHistory(c echo.Context) error
 	DeleteComment(c echo.Context) error
 }
 
@@ -26,10 +32,19 @@ func NewCommentController(cu usecase.ICommentUsecase) ICommentController {
 }
 
 func (cc *commentController) CreateComment(c echo.Context) error {
-	comment := &model.Comment{}
-	if err := c.Bind(comment); err != nil {
+	req := model.CommentRequest{}
+	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
+	// 隠し項目が入力されている場合はボットとみなす
+	if req.Website != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	comment := &model.Comment{
+		Content:  req.Content,
+		PlanID:   req.PlanID,
+		ParentID: req.ParentID,
+	}
 
 	// JWTトークンがある場合はユーザーIDを設定
 	if user, ok := c.Get("user").(*jwt.Token); ok {
@@ -40,7 +55,13 @@ func (cc *commentController) CreateComment(c echo.Context) error {
 
 	res, err := cc.cu.CreateComment(comment)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, err.Error())
+		switch {
+		case errors.Is(err, usecase.ErrContentRejected):
+			return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan or parent comment not found"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
 	return c.JSON(http.StatusCreated, res)
@@ -52,14 +73,49 @@ func (cc *commentController) GetCommentsByPlanID(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
 	}
 
-	comments, err := cc.cu.GetCommentsByPlanID(uint(planID))
+	comments, err := cc.cu.GetCommentsByPlanID(uint(planID), optionalUserID(c))
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
 
 	return c.JSON(http.StatusOK, comments)
 }
 
+// 1回に取得できる返信の上限
+const maxReplyPageSize = 50
+
+// クエリ: offset, limit
+func (cc *commentController) GetReplies(c echo.Context) error {
+	commentID, err := strconv.ParseUint(c.Param("commentId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid comment ID"})
+	}
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > maxReplyPageSize {
+		limit = maxReplyPageSize
+	}
+
+	replies, err := cc.cu.GetReplies(uint(commentID), optionalUserID(c), offset, limit)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Comment not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, replies)
+}
+
 func (cc *commentController) GetMyComments(c echo.Context) error {
 	user := c.Get("user").(*jwt.Token)
 	claims := user.Claims.(jwt.MapClaims)
@@ -73,22 +129,89 @@ func (cc *commentController) GetMyComments(c echo.Context) error {
 	return c.JSON(http.StatusOK, comments)
 }
 
+func (cc *commentController) UpdateComment(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userID := uint(claims["user_id"].(float64))
+
+	commentID, err := strconv.ParseUint(c.Param("commentId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid comment ID"})
+	}
+	comment := &model.Comment{}
+	if err := c.Bind(comment); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := cc.cu.UpdateComment(uint(commentID), userID, comment.Content)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Comment not found"})
+		case errors.Is(err, usecase.ErrForbidden):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "You are not the author of this comment"})
+		case errors.Is(err, usecase.ErrEditWindowClosed):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		case errors.Is(err, usecase.ErrContentRejected):
+			return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// モデレーター向け。編集前の本文を含めて返す
+func (cc *commentController) GetCommentHistory(c echo.Context) error {
+	commentID, err := strconv.ParseUint(c.Param("commentId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid comment ID"})
+	}
+
+	res, err := cc.cu.GetCommentHistory(uint(commentID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Comment not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
 func (cc *commentController) DeleteComment(c echo.Context) error {
 	commentID, err := strconv.ParseUint(c.Param("commentId"), 10, 32)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid comment ID"})
 	}
 
-	var userID *uint
+	// ログインしていない場合は投稿時に受け取った削除用トークンで本人か確認する
 	if user, ok := c.Get("user").(*jwt.Token); ok {
 		claims := user.Claims.(jwt.MapClaims)
-		id := uint(claims["user_id"].(float64))
-		userID = &id
+		userID := uint(claims["user_id"].(float64))
+		err = cc.cu.DeleteComment(uint(commentID), &userID)
+	} else {
+		err = cc.cu.DeleteCommentWithToken(uint(commentID), c.Request().Header.Get(middleware.HeaderDeleteToken))
 	}
-
-	if err := cc.cu.DeleteComment(uint(commentID), userID); err != nil {
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Comment not found"})
+		case errors.Is(err, usecase.ErrForbidden):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "Invalid delete token"})
+		}
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "Comment deleted successfully"})
 }
+
+// OptionalJwtMiddlewareのグループで、ログインしていない場合は0を返す
+func optionalUserID(c echo.Context) uint {
+	user, ok := c.Get("user").(*jwt.Token)
+	if !ok {
+		return 0
+	}
+	claims := user.Claims.(jwt.MapClaims)
+	return uint(claims["user_id"].(float64))
+}
@@ -3,10 +3,13 @@ package controller
 import (
 	"backend/model"
 	"backend/usecase"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type ICourseController interface {
@@ -14,6 +17,7 @@ type ICourseController interface {
 	CreateCourses(c echo.Context) error
 	UpdateCourse(c echo.Context) error
 	DeleteCourseByID(c echo.Context) error
+	MoveCourseToTerm(c echo.Context) error
 }
 
 type courseController struct {
@@ -27,21 +31,37 @@ func NewCourseController(cu usecase.ICourseUsecase) ICourseController {
 func (cc *courseController) GetAllCourses(c echo.Context) error {
 	id := c.Param("courseId")
 	planId, _ := strconv.Atoi(id)
-	postRes, err := cc.cu.GetAllCourses(uint(planId))
+	postRes, err := cc.cu.GetAllCourses(optionalUserID(c), uint(planId))
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
 		return c.JSON(500, err.Error())
 	}
 	return c.JSON(200, postRes)
 }
 
 func (cc *courseController) CreateCourses(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
 	var courses []model.Course
 	if err := c.Bind(&courses); err != nil {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
 
-	createdCourses, err := cc.cu.CreateCourses(courses)
+	createdCourses, err := cc.cu.CreateCourses(userId, courses)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan or plan term not found"})
+		}
+		if errors.Is(err, usecase.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
+		if errors.Is(err, usecase.ErrInvalidCourse) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
@@ -49,6 +69,10 @@ func (cc *courseController) CreateCourses(c echo.Context) error {
 }
 
 func (cc *courseController) UpdateCourse(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
 	id := c.Param("courseId")
 	courseId, _ := strconv.Atoi(id)
 
@@ -57,20 +81,65 @@ func (cc *courseController) UpdateCourse(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
 
-	postRes, err := cc.cu.UpdateCourse(course, courseId)
+	postRes, err := cc.cu.UpdateCourse(userId, course, courseId)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Course not found"})
+		}
+		if errors.Is(err, usecase.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
+		if errors.Is(err, usecase.ErrInvalidCourse) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
 	return c.JSON(http.StatusOK, postRes)
 }
 
 func (cc *courseController) DeleteCourseByID(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
 	id := c.Param("courseId")
 	courseId, _ := strconv.Atoi(id)
 
-	err := cc.cu.DeleteCourseByID(uint(courseId))
+	err := cc.cu.DeleteCourseByID(userId, uint(courseId))
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Course not found"})
+		}
+		if errors.Is(err, usecase.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
 	return c.JSON(http.StatusOK, map[string]string{"success": "Course deleted successfully"})
 }
+
+func (cc *courseController) MoveCourseToTerm(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	courseId, err := strconv.ParseUint(c.Param("courseId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid course ID"})
+	}
+	req := model.CourseTermMoveRequest{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := cc.cu.MoveCourseToTerm(userId, uint(courseId), req.PlanTermID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Course or plan term not found"})
+		}
+		if errors.Is(err, usecase.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
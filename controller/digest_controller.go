@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"backend/validator"
+	"errors"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+)
+
+type IDigestController interface {
+	GetSetting(c echo.Context) error
+	UpdateSetting(c echo.Context) error
+	ConfirmUnsubscribe(c echo.Context) error
+	Unsubscribe(c echo.Context) error
+}
+
+type digestController struct {
+	du usecase.IDigestUsecase
+	dv validator.IDigestValidator
+}
+
+func NewDigestController(du usecase.IDigestUsecase, dv validator.IDigestValidator) IDigestController {
+	return &digestController{du, dv}
+}
+
+func (dc *digestController) GetSetting(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	res, err := dc.du.GetSetting(userId)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (dc *digestController) UpdateSetting(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	req := model.DigestSettingRequest{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := dc.dv.DigestSettingValidate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	res, err := dc.du.UpdateSetting(userId, req)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// メール内のリンク(GET)では確認ページを返すだけにする。ログインは不要
+func (dc *digestController) ConfirmUnsubscribe(c echo.Context) error {
+	page, err := dc.du.GetUnsubscribePage(c.QueryParam("token"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidUnsubscribeToken) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid unsubscribe token"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.HTMLBlob(http.StatusOK, page)
+}
+
+// 確認ページのボタンとワンクリック配信停止(RFC 8058のPOST)で配信を止める。ログインは不要
+func (dc *digestController) Unsubscribe(c echo.Context) error {
+	if err := dc.du.Unsubscribe(c.QueryParam("token")); err != nil {
+		if errors.Is(err, usecase.ErrInvalidUnsubscribeToken) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid unsubscribe token"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.String(http.StatusOK, "ダイジェストメールの配信を停止しました / You have been unsubscribed from digest emails.")
+}
@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IFavoriteController interface {
+	SaveFavorite(c echo.Context) error
+	DeleteFavorite(c echo.Context) error
+	GetCollections(c echo.Context) error
+	CreateCollection(c echo.Context) error
+	UpdateCollection(c echo.Context) error
+	DeleteCollectionByID(c echo.Context) error
+	GetCollectionPlans(c echo.Context) error
+	AddPlanToCollection(c echo.Context) error
+	RemovePlanFromCollection(c echo.Context) error
+}
+
+type favoriteController struct {
+	fu usecase.IFavoriteUsecase
+}
+
+func NewFavoriteController(fu usecase.IFavoriteUsecase) IFavoriteController {
+	return &favoriteController{fu}
+}
+
+// リクエストボディ(任意): {"note": "..."}
+func (fc *favoriteController) SaveFavorite(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	req := model.FavoritePlanRequest{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := fc.fu.SaveFavorite(userId, uint(planId), req.Note)
+	if err != nil {
+		return favoriteErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (fc *favoriteController) DeleteFavorite(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	if err := fc.fu.DeleteFavorite(userId, uint(planId)); err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (fc *favoriteController) GetCollections(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	res, err := fc.fu.GetCollections(userId)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (fc *favoriteController) CreateCollection(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	collection := model.FavoriteCollection{}
+	if err := c.Bind(&collection); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := fc.fu.CreateCollection(userId, &collection)
+	if err != nil {
+		return favoriteErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (fc *favoriteController) UpdateCollection(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	collectionId, err := strconv.ParseUint(c.Param("collectionId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid collection ID"})
+	}
+	collection := model.FavoriteCollection{}
+	if err := c.Bind(&collection); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := fc.fu.UpdateCollection(userId, uint(collectionId), &collection)
+	if err != nil {
+		return favoriteErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (fc *favoriteController) DeleteCollectionByID(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	collectionId, err := strconv.ParseUint(c.Param("collectionId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid collection ID"})
+	}
+	if err := fc.fu.DeleteCollectionByID(userId, uint(collectionId)); err != nil {
+		return favoriteErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// クエリ: offset, limit
+func (fc *favoriteController) GetCollectionPlans(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	collectionId, err := strconv.ParseUint(c.Param("collectionId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid collection ID"})
+	}
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+	res, err := fc.fu.GetCollectionPlans(userId, uint(collectionId), offset, limit)
+	if err != nil {
+		return favoriteErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (fc *favoriteController) AddPlanToCollection(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	collectionId, err := strconv.ParseUint(c.Param("collectionId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid collection ID"})
+	}
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	res, err := fc.fu.AddPlanToCollection(userId, uint(collectionId), uint(planId))
+	if err != nil {
+		return favoriteErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (fc *favoriteController) RemovePlanFromCollection(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	collectionId, err := strconv.ParseUint(c.Param("collectionId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid collection ID"})
+	}
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	if err := fc.fu.RemovePlanFromCollection(userId, uint(collectionId), uint(planId)); err != nil {
+		return favoriteErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func favoriteErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan or collection not found"})
+	case errors.Is(err, usecase.ErrForbidden):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "You are not the owner of this collection"})
+	case errors.Is(err, usecase.ErrAlreadyExists):
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Collection name already exists"})
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+}
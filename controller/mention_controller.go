@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"backend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+)
+
+type IMentionController interface {
+	GetMyMentions(c echo.Context) error
+}
+
+type mentionController struct {
+	mu usecase.IMentionUsecase
+}
+
+func NewMentionController(mu usecase.IMentionUsecase) IMentionController {
+	return &mentionController{mu}
+}
+
+func (mc *mentionController) GetMyMentions(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit == 0 {
+		limit = 20
+	}
+	res, err := mc.mu.GetMyMentions(userId, offset, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,232 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IModerationController interface {
+	ReportComment(c echo.Context) error
+	ReportReview(c echo.Context) error
+	GetQueue(c echo.Context) error
+	HideComment(c echo.Context) error
+	RestoreComment(c echo.Context) error
+	DeleteComment(c echo.Context) error
+	HideReview(c echo.Context) error
+	RestoreReview(c echo.Context) error
+	DeleteReview(c echo.Context) error
+	GetActions(c echo.Context) error
+	GetNGWords(c echo.Context) error
+	CreateNGWord(c echo.Context) error
+	DeleteNGWordByID(c echo.Context) error
+}
+
+type moderationController struct {
+	mu usecase.IModerationUsecase
+}
+
+func NewModerationController(mu usecase.IModerationUsecase) IModerationController {
+	return &moderationController{mu}
+}
+
+// 未ログインでも通報できる
+func (mc *moderationController) ReportComment(c echo.Context) error {
+	commentId, err := strconv.ParseUint(c.Param("commentId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid comment ID"})
+	}
+	req := model.ReportRequest{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	var userId *uint
+	if user, ok := c.Get("user").(*jwt.Token); ok {
+		claims := user.Claims.(jwt.MapClaims)
+		id := uint(claims["user_id"].(float64))
+		userId = &id
+	}
+
+	res, err := mc.mu.ReportComment(uint(commentId), userId, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Comment not found"})
+		case errors.Is(err, usecase.ErrAlreadyExists):
+			return c.JSON(http.StatusConflict, map[string]string{"error": "You have already reported this comment"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (mc *moderationController) ReportReview(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	reviewId, err := strconv.ParseUint(c.Param("reviewId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid review ID"})
+	}
+	req := model.ReportRequest{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := mc.mu.ReportReview(uint(reviewId), userId, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Review not found"})
+		case errors.Is(err, usecase.ErrAlreadyExists):
+			return c.JSON(http.StatusConflict, map[string]string{"error": "You have already reported this review"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// クエリ: offset, limit
+func (mc *moderationController) GetQueue(c echo.Context) error {
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit == 0 {
+		limit = 20
+	}
+	res, err := mc.mu.GetQueue(offset, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (mc *moderationController) HideComment(c echo.Context) error {
+	return mc.applyAction(c, "commentId", "Comment", mc.mu.HideComment)
+}
+
+func (mc *moderationController) RestoreComment(c echo.Context) error {
+	return mc.applyAction(c, "commentId", "Comment", mc.mu.RestoreComment)
+}
+
+func (mc *moderationController) DeleteComment(c echo.Context) error {
+	return mc.applyAction(c, "commentId", "Comment", mc.mu.DeleteComment)
+}
+
+func (mc *moderationController) HideReview(c echo.Context) error {
+	return mc.applyAction(c, "reviewId", "Review", mc.mu.HideReview)
+}
+
+func (mc *moderationController) RestoreReview(c echo.Context) error {
+	return mc.applyAction(c, "reviewId", "Review", mc.mu.RestoreReview)
+}
+
+func (mc *moderationController) DeleteReview(c echo.Context) error {
+	return mc.applyAction(c, "reviewId", "Review", mc.mu.DeleteReview)
+}
+
+// クエリ: comment_id, review_id, offset, limit
+func (mc *moderationController) GetActions(c echo.Context) error {
+	var commentId *uint
+	if v := c.QueryParam("comment_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid comment ID"})
+		}
+		cid := uint(id)
+		commentId = &cid
+	}
+	var reviewId *uint
+	if v := c.QueryParam("review_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid review ID"})
+		}
+		rid := uint(id)
+		reviewId = &rid
+	}
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit == 0 {
+		limit = 20
+	}
+	res, err := mc.mu.GetActions(commentId, reviewId, offset, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (mc *moderationController) GetNGWords(c echo.Context) error {
+	res, err := mc.mu.GetNGWords()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (mc *moderationController) CreateNGWord(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	word := model.NGWord{}
+	if err := c.Bind(&word); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := mc.mu.CreateNGWord(userId, word.Word)
+	if err != nil {
+		if errors.Is(err, usecase.ErrAlreadyExists) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "NG word already exists"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (mc *moderationController) DeleteNGWordByID(c echo.Context) error {
+	ngWordId, err := strconv.ParseUint(c.Param("ngWordId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid NG word ID"})
+	}
+	if err := mc.mu.DeleteNGWordByID(uint(ngWordId)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "NG word not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// paramは対象のIDのパスパラメーター名、targetはエラーメッセージに使う対象の名前。
+// リクエストボディ(任意): {"reason": "..."}
+func (mc *moderationController) applyAction(c echo.Context, param string, target string, apply func(moderatorId uint, targetId uint, reason *string) (model.ModerationActionResponse, error)) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	moderatorId := uint(claims["user_id"].(float64))
+
+	targetId, err := strconv.ParseUint(c.Param(param), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid " + strings.ToLower(target) + " ID"})
+	}
+	req := model.ModerationActionRequest{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := apply(moderatorId, uint(targetId), req.Reason)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": target + " not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
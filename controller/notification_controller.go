@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type INotificationController interface {
+	GetNotifications(c echo.Context) error
+	MarkRead(c echo.Context) error
+	MarkAllRead(c echo.Context) error
+	GetPreferences(c echo.Context) error
+	UpdatePreferences(c echo.Context) error
+}
+
+type notificationController struct {
+	nu usecase.INotificationUsecase
+}
+
+func NewNotificationController(nu usecase.INotificationUsecase) INotificationController {
+	return &notificationController{nu}
+}
+
+// クエリ: unread(trueの場合は未読のみ), offset, limit
+func (nc *notificationController) GetNotifications(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	unreadOnly, _ := strconv.ParseBool(c.QueryParam("unread"))
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit == 0 {
+		limit = 20
+	}
+	res, err := nc.nu.GetNotifications(userId, unreadOnly, offset, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (nc *notificationController) MarkRead(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	notificationId, err := strconv.ParseUint(c.Param("notificationId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid notification ID"})
+	}
+	if err := nc.nu.MarkRead(userId, uint(notificationId)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Notification not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (nc *notificationController) MarkAllRead(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	if err := nc.nu.MarkAllRead(userId); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (nc *notificationController) GetPreferences(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	res, err := nc.nu.GetPreferences(userId)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// リクエストボディ: [{"type": "favorite", "enabled": false}, ...]。含まれない種類は変更しない
+func (nc *notificationController) UpdatePreferences(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	preferences := []model.NotificationPreference{}
+	if err := c.Bind(&preferences); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	res, err := nc.nu.UpdatePreferences(userId, preferences)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUnknownNotificationType) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
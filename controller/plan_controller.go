@@ -3,11 +3,17 @@ package controller
 import (
 	"backend/model"
 	"backend/usecase"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type IPlanController interface {
@@ -18,23 +24,33 @@ type IPlanController interface {
 	DeletePlanByID(c echo.Context) error
 	ToggleFavoritePlan(c echo.Context) error
 	GetFavoriteCount(c echo.Context) error
+	ExportPlan(c echo.Context) error
+	ComparePlans(c echo.Context) error
+	ImportPlan(c echo.Context) error
+	ForkPlan(c echo.Context) error
+	GetMyFavoritePlans(c echo.Context) error
 }
 
 type planController struct {
-	pu usecase.IPlanUsecase
+	pu  usecase.IPlanUsecase
+	ptu usecase.IPlanTransferUsecase
 }
 
-func NewPlanController(pu usecase.IPlanUsecase) IPlanController {
-	return &planController{pu}
+func NewPlanController(pu usecase.IPlanUsecase, ptu usecase.IPlanTransferUsecase) IPlanController {
+	return &planController{pu, ptu}
 }
 
 func (pc *planController) GetAllPlans(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
 	offset, _ := strconv.Atoi(c.QueryParam("offset"))
 	limit, _ := strconv.Atoi(c.QueryParam("limit"))
 	if limit == 0 {
 		limit = 10 // デフォルトのリミットをは10に設定
 	}
-	postRes, err := pc.pu.GetAllPlans(offset, limit)
+	postRes, err := pc.pu.GetAllPlans(userId, offset, limit)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
@@ -42,10 +58,17 @@ func (pc *planController) GetAllPlans(c echo.Context) error {
 }
 
 func (pc *planController) GetPlansByID(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
 	id := c.Param("planId")
 	planId, _ := strconv.Atoi(id)
-	postRes, err := pc.pu.GetPlanByID(uint(planId))
+	postRes, err := pc.pu.GetPlanByID(userId, uint(planId))
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
 	return c.JSON(http.StatusOK, postRes)
@@ -61,6 +84,7 @@ func (pc *planController) CreatePlan(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
 	plan.UserID = userId
+	plan.ForkedFromID = nil
 	planRes, err := pc.pu.CreatePlan(plan)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
@@ -80,19 +104,70 @@ func (pc *planController) UpdatePlan(c echo.Context) error {
 	if err := c.Bind(&plan); err != nil {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
-	plan.UserID = userId
-	planRes, err := pc.pu.UpdatePlan(plan, uint(planId))
+	planRes, err := pc.pu.UpdatePlan(userId, plan, uint(planId))
 	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		case errors.Is(err, usecase.ErrForbidden):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "You can only update your own plans"})
+		}
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 	return c.JSON(http.StatusOK, planRes)
 }
 
+func (pc *planController) GetMyFavoritePlans(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+	res, err := pc.pu.GetMyFavoritePlans(userId, offset, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (pc *planController) ForkPlan(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	planRes, err := pc.pu.ForkPlan(userId, uint(planId))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusCreated, planRes)
+}
+
 func (pc *planController) DeletePlanByID(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
 	id := c.Param("planId")
 	planId, _ := strconv.Atoi(id)
-	err := pc.pu.DeletePlanByID(uint(planId))
+	err := pc.pu.DeletePlanByID(userId, uint(planId))
 	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		case errors.Is(err, usecase.ErrForbidden):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "You can only delete your own plans"})
+		}
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
 	return c.JSON(http.StatusOK, "Plan Deleted")
@@ -110,6 +185,9 @@ func (pc *planController) ToggleFavoritePlan(c echo.Context) error {
 	}
 
 	if err := pc.pu.ToggleFavoritePlan(userId, uint(planId)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
@@ -117,14 +195,21 @@ func (pc *planController) ToggleFavoritePlan(c echo.Context) error {
 }
 
 func (pc *planController) GetFavoriteCount(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
 	id := c.Param("planId")
 	planId, err := strconv.ParseUint(id, 10, 32)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
 	}
 
-	count, err := pc.pu.GetFavoriteCount(uint(planId))
+	count, err := pc.pu.GetFavoriteCount(userId, uint(planId))
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
@@ -133,3 +218,119 @@ func (pc *planController) GetFavoriteCount(c echo.Context) error {
 		"favorite_count": count,
 	})
 }
+
+// クエリ: format (json, csv, xlsx)。省略時はjson
+func (pc *planController) ExportPlan(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+
+	format := strings.ToLower(c.QueryParam("format"))
+	var body []byte
+	var contentType string
+	switch format {
+	case "", "json":
+		res, err := pc.ptu.ExportPlan(userId, uint(planId))
+		if err != nil {
+			return planExportErrorResponse(c, err)
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"plan-%d.json\"", planId))
+		return c.JSON(http.StatusOK, res)
+	case "csv":
+		contentType = "text/csv; charset=utf-8"
+		body, err = pc.ptu.ExportPlanCSV(userId, uint(planId))
+	case "xlsx":
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		body, err = pc.ptu.ExportPlanXLSX(userId, uint(planId))
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "format must be one of json, csv, xlsx"})
+	}
+	if err != nil {
+		return planExportErrorResponse(c, err)
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"plan-%d.%s\"", planId, format))
+	return c.Blob(http.StatusOK, contentType, body)
+}
+
+// JSONは本文にそのまま、CSVはmultipartのfileで受け取る。
+// multipartの場合は format, title, content を指定でき、formatを省略した場合はファイルの拡張子で判定する
+func (pc *planController) ImportPlan(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	var r io.Reader
+	options := model.PlanImportOptions{Format: "json"}
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "File is required"})
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		defer file.Close()
+		r = file
+
+		options.Format = c.FormValue("format")
+		if options.Format == "" {
+			options.Format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+		}
+		options.Title = c.FormValue("title")
+		if v := c.FormValue("content"); v != "" {
+			options.Content = &v
+		}
+	} else {
+		r = c.Request().Body
+	}
+
+	res, err := pc.ptu.ImportPlan(userId, r, options)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if !res.Valid {
+		return c.JSON(http.StatusUnprocessableEntity, res)
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+func planExportErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+	}
+	return c.JSON(http.StatusInternalServerError, err.Error())
+}
+
+// クエリ: ids (カンマ区切りの計画ID、2〜3件)
+func (pc *planController) ComparePlans(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planIds := []uint{}
+	for _, v := range strings.Split(c.QueryParam("ids"), ",") {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		planId, err := strconv.ParseUint(strings.TrimSpace(v), 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+		}
+		planIds = append(planIds, uint(planId))
+	}
+
+	res, err := pc.pu.ComparePlans(userId, planIds)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IPlanEventController interface {
+	StreamPlanEvents(c echo.Context) error
+}
+
+type planEventController struct {
+	peu usecase.IPlanEventUsecase
+}
+
+func NewPlanEventController(peu usecase.IPlanEventUsecase) IPlanEventController {
+	return &planEventController{peu}
+}
+
+// プロキシに接続を切られないよう、イベントがなくても送るコメント行の間隔
+const sseKeepAliveInterval = 25 * time.Second
+
+// Server-Sent Eventsで計画のイベントを配信する。
+// 再接続時はLast-Event-IDヘッダー(またはクエリのlast_event_id)以降のイベントから送る
+func (pec *planEventController) StreamPlanEvents(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	lastEventId := c.Request().Header.Get("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = c.QueryParam("last_event_id")
+	}
+	var after uint64
+	if lastEventId != "" {
+		if after, err = strconv.ParseUint(lastEventId, 10, 32); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid Last-Event-ID"})
+		}
+	}
+
+	sub, err := pec.peu.Subscribe(userId, uint(planId), uint(after))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	defer sub.Close()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprint(res, "retry: 3000\n\n"); err != nil {
+		return nil
+	}
+
+	sent := uint(after)
+	for _, planEvent := range sub.Replay {
+		if err := writePlanEvent(res, planEvent); err != nil {
+			return nil
+		}
+		sent = planEvent.ID
+	}
+	res.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case planEvent, ok := <-sub.Events:
+			if !ok {
+				return nil
+			}
+			// 購読してから送り直し分を読み込むまでに届いたイベントは送信済み。
+			// 同じ計画のイベントIDはコミット順に採番されるため、IDの比較で判定できる
+			if planEvent.ID <= sent {
+				continue
+			}
+			if err := writePlanEvent(res, planEvent); err != nil {
+				return nil
+			}
+			sent = planEvent.ID
+			res.Flush()
+		}
+	}
+}
+
+func writePlanEvent(res *echo.Response, planEvent model.PlanEvent) error {
+	_, err := fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", planEvent.ID, planEvent.Type, planEvent.Data)
+	return err
+}
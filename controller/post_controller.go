@@ -3,11 +3,13 @@ package controller
 import (
 	"backend/model"
 	"backend/usecase"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type IPostController interface {
@@ -37,10 +39,17 @@ func (pc *postController) GetAllPosts(c echo.Context) error {
 }
 
 func (pc *postController) GetPostByID(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
 	id := c.Param("planId")
 	planId, _ := strconv.Atoi(id)
-	postRes, err := pc.pu.GetPostByID(uint(planId))
+	postRes, err := pc.pu.GetPostByID(uint(planId), userId)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
 	return c.JSON(http.StatusOK, postRes)
@@ -58,6 +67,9 @@ func (pc *postController) CreatePost(c echo.Context) error {
 	post.AuthorID = uint(userId.(float64))
 	postRes, err := pc.pu.CreatePost(post)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 	return c.JSON(http.StatusCreated, postRes)
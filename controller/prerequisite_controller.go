@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IPrerequisiteController interface {
+	GetPrerequisiteGraph(c echo.Context) error
+	GetPrerequisitesByCourseID(c echo.Context) error
+	CreatePrerequisite(c echo.Context) error
+	DeletePrerequisiteByID(c echo.Context) error
+	CheckPlan(c echo.Context) error
+}
+
+type prerequisiteController struct {
+	pu usecase.IPrerequisiteUsecase
+}
+
+func NewPrerequisiteController(pu usecase.IPrerequisiteUsecase) IPrerequisiteController {
+	return &prerequisiteController{pu}
+}
+
+func (pc *prerequisiteController) GetPrerequisiteGraph(c echo.Context) error {
+	universityId, err := strconv.ParseUint(c.QueryParam("university_id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid university ID"})
+	}
+	res, err := pc.pu.GetPrerequisiteGraph(uint(universityId))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (pc *prerequisiteController) GetPrerequisitesByCourseID(c echo.Context) error {
+	catalogCourseId, err := strconv.ParseUint(c.Param("catalogCourseId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid catalog course ID"})
+	}
+	res, err := pc.pu.GetPrerequisitesByCourseID(uint(catalogCourseId))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (pc *prerequisiteController) CreatePrerequisite(c echo.Context) error {
+	prerequisite := &model.CoursePrerequisite{}
+	if err := c.Bind(prerequisite); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := pc.pu.CreatePrerequisite(prerequisite)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (pc *prerequisiteController) DeletePrerequisiteByID(c echo.Context) error {
+	prerequisiteId, err := strconv.ParseUint(c.Param("prerequisiteId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid prerequisite ID"})
+	}
+	if err := pc.pu.DeletePrerequisiteByID(uint(prerequisiteId)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Prerequisite not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (pc *prerequisiteController) CheckPlan(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	res, err := pc.pu.CheckPlan(userId, uint(planId))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
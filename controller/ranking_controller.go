@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"backend/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+)
+
+type IRankingController interface {
+	GetTrendingPlans(c echo.Context) error
+}
+
+type rankingController struct {
+	ru usecase.IRankingUsecase
+}
+
+func NewRankingController(ru usecase.IRankingUsecase) IRankingController {
+	return &rankingController{ru}
+}
+
+// クエリ: university_id, department_id, offset, limit
+func (rc *rankingController) GetTrendingPlans(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	var universityId, departmentId *uint
+	if v := c.QueryParam("university_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid university ID"})
+		}
+		u := uint(id)
+		universityId = &u
+	}
+	if v := c.QueryParam("department_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid department ID"})
+		}
+		d := uint(id)
+		departmentId = &d
+	}
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+	res, err := rc.ru.GetTrendingPlans(userId, universityId, departmentId, offset, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
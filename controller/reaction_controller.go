@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IReactionController interface {
+	AddCommentReaction(c echo.Context) error
+	RemoveCommentReaction(c echo.Context) error
+	AddPostReaction(c echo.Context) error
+	RemovePostReaction(c echo.Context) error
+}
+
+type reactionController struct {
+	ru usecase.IReactionUsecase
+}
+
+func NewReactionController(ru usecase.IReactionUsecase) IReactionController {
+	return &reactionController{ru}
+}
+
+// 追加・取り消しはどちらも何度呼んでも同じ結果になり、対象の最新の集計を返す
+func (rc *reactionController) AddCommentReaction(c echo.Context) error {
+	return rc.handle(c, "commentId", rc.ru.AddCommentReaction)
+}
+
+func (rc *reactionController) RemoveCommentReaction(c echo.Context) error {
+	return rc.handle(c, "commentId", rc.ru.RemoveCommentReaction)
+}
+
+func (rc *reactionController) AddPostReaction(c echo.Context) error {
+	return rc.handle(c, "postId", rc.ru.AddPostReaction)
+}
+
+func (rc *reactionController) RemovePostReaction(c echo.Context) error {
+	return rc.handle(c, "postId", rc.ru.RemovePostReaction)
+}
+
+func (rc *reactionController) handle(c echo.Context, idParam string, apply func(userId uint, targetId uint, emoji string) ([]model.ReactionSummary, error)) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	targetId, err := strconv.ParseUint(c.Param(idParam), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID"})
+	}
+	emoji, err := url.PathUnescape(c.Param("emoji"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid emoji"})
+	}
+
+	res, err := apply(userId, uint(targetId), emoji)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Not found"})
+		case errors.Is(err, usecase.ErrForbidden):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		case errors.Is(err, usecase.ErrUnknownReaction):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
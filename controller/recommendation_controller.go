@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"backend/usecase"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IRecommendationController interface {
+	GetPlanRecommendations(c echo.Context) error
+	GetCourseRecommendations(c echo.Context) error
+}
+
+type recommendationController struct {
+	ru usecase.IRecommendationUsecase
+}
+
+func NewRecommendationController(ru usecase.IRecommendationUsecase) IRecommendationController {
+	return &recommendationController{ru}
+}
+
+// クエリ: limit
+func (rc *recommendationController) GetPlanRecommendations(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	res, err := rc.ru.GetPlanRecommendations(userId, uint(planId), recommendationLimit(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// クエリ: department_id (省略時は自分の学科), limit
+func (rc *recommendationController) GetCourseRecommendations(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	catalogCourseId, err := strconv.ParseUint(c.Param("catalogCourseId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid catalog course ID"})
+	}
+	var departmentId *uint
+	if v := c.QueryParam("department_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid department ID"})
+		}
+		d := uint(id)
+		departmentId = &d
+	}
+	res, err := rc.ru.GetCourseRecommendations(userId, uint(catalogCourseId), departmentId, recommendationLimit(c))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func recommendationLimit(c echo.Context) int {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+	return limit
+}
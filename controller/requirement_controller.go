@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IRequirementController interface {
+	GetRequirementSets(c echo.Context) error
+	GetRequirementSetByID(c echo.Context) error
+	CreateRequirementSet(c echo.Context) error
+	UpdateRequirementSet(c echo.Context) error
+	DeleteRequirementSetByID(c echo.Context) error
+	EvaluatePlan(c echo.Context) error
+}
+
+type requirementController struct {
+	ru usecase.IRequirementUsecase
+}
+
+func NewRequirementController(ru usecase.IRequirementUsecase) IRequirementController {
+	return &requirementController{ru}
+}
+
+func (rc *requirementController) GetRequirementSets(c echo.Context) error {
+	departmentId, _ := strconv.Atoi(c.QueryParam("department_id"))
+	res, err := rc.ru.GetRequirementSets(uint(departmentId))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (rc *requirementController) GetRequirementSetByID(c echo.Context) error {
+	requirementId, err := strconv.ParseUint(c.Param("requirementId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid requirement ID"})
+	}
+	res, err := rc.ru.GetRequirementSetByID(uint(requirementId))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Requirement not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (rc *requirementController) CreateRequirementSet(c echo.Context) error {
+	set := &model.RequirementSet{}
+	if err := c.Bind(set); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := rc.ru.CreateRequirementSet(set)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (rc *requirementController) UpdateRequirementSet(c echo.Context) error {
+	requirementId, err := strconv.ParseUint(c.Param("requirementId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid requirement ID"})
+	}
+	set := &model.RequirementSet{}
+	if err := c.Bind(set); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := rc.ru.UpdateRequirementSet(set, uint(requirementId))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Requirement not found"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (rc *requirementController) DeleteRequirementSetByID(c echo.Context) error {
+	requirementId, err := strconv.ParseUint(c.Param("requirementId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid requirement ID"})
+	}
+	if err := rc.ru.DeleteRequirementSetByID(uint(requirementId)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Requirement not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// クエリで学科・入学年度を指定すると計画作成者のプロフィールより優先される
+func (rc *requirementController) EvaluatePlan(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+
+	var departmentId, entryYear *uint
+	if v := c.QueryParam("department_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid department ID"})
+		}
+		d := uint(id)
+		departmentId = &d
+	}
+	if v := c.QueryParam("entry_year"); v != "" {
+		year, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid entry year"})
+		}
+		y := uint(year)
+		entryYear = &y
+	}
+
+	res, err := rc.ru.EvaluatePlan(userId, uint(planId), departmentId, entryYear)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan or applicable requirement not found"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IReviewController interface {
+	GetReviewsByCatalogCourseID(c echo.Context) error
+	GetReviewSummary(c echo.Context) error
+	GetMyReviews(c echo.Context) error
+	CreateReview(c echo.Context) error
+	UpdateReview(c echo.Context) error
+	DeleteReviewByID(c echo.Context) error
+}
+
+type reviewController struct {
+	ru usecase.IReviewUsecase
+}
+
+func NewReviewController(ru usecase.IReviewUsecase) IReviewController {
+	return &reviewController{ru}
+}
+
+// クエリ: sort (newest, oldest, rating_desc, rating_asc, difficulty_desc, difficulty_asc, workload_desc, workload_asc), offset, limit
+func (rc *reviewController) GetReviewsByCatalogCourseID(c echo.Context) error {
+	catalogCourseId, err := strconv.ParseUint(c.Param("catalogCourseId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid catalog course ID"})
+	}
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit == 0 {
+		limit = 20
+	}
+	res, err := rc.ru.GetReviewsByCatalogCourseID(uint(catalogCourseId), c.QueryParam("sort"), offset, limit)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (rc *reviewController) GetReviewSummary(c echo.Context) error {
+	catalogCourseId, err := strconv.ParseUint(c.Param("catalogCourseId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid catalog course ID"})
+	}
+	res, err := rc.ru.GetReviewSummary(uint(catalogCourseId))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (rc *reviewController) GetMyReviews(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	res, err := rc.ru.GetReviewsByUserID(userId)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (rc *reviewController) CreateReview(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	catalogCourseId, err := strconv.ParseUint(c.Param("catalogCourseId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid catalog course ID"})
+	}
+	review := &model.CourseReview{}
+	if err := c.Bind(review); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	review.CatalogCourseID = uint(catalogCourseId)
+	review.UserID = userId
+
+	res, err := rc.ru.CreateReview(review)
+	if err != nil {
+		return reviewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (rc *reviewController) UpdateReview(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	reviewId, err := strconv.ParseUint(c.Param("reviewId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid review ID"})
+	}
+	review := &model.CourseReview{}
+	if err := c.Bind(review); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := rc.ru.UpdateReview(userId, uint(reviewId), review)
+	if err != nil {
+		return reviewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (rc *reviewController) DeleteReviewByID(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	reviewId, err := strconv.ParseUint(c.Param("reviewId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid review ID"})
+	}
+	if err := rc.ru.DeleteReviewByID(userId, uint(reviewId)); err != nil {
+		return reviewErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func reviewErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Review or catalog course not found"})
+	case errors.Is(err, usecase.ErrForbidden):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "You are not the author of this review"})
+	case errors.Is(err, usecase.ErrAlreadyExists):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrContentRejected):
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+}
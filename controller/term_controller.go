@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ITermController interface {
+	GetAcademicTerms(c echo.Context) error
+	CreateAcademicTerm(c echo.Context) error
+	UpdateAcademicTerm(c echo.Context) error
+	DeleteAcademicTermByID(c echo.Context) error
+	GetPlanTerms(c echo.Context) error
+	CreatePlanTerms(c echo.Context) error
+	UpdatePlanTerm(c echo.Context) error
+	DeletePlanTermByID(c echo.Context) error
+}
+
+type termController struct {
+	tu usecase.ITermUsecase
+}
+
+func NewTermController(tu usecase.ITermUsecase) ITermController {
+	return &termController{tu}
+}
+
+func (tc *termController) GetAcademicTerms(c echo.Context) error {
+	universityId, err := strconv.ParseUint(c.QueryParam("university_id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid university ID"})
+	}
+	academicYear, _ := strconv.Atoi(c.QueryParam("academic_year"))
+	res, err := tc.tu.GetAcademicTerms(uint(universityId), uint(academicYear))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (tc *termController) CreateAcademicTerm(c echo.Context) error {
+	term := &model.AcademicTerm{}
+	if err := c.Bind(term); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := tc.tu.CreateAcademicTerm(term)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (tc *termController) UpdateAcademicTerm(c echo.Context) error {
+	academicTermId, err := strconv.ParseUint(c.Param("academicTermId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid academic term ID"})
+	}
+	term := &model.AcademicTerm{}
+	if err := c.Bind(term); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := tc.tu.UpdateAcademicTerm(term, uint(academicTermId))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Academic term not found"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (tc *termController) DeleteAcademicTermByID(c echo.Context) error {
+	academicTermId, err := strconv.ParseUint(c.Param("academicTermId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid academic term ID"})
+	}
+	if err := tc.tu.DeleteAcademicTermByID(uint(academicTermId)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Academic term not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (tc *termController) GetPlanTerms(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	res, err := tc.tu.GetPlanTerms(userId, uint(planId))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (tc *termController) CreatePlanTerms(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	var terms []model.PlanTerm
+	if err := c.Bind(&terms); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := tc.tu.CreatePlanTerms(userId, uint(planId), terms)
+	if err != nil {
+		return planTermErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (tc *termController) UpdatePlanTerm(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	planTermId, err := strconv.ParseUint(c.Param("planTermId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan term ID"})
+	}
+	term := &model.PlanTerm{}
+	if err := c.Bind(term); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	res, err := tc.tu.UpdatePlanTerm(userId, uint(planId), uint(planTermId), term)
+	if err != nil {
+		return planTermErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (tc *termController) DeletePlanTermByID(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	planTermId, err := strconv.ParseUint(c.Param("planTermId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan term ID"})
+	}
+	if err := tc.tu.DeletePlanTermByID(userId, uint(planId), uint(planTermId)); err != nil {
+		return planTermErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func planTermErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan or plan term not found"})
+	case errors.Is(err, usecase.ErrForbidden):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "You are not the owner of this plan"})
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+}
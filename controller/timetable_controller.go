@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"backend/usecase"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ITimetableController interface {
+	GetTimetable(c echo.Context) error
+}
+
+type timetableController struct {
+	tu usecase.ITimetableUsecase
+}
+
+func NewTimetableController(tu usecase.ITimetableUsecase) ITimetableController {
+	return &timetableController{tu}
+}
+
+// クエリ: format (html, svg, png)、plan_term_id (省略時は計画の全科目)
+func (tc *timetableController) GetTimetable(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	planId, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan ID"})
+	}
+	var planTermId *uint
+	if v := c.QueryParam("plan_term_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid plan term ID"})
+		}
+		termId := uint(id)
+		planTermId = &termId
+	}
+
+	var body []byte
+	var contentType string
+	switch c.QueryParam("format") {
+	case "", "html":
+		contentType = echo.MIMETextHTMLCharsetUTF8
+		body, err = tc.tu.GetTimetableHTML(userId, uint(planId), planTermId)
+	case "svg":
+		contentType = "image/svg+xml"
+		body, err = tc.tu.GetTimetableSVG(userId, uint(planId), planTermId)
+	case "png":
+		contentType = "image/png"
+		body, err = tc.tu.GetTimetablePNG(userId, uint(planId), planTermId)
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "format must be one of html, svg, png"})
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Plan or plan term not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+	return c.Blob(http.StatusOK, contentType, body)
+}
@@ -3,10 +3,12 @@ package controller
 import (
 	"backend/model"
 	"backend/usecase"
+	"errors"
 	"net/http"
 	"os"
 	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/labstack/echo/v4"
 )
 
@@ -17,6 +19,7 @@ type IUserController interface {
 	CsrfToken(c echo.Context) error
 	GoogleLogin(c echo.Context) error
 	GoogleCallback(c echo.Context) error
+	UpdateHandle(c echo.Context) error
 }
 type userController struct {
 	uu usecase.IUserUsecase
@@ -112,3 +115,22 @@ func (uc *userController) GoogleCallback(c echo.Context) error {
 
 	return c.Redirect(http.StatusTemporaryRedirect, os.Getenv("FE_URL"))
 }
+
+func (uc *userController) UpdateHandle(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	req := model.UserHandleRequest{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	userRes, err := uc.uu.UpdateHandle(userId, req.Handle)
+	if err != nil {
+		if errors.Is(err, usecase.ErrAlreadyExists) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, userRes)
+}
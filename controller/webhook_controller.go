@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IWebhookController interface {
+	GetWebhooks(c echo.Context) error
+	CreateWebhook(c echo.Context) error
+	UpdateWebhook(c echo.Context) error
+	DeleteWebhook(c echo.Context) error
+	GetDeliveries(c echo.Context) error
+	Redeliver(c echo.Context) error
+}
+
+type webhookController struct {
+	wu usecase.IWebhookUsecase
+}
+
+func NewWebhookController(wu usecase.IWebhookUsecase) IWebhookController {
+	return &webhookController{wu}
+}
+
+func (wc *webhookController) GetWebhooks(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	res, err := wc.wu.GetWebhooks(userId)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// レスポンスのsecretで受信側が署名を検証する
+func (wc *webhookController) CreateWebhook(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+	role, _ := claims["role"].(string)
+
+	req := model.WebhookRequest{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	res, err := wc.wu.CreateWebhook(userId, role == model.RoleAdmin, req)
+	if err != nil {
+		return webhookError(c, err)
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (wc *webhookController) UpdateWebhook(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+	role, _ := claims["role"].(string)
+
+	webhookId, err := strconv.ParseUint(c.Param("webhookId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid webhook ID"})
+	}
+	req := model.WebhookRequest{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	res, err := wc.wu.UpdateWebhook(userId, role == model.RoleAdmin, uint(webhookId), req)
+	if err != nil {
+		return webhookError(c, err)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (wc *webhookController) DeleteWebhook(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	webhookId, err := strconv.ParseUint(c.Param("webhookId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid webhook ID"})
+	}
+	if err := wc.wu.DeleteWebhook(userId, uint(webhookId)); err != nil {
+		return webhookError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+const maxDeliveryPageSize = 100
+
+// クエリ: offset, limit
+func (wc *webhookController) GetDeliveries(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	webhookId, err := strconv.ParseUint(c.Param("webhookId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid webhook ID"})
+	}
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if offset < 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid offset"})
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > maxDeliveryPageSize {
+		limit = maxDeliveryPageSize
+	}
+	res, err := wc.wu.GetDeliveries(userId, uint(webhookId), offset, limit)
+	if err != nil {
+		return webhookError(c, err)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// 送信は非同期で行うため、登録した時点で202を返す
+func (wc *webhookController) Redeliver(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := uint(claims["user_id"].(float64))
+
+	webhookId, err := strconv.ParseUint(c.Param("webhookId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid webhook ID"})
+	}
+	deliveryId, err := strconv.ParseUint(c.Param("deliveryId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid delivery ID"})
+	}
+	res, err := wc.wu.Redeliver(userId, uint(webhookId), uint(deliveryId))
+	if err != nil {
+		return webhookError(c, err)
+	}
+	return c.JSON(http.StatusAccepted, res)
+}
+
+func webhookError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Not found"})
+	case errors.Is(err, usecase.ErrForbidden), errors.Is(err, usecase.ErrWebhookAllPlansForbidden):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+}
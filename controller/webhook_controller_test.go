@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"backend/model"
+	"backend/usecase"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+)
+
+// 渡されたページングの値を記録する
+type fakeWebhookUsecase struct {
+	usecase.IWebhookUsecase
+	offset int
+	limit  int
+	called bool
+}
+
+func (u *fakeWebhookUsecase) GetDeliveries(userId uint, webhookId uint, offset int, limit int) ([]model.WebhookDeliveryResponse, error) {
+	u.offset, u.limit, u.called = offset, limit, true
+	return []model.WebhookDeliveryResponse{}, nil
+}
+
+func TestGetDeliveriesPaging(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantOffset int
+		wantLimit  int
+	}{
+		{"未指定は既定の件数", "", http.StatusOK, 0, 20},
+		{"指定した件数", "?offset=40&limit=10", http.StatusOK, 40, 10},
+		{"上限を超える件数は上限にする", "?limit=100000", http.StatusOK, 0, maxDeliveryPageSize},
+		{"負の件数は既定の件数", "?limit=-5", http.StatusOK, 0, 20},
+		{"負のoffsetは拒否する", "?offset=-1", http.StatusBadRequest, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/webhooks/1/deliveries"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("webhookId")
+			c.SetParamValues("1")
+			c.Set("user", &jwt.Token{Claims: jwt.MapClaims{"user_id": float64(10)}})
+
+			wu := &fakeWebhookUsecase{}
+			if err := NewWebhookController(wu).GetDeliveries(c); err != nil {
+				t.Fatalf("GetDeliveries: %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if wu.called {
+					t.Fatalf("usecase was called")
+				}
+				return
+			}
+			if wu.offset != tt.wantOffset || wu.limit != tt.wantLimit {
+				t.Fatalf("offset=%d limit=%d, want offset=%d limit=%d", wu.offset, wu.limit, tt.wantOffset, tt.wantLimit)
+			}
+		})
+	}
+}
@@ -25,7 +25,8 @@ func NewDB() *gorm.DB {
 		os.Getenv("POSTGRES_PORT"),
 		os.Getenv("POSTGRES_DB"))
 
-	db, err := gorm.Open(postgres.Open(url), &gorm.Config{})
+	// 一意制約の違反などをgorm.ErrDuplicatedKeyなどのエラーに変換する
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatalln(err)
 	}
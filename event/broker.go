@@ -0,0 +1,78 @@
+package event
+
+import (
+	"backend/model"
+	"sync"
+)
+
+// 1つの購読者に溜めておけるイベントの数。溢れた購読者は切断し、
+// クライアントにLast-Event-IDで再接続させる
+const subscriberBuffer = 64
+
+// このインスタンスに接続しているクライアントへ計画ごとにイベントを配る
+type Broker struct {
+	mu   sync.Mutex
+	subs map[uint]map[chan model.PlanEvent]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subs: map[uint]map[chan model.PlanEvent]struct{}{}}
+}
+
+// 返した関数で購読をやめる。切断された場合はチャンネルが閉じられる
+func (b *Broker) Subscribe(planId uint) (<-chan model.PlanEvent, func()) {
+	ch := make(chan model.PlanEvent, subscriberBuffer)
+	b.mu.Lock()
+	if b.subs[planId] == nil {
+		b.subs[planId] = map[chan model.PlanEvent]struct{}{}
+	}
+	b.subs[planId][ch] = struct{}{}
+	b.mu.Unlock()
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(planId, ch)
+	}
+}
+
+func (b *Broker) HasSubscribers(planId uint) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs[planId]) > 0
+}
+
+// 受け取りが追いついていない購読者は待たずに切断する
+func (b *Broker) Publish(event model.PlanEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[event.PlanID] {
+		select {
+		case ch <- event:
+		default:
+			b.remove(event.PlanID, ch)
+		}
+	}
+}
+
+// 通知を取りこぼした可能性がある場合に、すべての購読者を再接続させる
+func (b *Broker) CloseAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for planId, chs := range b.subs {
+		for ch := range chs {
+			b.remove(planId, ch)
+		}
+	}
+}
+
+// b.muを取得した状態で呼ぶ
+func (b *Broker) remove(planId uint, ch chan model.PlanEvent) {
+	if _, ok := b.subs[planId][ch]; !ok {
+		return
+	}
+	delete(b.subs[planId], ch)
+	if len(b.subs[planId]) == 0 {
+		delete(b.subs, planId)
+	}
+	close(ch)
+}
@@ -0,0 +1,62 @@
+package event
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+// 接続が切れた後、LISTENし直すまでの待ち時間
+const listenRetryInterval = 5 * time.Second
+
+// Postgresのchannelを購読し、通知のペイロードをhandleに渡す。
+// コネクションプールの接続を1つ専有し、切断された場合は接続し直す。
+// 接続し直した後は、切断中の通知を取りこぼしている可能性があるためonReconnectを呼ぶ
+func Listen(ctx context.Context, db *gorm.DB, channel string, handle func(payload string), onReconnect func()) {
+	go func() {
+		connected := false
+		for ctx.Err() == nil {
+			err := listen(ctx, db, channel, handle, func() {
+				if connected {
+					onReconnect()
+				}
+				connected = true
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("listen %s: %v", channel, err)
+			time.Sleep(listenRetryInterval)
+		}
+	}()
+}
+
+func listen(ctx context.Context, db *gorm.DB, channel string, handle func(payload string), onListen func()) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.Raw(func(driverConn any) error {
+		pgConn := driverConn.(*stdlib.Conn).Conn()
+		if _, err := pgConn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return err
+		}
+		onListen()
+		for {
+			notification, err := pgConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			handle(notification.Payload)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"backend/db"
+	"backend/model"
+	"backend/repository"
+	"backend/usecase"
+	"backend/validator"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"strings"
+)
+
+// シラバスのCSV/TSVを科目カタログへ取り込む
+//
+//	go run ./importer -file syllabus.csv -university 1 -encoding shift_jis -dry-run
+func main() {
+	path := flag.String("file", "", "取り込むCSV/TSVファイル")
+	universityId := flag.Uint("university", 0, "大学ID")
+	encoding := flag.String("encoding", "utf-8", "文字コード(utf-8, shift_jis)")
+	delimiter := flag.String("delimiter", "", "区切り文字(csv, tsv)。未指定の場合は拡張子から判定")
+	mapping := flag.String("mapping", "", "見出し名の対応(JSON)。例: {\"code\":\"授業コード\"}")
+	dryRun := flag.Bool("dry-run", false, "差分のみを表示し反映しない")
+	flag.Parse()
+
+	if *path == "" || *universityId == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	options := model.CatalogImportOptions{
+		UniversityID: uint(*universityId),
+		Encoding:     *encoding,
+		Delimiter:    *delimiter,
+		DryRun:       *dryRun,
+	}
+	if options.Delimiter == "" && strings.HasSuffix(strings.ToLower(*path), ".tsv") {
+		options.Delimiter = "tsv"
+	}
+	if *mapping != "" {
+		if err := json.Unmarshal([]byte(*mapping), &options.Mapping); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer file.Close()
+
+	dbConn := db.NewDB()
+	defer db.CloseDB(dbConn)
+	catalogImportUsecase := usecase.NewCatalogImportUsecase(
+		repository.NewCatalogRepository(dbConn), validator.NewCatalogValidator())
+
+	report, err := catalogImportUsecase.ImportCatalogCourses(file, options)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalln(err)
+	}
+	if report.Errors > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,26 @@
+package job
+
+import (
+	"log"
+	"time"
+)
+
+// 起動直後に1回、その後はintervalごとにfnを実行する。前回の実行が終わるまで次は実行しない
+func Every(name string, interval time.Duration, fn func() error) {
+	go func() {
+		run := func() {
+			started := time.Now()
+			if err := fn(); err != nil {
+				log.Printf("job %s failed: %v", name, err)
+				return
+			}
+			log.Printf("job %s finished in %s", name, time.Since(started))
+		}
+		run()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			run()
+		}
+	}()
+}
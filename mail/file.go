@@ -0,0 +1,24 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 送信する代わりに1通ずつ.emlファイルとして書き出す。開発環境や動作確認で使う
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func (fm *FileMailer) Send(msg Message) error {
+	if err := os.MkdirAll(fm.Dir, 0o755); err != nil {
+		return err
+	}
+	to := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(msg.To)
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), to)
+	return os.WriteFile(filepath.Join(fm.Dir, name), msg.bytes(fm.From), 0o644)
+}
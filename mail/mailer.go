@@ -0,0 +1,82 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"mime"
+	"os"
+	"sort"
+	"time"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string            // プレーンテキスト
+	Headers map[string]string // List-Unsubscribeなど追加のヘッダー
+}
+
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// 環境変数 SMTP_HOST が設定されていればSMTPで送信し、
+// なければ MAIL_FILE_DIR (既定は mail_outbox) にファイルとして書き出す
+func NewMailerFromEnv() Mailer {
+	from := os.Getenv("MAIL_FROM")
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return &SMTPMailer{
+			Host:     host,
+			Port:     port,
+			Username: os.Getenv("SMTP_USER"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}
+	}
+	dir := os.Getenv("MAIL_FILE_DIR")
+	if dir == "" {
+		dir = "mail_outbox"
+	}
+	log.Printf("SMTP_HOST is not set, writing mail to %s", dir)
+	return &FileMailer{Dir: dir, From: from}
+}
+
+// 日本語を含むため件名はMIMEエンコードし、本文はbase64で送る
+func (m Message) bytes(from string) []byte {
+	headers := map[string]string{
+		"From":                      from,
+		"To":                        m.To,
+		"Subject":                   mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date":                      time.Now().Format(time.RFC1123Z),
+		"MIME-Version":              "1.0",
+		"Content-Type":              "text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding": "base64",
+	}
+	for k, v := range m.Headers {
+		headers[k] = v
+	}
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "%s: %s\r\n", k, headers[k])
+	}
+	buf.WriteString("\r\n")
+	body := base64.StdEncoding.EncodeToString([]byte(m.Body))
+	for len(body) > 76 {
+		buf.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	buf.WriteString(body + "\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,23 @@
+package mail
+
+import (
+	"net"
+	"net/smtp"
+)
+
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Usernameが空の場合は認証しない
+func (sm *SMTPMailer) Send(msg Message) error {
+	var auth smtp.Auth
+	if sm.Username != "" {
+		auth = smtp.PlainAuth("", sm.Username, sm.Password, sm.Host)
+	}
+	return smtp.SendMail(net.JoinHostPort(sm.Host, sm.Port), auth, sm.From, []string{msg.To}, msg.bytes(sm.From))
+}
@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// templates/<名前>.<言語>.tmpl に "subject" と "body" を定義する
+//
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = parseTemplates()
+
+func parseTemplates() map[string]*template.Template {
+	files, err := templateFS.ReadDir("templates")
+	if err != nil {
+		panic(err)
+	}
+	parsed := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), ".tmpl")
+		parsed[name] = template.Must(template.ParseFS(templateFS, path.Join("templates", file.Name())))
+	}
+	return parsed
+}
+
+// 件名と本文を描画する
+func Render(name string, locale string, data interface{}) (string, string, error) {
+	tmpl, ok := templates[name+"."+locale]
+	if !ok {
+		return "", "", fmt.Errorf("mail template %s.%s not found", name, locale)
+	}
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(subject.String()), body.String(), nil
+}
@@ -4,10 +4,17 @@ import (
 	"backend/auth"
 	"backend/controller"
 	"backend/db"
+	"backend/event"
+	"backend/job"
+	"backend/mail"
 	"backend/repository"
 	"backend/router"
 	"backend/usecase"
 	"backend/validator"
+	"backend/webhook"
+	"context"
+	"log"
+	"time"
 )
 
 func main() {
@@ -15,10 +22,28 @@ func main() {
 	// auth
 	googleAuthConfig := auth.NewGoogleAuthConfig()
 
+	// 時間割PNGのフォント
+	timetableFont, err := usecase.LoadTimetableFont()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
 	// validation
 	userValidator := validator.NewUserValidator()
 	postValidator := validator.NewPostValidator()
 	planValidator := validator.NewPlanValidator()
+	requirementValidator := validator.NewRequirementValidator()
+	catalogValidator := validator.NewCatalogValidator()
+	termValidator := validator.NewTermValidator()
+	courseValidator := validator.NewCourseValidator()
+	reviewValidator := validator.NewReviewValidator()
+	calendarValidator := validator.NewCalendarValidator()
+	favoriteValidator := validator.NewFavoriteValidator()
+	moderationValidator := validator.NewModerationValidator()
+	commentValidator := validator.NewCommentValidator()
+	answerValidator := validator.NewAnswerValidator()
+	digestValidator := validator.NewDigestValidator()
+	webhookValidator := validator.NewWebhookValidator()
 
 	// repository
 	userRepository := repository.NewUserRepository(db)
@@ -26,22 +51,126 @@ func main() {
 	planRepository := repository.NewPlanRepository(db)
 	courseRepository := repository.NewCourseRepository(db)
 	commentRepository := repository.NewCommentRepository(db)
+	requirementRepository := repository.NewRequirementRepository(db)
+	catalogRepository := repository.NewCatalogRepository(db)
+	prerequisiteRepository := repository.NewPrerequisiteRepository(db)
+	termRepository := repository.NewTermRepository(db)
+	calendarRepository := repository.NewCalendarRepository(db)
+	reviewRepository := repository.NewReviewRepository(db)
+	recommendationRepository := repository.NewRecommendationRepository(db)
+	rankingRepository := repository.NewRankingRepository(db)
+	favoriteRepository := repository.NewFavoriteRepository(db)
+	moderationRepository := repository.NewModerationRepository(db)
+	mentionRepository := repository.NewMentionRepository(db)
+	reactionRepository := repository.NewReactionRepository(db)
+	answerRepository := repository.NewAnswerRepository(db)
+	notificationRepository := repository.NewNotificationRepository(db)
+	planEventRepository := repository.NewPlanEventRepository(db)
+	digestRepository := repository.NewDigestRepository(db)
+	webhookRepository := repository.NewWebhookRepository(db)
+
+	// moderation
+	moderator := usecase.NewNGWordModerator(moderationRepository)
+	commentPolicy := usecase.NewCommentPolicy()
+	reactionPolicy := usecase.NewReactionPolicy()
+
+	// notification
+	notifier := usecase.NewNotifier(notificationRepository)
+	mailer := mail.NewMailerFromEnv()
+
+	// event
+	broker := event.NewBroker()
+	planEventUsecase := usecase.NewPlanEventUsecase(planEventRepository, planRepository, broker)
+
+	// webhook
+	webhookUsecase := usecase.NewWebhookUsecase(webhookRepository, webhookValidator, webhook.NewSender(webhook.NewHTTPClientFromEnv()))
 
 	// usecase
 	userUsecase := usecase.NewUserUsecase(userRepository, userValidator, googleAuthConfig)
-	postUsecase := usecase.NewPostUsecase(postRepository, postValidator)
-	planUsecase := usecase.NewPlanUsecase(planRepository, planValidator)
-	courseUsecase := usecase.NewCourseUsecase(courseRepository)
-	commentUsecase := usecase.NewCommentUsecase(commentRepository)
+	postUsecase := usecase.NewPostUsecase(postRepository, userRepository, planRepository, postValidator, notifier)
+	planUsecase := usecase.NewPlanUsecase(planRepository, planValidator, notifier, planEventUsecase, webhookUsecase)
+	planTransferUsecase := usecase.NewPlanTransferUsecase(planRepository, catalogRepository, planValidator, courseValidator, termValidator, webhookUsecase)
+	courseUsecase := usecase.NewCourseUsecase(courseRepository, catalogRepository, termRepository, planRepository, courseValidator)
+	commentUsecase := usecase.NewCommentUsecase(commentRepository, planRepository, userRepository, moderationRepository, commentValidator, moderator, notifier, planEventUsecase, webhookUsecase, commentPolicy)
+	requirementUsecase := usecase.NewRequirementUsecase(requirementRepository, planRepository, requirementValidator)
+	catalogUsecase := usecase.NewCatalogUsecase(catalogRepository, catalogValidator)
+	catalogImportUsecase := usecase.NewCatalogImportUsecase(catalogRepository, catalogValidator)
+	prerequisiteUsecase := usecase.NewPrerequisiteUsecase(prerequisiteRepository, catalogRepository, planRepository)
+	termUsecase := usecase.NewTermUsecase(termRepository, planRepository, termValidator)
+	calendarUsecase := usecase.NewCalendarUsecase(calendarRepository, planRepository, calendarValidator)
+	timetableUsecase := usecase.NewTimetableUsecase(planRepository, timetableFont)
+	reviewUsecase := usecase.NewReviewUsecase(reviewRepository, catalogRepository, moderationRepository, reviewValidator, moderator)
+	recommendationUsecase := usecase.NewRecommendationUsecase(recommendationRepository, planRepository, userRepository)
+	rankingUsecase := usecase.NewRankingUsecase(rankingRepository, planRepository)
+	favoriteUsecase := usecase.NewFavoriteUsecase(favoriteRepository, planRepository, favoriteValidator, notifier, planEventUsecase, webhookUsecase)
+	moderationUsecase := usecase.NewModerationUsecase(moderationRepository, commentRepository, reviewRepository, moderationValidator, moderator, planEventUsecase, webhookUsecase)
+	mentionUsecase := usecase.NewMentionUsecase(mentionRepository)
+	reactionUsecase := usecase.NewReactionUsecase(reactionRepository, commentRepository, postRepository, planRepository, reactionPolicy)
+	answerUsecase := usecase.NewAnswerUsecase(answerRepository, postRepository, planRepository, answerValidator, moderator, notifier)
+	notificationUsecase := usecase.NewNotificationUsecase(notificationRepository)
+	digestUsecase := usecase.NewDigestUsecase(digestRepository, mailer)
 
 	// controller
 	userController := controller.NewUserController(userUsecase)
 	postController := controller.NewTaskController(postUsecase)
-	planController := controller.NewPlanController(planUsecase)
+	planController := controller.NewPlanController(planUsecase, planTransferUsecase)
 	courseController := controller.NewCourseController(courseUsecase)
 	commentController := controller.NewCommentController(commentUsecase)
+	requirementController := controller.NewRequirementController(requirementUsecase)
+	catalogController := controller.NewCatalogController(catalogUsecase, catalogImportUsecase)
+	prerequisiteController := controller.NewPrerequisiteController(prerequisiteUsecase)
+	termController := controller.NewTermController(termUsecase)
+	calendarController := controller.NewCalendarController(calendarUsecase)
+	timetableController := controller.NewTimetableController(timetableUsecase)
+	reviewController := controller.NewReviewController(reviewUsecase)
+	recommendationController := controller.NewRecommendationController(recommendationUsecase)
+	rankingController := controller.NewRankingController(rankingUsecase)
+	favoriteController := controller.NewFavoriteController(favoriteUsecase)
+	moderationController := controller.NewModerationController(moderationUsecase)
+	mentionController := controller.NewMentionController(mentionUsecase)
+	reactionController := controller.NewReactionController(reactionUsecase)
+	answerController := controller.NewAnswerController(answerUsecase)
+	notificationController := controller.NewNotificationController(notificationUsecase)
+	planEventController := controller.NewPlanEventController(planEventUsecase)
+	digestController := controller.NewDigestController(digestUsecase, digestValidator)
+	webhookController := controller.NewWebhookController(webhookUsecase)
 
 	// router
-	e := router.NewRouter(userController, postController, planController, courseController, commentController)
+	e := router.NewRouter(
+		userController,
+		postController,
+		planController,
+		courseController,
+		commentController,
+		requirementController,
+		catalogController,
+		prerequisiteController,
+		termController,
+		calendarController,
+		timetableController,
+		reviewController,
+		recommendationController,
+		rankingController,
+		favoriteController,
+		moderationController,
+		mentionController,
+		reactionController,
+		answerController,
+		notificationController,
+		planEventController,
+		digestController,
+		webhookController,
+	)
+
+	// job
+	job.Every("recommendations", time.Hour, recommendationUsecase.RecomputeCooccurrences)
+	job.Every("plan-ranking", 15*time.Minute, rankingUsecase.RecomputeScores)
+	job.Every("plan-events-prune", time.Hour, planEventUsecase.PruneEvents)
+	job.Every("digests", time.Hour, digestUsecase.SendDueDigests)
+	job.Every("webhook-deliveries", 30*time.Second, webhookUsecase.DeliverDue)
+
+	// 他のAPIインスタンスで発生したイベントも受け取る
+	event.Listen(context.Background(), db, repository.PlanEventChannel, planEventUsecase.HandleNotification, planEventUsecase.Reconnect)
+
 	e.Logger.Fatal(e.Start(":8080"))
 }
@@ -1,14 +1,26 @@
 package middleware
 
 import (
+	"log"
+	"net"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
 )
 
+// 匿名のコメントを削除するときに削除用トークンを送るヘッダー
+const HeaderDeleteToken = "X-Delete-Token"
+
+// メールソフトから直接POSTされるため、CSRFトークンを検証しないダイジェストの配信停止URL
+const DigestUnsubscribePath = "/digest/unsubscribe"
+
 func JwtMiddleware() echo.MiddlewareFunc {
 	return echojwt.WithConfig(echojwt.Config{
 		SigningKey:  []byte(os.Getenv("SECRET")),
@@ -31,7 +43,8 @@ func CorsMiddleware() echo.MiddlewareFunc {
 			echo.HeaderContentType,
 			echo.HeaderAccept,
 			echo.HeaderAccessControlAllowHeaders,
-			echo.HeaderXCSRFToken},
+			echo.HeaderXCSRFToken,
+			HeaderDeleteToken},
 		AllowMethods:     []string{"GET", "POST", "DELETE", "PUT"},
 		AllowCredentials: true,
 	})
@@ -39,6 +52,9 @@ func CorsMiddleware() echo.MiddlewareFunc {
 
 func CsrfMiddleware() echo.MiddlewareFunc {
 	return middleware.CSRFWithConfig(middleware.CSRFConfig{
+		Skipper: func(c echo.Context) bool {
+			return c.Path() == DigestUnsubscribePath
+		},
 		CookiePath:     "/",
 		CookieDomain:   os.Getenv("API_DOMAIN"),
 		CookieHTTPOnly: true,
@@ -71,3 +87,67 @@ func OptionalJwtMiddleware() echo.MiddlewareFunc {
 		}
 	}
 }
+
+// JwtMiddlewareの後に使用し、トークンのroleが一致しない場合は403を返すミドルウェア
+func RoleMiddleware(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, ok := c.Get("user").(*jwt.Token)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+			}
+			claims := user.Claims.(jwt.MapClaims)
+			role, _ := claims["role"].(string)
+			for _, r := range roles {
+				if role == r {
+					return next(c)
+				}
+			}
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "Forbidden"})
+		}
+	}
+}
+
+// 環境変数 TRUSTED_PROXIES (カンマ区切りのCIDR) に指定したプロキシからのX-Forwarded-Forのみ信頼する。
+// 未設定の場合は接続元のアドレスを使い、クライアントが送ったヘッダーで制限を回避できないようにする
+func IPExtractor() echo.IPExtractor {
+	v := os.Getenv("TRUSTED_PROXIES")
+	if v == "" {
+		return echo.ExtractIPDirect()
+	}
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, cidr := range strings.Split(v, ",") {
+		_, ipRange, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			log.Printf("invalid TRUSTED_PROXIES entry %q, ignoring", cidr)
+			continue
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// 未ログインのリクエストをIPアドレスごとに制限する。OptionalJwtMiddlewareの後に使う
+func AnonymousRateLimitMiddleware(interval time.Duration, burst int) echo.MiddlewareFunc {
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Skipper: func(c echo.Context) bool {
+			_, ok := c.Get("user").(*jwt.Token)
+			return ok
+		},
+		Store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+			Rate:      rate.Every(interval),
+			Burst:     burst,
+			ExpiresIn: 10 * time.Minute,
+		}),
+		IdentifierExtractor: func(c echo.Context) (string, error) {
+			return c.RealIP(), nil
+		},
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "Too many requests. Please wait a moment and try again"})
+		},
+	})
+}
@@ -4,21 +4,134 @@ import (
 	"backend/db"
 	"backend/model"
 	"fmt"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
 )
 
 func main() {
 	dbConn := db.NewDB()
 	defer fmt.Println("Successfully Migrated")
 	defer db.CloseDB(dbConn)
+	if err := dedupeFavoritePlans(dbConn); err != nil {
+		log.Fatalln(err)
+	}
 	dbConn.AutoMigrate(
 		&model.User{},
 		&model.University{},
+		&model.CatalogCourse{},
+		&model.CatalogCourseSlot{},
+		&model.CoursePrerequisite{},
+		&model.AcademicTerm{},
+		&model.PlanTerm{},
+		&model.PeriodTime{},
+		&model.AcademicHoliday{},
+		&model.CalendarFeed{},
+		&model.CourseReview{},
+		&model.CourseCooccurrence{},
 		&model.Course{},
+		&model.CourseSlot{},
 		&model.Department{},
 		&model.Faculty{},
 		&model.FavoritePlan{},
+		&model.FavoriteCollection{},
+		&model.FavoriteCollectionItem{},
 		&model.Plan{},
+		&model.PlanScore{},
+		&model.PlanRankingSnapshot{},
 		&model.Post{},
+		&model.Answer{},
+		&model.AnswerVote{},
 		&model.Comment{},
+		&model.CommentRevision{},
+		&model.Mention{},
+		&model.Reaction{},
+		&model.Notification{},
+		&model.NotificationOptOut{},
+		&model.PlanEvent{},
+		&model.DigestSetting{},
+		&model.Webhook{},
+		&model.WebhookDelivery{},
+		&model.CommentReport{},
+		&model.ReviewReport{},
+		&model.ModerationAction{},
+		&model.NGWord{},
+		&model.RequirementSet{},
+		&model.RequirementRule{},
+		&model.RequirementCourse{},
 	)
+	if err := migrateCourseTermOrder(dbConn); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// (user_id, plan_id)の一意制約を追加する前に、同時操作で重複したお気に入りを最も古い1件に揃える
+func dedupeFavoritePlans(dbConn *gorm.DB) error {
+	if !dbConn.Migrator().HasTable(&model.FavoritePlan{}) {
+		return nil
+	}
+	return dbConn.Exec(`DELETE FROM favorite_plans a USING favorite_plans b
+		WHERE a.user_id = b.user_id AND a.plan_id = b.plan_id AND a.id > b.id`).Error
+}
+
+// 科目の学期を通し番号(courses.term_order。1年前期=1, 1年後期=2, ...)で持っていた計画を、
+// 計画内の学期(plan_terms)への割り当てに移してから通し番号の列を削除する
+func migrateCourseTermOrder(dbConn *gorm.DB) error {
+	if !dbConn.Migrator().HasColumn(&model.Course{}, "term_order") {
+		return nil
+	}
+	return dbConn.Transaction(func(tx *gorm.DB) error {
+		var maxTermOrder int
+		if err := tx.Raw("SELECT COALESCE(MAX(term_order), 0) FROM courses").Scan(&maxTermOrder).Error; err != nil {
+			return err
+		}
+		if maxTermOrder >= 1 {
+			values, args := termOrderValues(maxTermOrder)
+			if err := tx.Exec(`INSERT INTO plan_terms (plan_id, grade, kind, sequence, created_at, updated_at)
+				SELECT DISTINCT courses.plan_id, m.grade, m.kind, m.sequence, NOW(), NOW()
+				FROM courses JOIN (VALUES `+values+`) AS m(term_order, grade, kind, sequence)
+					ON m.term_order = courses.term_order
+				WHERE courses.plan_term_id IS NULL
+				ON CONFLICT (plan_id, grade, kind) DO NOTHING`, args...).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(`UPDATE courses SET plan_term_id = plan_terms.id
+				FROM plan_terms, (VALUES `+values+`) AS m(term_order, grade, kind, sequence)
+				WHERE courses.term_order = m.term_order AND courses.plan_term_id IS NULL
+					AND plan_terms.plan_id = courses.plan_id
+					AND plan_terms.grade = m.grade
+					AND plan_terms.kind = m.kind`, args...).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropColumn(&model.Course{}, "term_order")
+	})
+}
+
+type termOrderTerm struct {
+	Grade    uint
+	Kind     string
+	Sequence int
+}
+
+// 通し番号は前期・後期のみを数える。計画内の学期の通し番号(sequence)は1学年につき前期・後期・集中講義の3つ分進む
+func termOrderToPlanTerm(termOrder int) termOrderTerm {
+	grade := (termOrder + 1) / 2
+	if termOrder%2 == 1 {
+		return termOrderTerm{Grade: uint(grade), Kind: model.TermKindFirst, Sequence: (grade-1)*3 + 1}
+	}
+	return termOrderTerm{Grade: uint(grade), Kind: model.TermKindSecond, Sequence: (grade-1)*3 + 2}
+}
+
+// 1からmaxTermOrderまでの対応表をVALUES句とその引数にする
+func termOrderValues(maxTermOrder int) (string, []interface{}) {
+	rows := make([]string, 0, maxTermOrder)
+	args := make([]interface{}, 0, maxTermOrder*4)
+	for termOrder := 1; termOrder <= maxTermOrder; termOrder++ {
+		term := termOrderToPlanTerm(termOrder)
+		rows = append(rows, "(CAST(? AS bigint), CAST(? AS bigint), CAST(? AS text), CAST(? AS bigint))")
+		args = append(args, termOrder, term.Grade, term.Kind, term.Sequence)
+	}
+	return strings.Join(rows, ", "), args
 }
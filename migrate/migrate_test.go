@@ -0,0 +1,46 @@
+package main
+
+import (
+	"backend/model"
+	"strings"
+	"testing"
+)
+
+func TestTermOrderToPlanTerm(t *testing.T) {
+	tests := []struct {
+		termOrder int
+		want      termOrderTerm
+	}{
+		{1, termOrderTerm{1, model.TermKindFirst, 1}},
+		{2, termOrderTerm{1, model.TermKindSecond, 2}},
+		{3, termOrderTerm{2, model.TermKindFirst, 4}}, // 1年の集中講義の分を空ける
+		{4, termOrderTerm{2, model.TermKindSecond, 5}},
+		{7, termOrderTerm{4, model.TermKindFirst, 10}},
+		{8, termOrderTerm{4, model.TermKindSecond, 11}},
+	}
+	for _, tt := range tests {
+		if got := termOrderToPlanTerm(tt.termOrder); got != tt.want {
+			t.Errorf("termOrderToPlanTerm(%d) = %+v, want %+v", tt.termOrder, got, tt.want)
+		}
+	}
+}
+
+func TestTermOrderValues(t *testing.T) {
+	values, args := termOrderValues(3)
+	if got := strings.Count(values, "(CAST(? AS bigint), "); got != 3 {
+		t.Fatalf("rows = %d, want 3: %s", got, values)
+	}
+	want := []interface{}{
+		1, uint(1), model.TermKindFirst, 1,
+		2, uint(1), model.TermKindSecond, 2,
+		3, uint(2), model.TermKindFirst, 4,
+	}
+	if len(args) != len(want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+	for i := range want {
+		if args[i] != want[i] {
+			t.Fatalf("args = %v, want %v", args, want)
+		}
+	}
+}
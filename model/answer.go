@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// 質問(Post)への回答
+type Answer struct {
+	ID        uint         `json:"id" gorm:"primaryKey"`
+	PostID    uint         `json:"post_id" gorm:"not null;index"`
+	AuthorID  uint         `json:"author_id" gorm:"not null;index"`
+	Content   string       `json:"content" gorm:"not null"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	Author    User         `json:"author" gorm:"foreignKey:AuthorID"`
+	Votes     []AnswerVote `json:"votes" gorm:"foreignKey:AnswerID;constraint:OnDelete:CASCADE"`
+}
+
+// 回答への評価。1人1票で、valueは1(役に立った)か-1
+type AnswerVote struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	AnswerID  uint      `json:"answer_id" gorm:"not null;uniqueIndex:idx_answer_votes_answer_user"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_answer_votes_answer_user"`
+	Value     int       `json:"value" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type AnswerRequest struct {
+	Content string `json:"content"`
+}
+
+type AnswerVoteRequest struct {
+	Value int `json:"value"`
+}
+
+type AcceptAnswerRequest struct {
+	AnswerID uint `json:"answer_id"`
+}
+
+type AnswerResponse struct {
+	ID        uint      `json:"id"`
+	PostID    uint      `json:"post_id"`
+	AuthorID  uint      `json:"author_id"`
+	Content   string    `json:"content"`
+	Score     int       `json:"score"`    // 評価の合計
+	MyVote    int       `json:"my_vote"`  // 自分の評価。未評価の場合は0
+	Accepted  bool      `json:"accepted"` // 質問者がベストアンサーに選んだか
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
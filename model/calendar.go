@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// 大学ごとの時限の開始・終了時刻（"09:00" 形式）
+type PeriodTime struct {
+	ID           uint   `json:"id" gorm:"primaryKey"`
+	UniversityID uint   `json:"university_id" gorm:"not null;uniqueIndex:idx_period_times_university_period"`
+	Period       int    `json:"period" gorm:"not null;uniqueIndex:idx_period_times_university_period"`
+	StartTime    string `json:"start_time" gorm:"not null"`
+	EndTime      string `json:"end_time" gorm:"not null"`
+}
+
+// 授業が行われない日（祝日・大学の休講日）
+type AcademicHoliday struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	UniversityID uint      `json:"university_id" gorm:"not null;index"`
+	Date         time.Time `json:"date" gorm:"type:date;not null"`
+	Name         string    `json:"name" gorm:"not null"`
+}
+
+// カレンダーアプリから購読するためのフィード。トークンはハッシュのみ保存する
+type CalendarFeed struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	PlanID    uint       `json:"plan_id" gorm:"not null;index"`
+	UserID    uint       `json:"user_id" gorm:"not null"`
+	TokenHash string     `json:"-" gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+
+	Plan Plan `json:"plan" gorm:"foreignKey:PlanID"`
+}
+
+type PeriodTimeResponse struct {
+	Period    int    `json:"period"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+type AcademicHolidayResponse struct {
+	ID           uint      `json:"id"`
+	UniversityID uint      `json:"university_id"`
+	Date         time.Time `json:"date"`
+	Name         string    `json:"name"`
+}
+
+// トークンは発行時にのみ返す
+type CalendarFeedResponse struct {
+	ID        uint      `json:"id"`
+	PlanID    uint      `json:"plan_id"`
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
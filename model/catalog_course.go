@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// 大学ごとの正規の科目カタログ
+type CatalogCourse struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	UniversityID uint      `json:"university_id" gorm:"not null;uniqueIndex:idx_catalog_courses_university_code"`
+	Code         string    `json:"code" gorm:"not null;uniqueIndex:idx_catalog_courses_university_code"`
+	Name         string    `json:"name" gorm:"not null;index"`
+	Instructor   *string   `json:"instructor"`
+	Credits      uint      `json:"credits" gorm:"not null;default:0"`
+	Term         *string   `json:"term"` // 開講学期（前期・後期・通年・集中など）
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	University University          `json:"university" gorm:"foreignKey:UniversityID"`
+	Slots      []CatalogCourseSlot `json:"slots" gorm:"foreignKey:CatalogCourseID;constraint:OnDelete:CASCADE"`
+}
+
+// 曜日はtime.Weekdayと同じく0が日曜日、時限は1始まり
+type CatalogCourseSlot struct {
+	ID              uint `json:"id" gorm:"primaryKey"`
+	CatalogCourseID uint `json:"catalog_course_id" gorm:"not null;index"`
+	DayOfWeek       int  `json:"day_of_week" gorm:"not null"`
+	Period          int  `json:"period" gorm:"not null"`
+}
+
+type CatalogCourseSearchParams struct {
+	UniversityID uint
+	Query        string
+	Term         string
+	DayOfWeek    *int
+	Period       *int
+}
+
+type SlotResponse struct {
+	DayOfWeek int `json:"day_of_week"`
+	Period    int `json:"period"`
+}
+
+type CatalogCourseResponse struct {
+	ID           uint           `json:"id"`
+	UniversityID uint           `json:"university_id"`
+	Code         string         `json:"code"`
+	Name         string         `json:"name"`
+	Instructor   *string        `json:"instructor"`
+	Credits      uint           `json:"credits"`
+	Term         *string        `json:"term"`
+	Slots        []SlotResponse `json:"slots"`
+}
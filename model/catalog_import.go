@@ -0,0 +1,52 @@
+package model
+
+// シラバスの取り込み結果の種別
+const (
+	CatalogImportInsert    = "insert"
+	CatalogImportUpdate    = "update"
+	CatalogImportUnchanged = "unchanged"
+	CatalogImportError     = "error"
+)
+
+// 取り込みファイルの見出し名とカタログ項目の対応
+type CatalogColumnMapping struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Instructor string `json:"instructor"`
+	Credits    string `json:"credits"`
+	Term       string `json:"term"`
+	Slots      string `json:"slots"`
+}
+
+type CatalogImportOptions struct {
+	UniversityID uint                 `json:"university_id"`
+	Encoding     string               `json:"encoding"`  // utf-8 または shift_jis
+	Delimiter    string               `json:"delimiter"` // csv または tsv
+	Mapping      CatalogColumnMapping `json:"mapping"`
+	DryRun       bool                 `json:"dry_run"`
+}
+
+type CatalogImportChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+type CatalogImportLineResult struct {
+	Line    int                            `json:"line"`
+	Action  string                         `json:"action"`
+	Code    string                         `json:"code"`
+	Name    string                         `json:"name"`
+	Changes map[string]CatalogImportChange `json:"changes,omitempty"`
+	Errors  []string                       `json:"errors,omitempty"`
+}
+
+type CatalogImportReport struct {
+	UniversityID uint                      `json:"university_id"`
+	DryRun       bool                      `json:"dry_run"`
+	Applied      bool                      `json:"applied"`
+	Inserts      int                       `json:"inserts"`
+	Updates      int                       `json:"updates"`
+	Unchanged    int                       `json:"unchanged"`
+	Errors       int                       `json:"errors"`
+	Lines        []CatalogImportLineResult `json:"lines"`
+}
@@ -2,21 +2,77 @@ package model
 
 import "time"
 
+// コメントの公開状態
+const (
+	CommentStatusVisible = "visible" // 公開中
+	CommentStatusPending = "pending" // NGワードに該当したため確認待ち
+	CommentStatusHidden  = "hidden"  // モデレーターが非表示にした
+)
+
 type Comment struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	Content         string     `json:"content"`
+	PlanID          uint       `json:"plan_id"`
+	UserID          *uint      `json:"user_id"`                // 認証ユーザーの場合のみ設定
+	ParentID        *uint      `json:"parent_id" gorm:"index"` // 返信先のコメント。トップレベルの場合はnil
+	Depth           int        `json:"depth" gorm:"not null;default:0"`
+	Status          string     `json:"status" gorm:"not null;default:visible;index"`
+	EditedAt        *time.Time `json:"edited_at"` // 本文を最後に編集した日時
+	DeleteTokenHash *string    `json:"-"`         // 匿名のコメントを投稿者が削除するためのトークンのハッシュ
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	User            *User      `json:"user" gorm:"foreignKey:UserID"`
+	Plan            Plan       `json:"plan" gorm:"foreignKey:PlanID"`
+	Replies         []Comment  `json:"replies" gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE"`
+
+	Revisions []CommentRevision `json:"revisions" gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE"`
+	Mentions  []Mention         `json:"mentions" gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE"`
+	Reactions []Reaction        `json:"reactions" gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE"`
+}
+
+// 編集で置き換えられる前の本文
+type CommentRevision struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
+	CommentID uint      `json:"comment_id" gorm:"not null;index"`
 	Content   string    `json:"content"`
-	PlanID    uint      `json:"plan_id"`
-	UserID    *uint     `json:"user_id"` // 認証ユーザーの場合のみ設定
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	User      *User     `json:"user" gorm:"foreignKey:UserID"`
-	Plan      Plan      `json:"plan" gorm:"foreignKey:PlanID"`
+	WrittenAt time.Time `json:"written_at" gorm:"not null"` // この本文が書かれた日時
+	CreatedAt time.Time `json:"created_at"`                 // 編集で置き換えられた日時
 }
 
 type CommentResponse struct {
-	ID        uint      `json:"id"`
-	Content   string    `json:"content"`
-	PlanID    uint      `json:"plan_id"`
-	UserID    *uint     `json:"user_id"`
-	CreatedAt time.Time `json:"created_at"`
+	ID             uint              `json:"id"`
+	Content        string            `json:"content"`
+	PlanID         uint              `json:"plan_id"`
+	UserID         *uint             `json:"user_id"`
+	ParentID       *uint             `json:"parent_id"`
+	Depth          int               `json:"depth"`
+	Status         string            `json:"status"`           // visible以外の場合contentは空になる
+	ReplyCount     int               `json:"reply_count"`      // 直下の返信の数
+	HasMoreReplies bool              `json:"has_more_replies"` // repliesに含まれていない返信があるか
+	Edited         bool              `json:"edited"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	Replies        []CommentResponse `json:"replies"`
+	Mentions       []MentionSpan     `json:"mentions"`
+	Reactions      []ReactionSummary `json:"reactions"`
+	DeleteToken    string            `json:"delete_token,omitempty"` // 匿名で投稿した直後のレスポンスにのみ含める
+}
+
+type CommentRequest struct {
+	Content  string `json:"content"`
+	PlanID   uint   `json:"plan_id"`
+	ParentID *uint  `json:"parent_id"`
+	Website  string `json:"website"` // ボット対策の入力欄。画面には表示しないため人は入力しない
+}
+
+type CommentRevisionResponse struct {
+	Version    int       `json:"version"` // 投稿時の本文が1
+	Content    string    `json:"content"`
+	WrittenAt  time.Time `json:"written_at"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+type CommentHistoryResponse struct {
+	Comment   CommentResponse           `json:"comment"`
+	Revisions []CommentRevisionResponse `json:"revisions"` // 古い順。現在の本文は含まない
 }
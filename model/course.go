@@ -3,17 +3,42 @@ package model
 import "time"
 
 type Course struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Name      string    `json:"name" gorm:"not null"`
-	Content   *string   `json:"content"`
-	PlanID    uint      `json:"plan_id" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	Name            string    `json:"name" gorm:"not null"`
+	Content         *string   `json:"content"`
+	Credits         uint      `json:"credits" gorm:"not null;default:0"`
+	Category        *string   `json:"category"`
+	Room            *string   `json:"room"`  // 教室
+	Color           *string   `json:"color"` // 時間割での表示色 (#RRGGBB)
+	PlanID          uint      `json:"plan_id" gorm:"not null"`
+	CatalogCourseID *uint     `json:"catalog_course_id" gorm:"index"` // カタログ外の自由入力の場合はnil
+	PlanTermID      *uint     `json:"plan_term_id" gorm:"index"`      // 学期未割り当ての場合はnil
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 
-	Plan Plan `json:"plan" gorm:"foreignKey:PlanID"`
+	Plan          Plan           `json:"plan" gorm:"foreignKey:PlanID"`
+	CatalogCourse *CatalogCourse `json:"catalog_course" gorm:"foreignKey:CatalogCourseID"`
+	PlanTerm      *PlanTerm      `json:"plan_term" gorm:"foreignKey:PlanTermID"`
+	Slots         []CourseSlot   `json:"slots" gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
 }
+
+// 曜日はtime.Weekdayと同じく0が日曜日、時限は1始まり
+type CourseSlot struct {
+	ID        uint `json:"id" gorm:"primaryKey"`
+	CourseID  uint `json:"course_id" gorm:"not null;index"`
+	DayOfWeek int  `json:"day_of_week" gorm:"not null"`
+	Period    int  `json:"period" gorm:"not null"`
+}
+
 type CourseResponse struct {
-	ID      uint    `json:"id" gorm:"primaryKey"`
-	Name    string  `json:"name"`
-	Content *string `json:"content"`
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	Name            string         `json:"name"`
+	Content         *string        `json:"content"`
+	Credits         uint           `json:"credits"`
+	Category        *string        `json:"category"`
+	Room            *string        `json:"room"`
+	Color           *string        `json:"color"`
+	CatalogCourseID *uint          `json:"catalog_course_id"`
+	PlanTermID      *uint          `json:"plan_term_id"`
+	Slots           []SlotResponse `json:"slots"`
 }
@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// ダイジェストメールの頻度
+const (
+	DigestFrequencyOff    = "off"
+	DigestFrequencyDaily  = "daily"
+	DigestFrequencyWeekly = "weekly"
+)
+
+// ダイジェストメールの言語
+const (
+	DigestLocaleJa = "ja"
+	DigestLocaleEn = "en"
+)
+
+// 設定がないユーザーにはダイジェストを送らない
+type DigestSetting struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null;uniqueIndex"`
+	Frequency  string     `json:"frequency" gorm:"not null;default:off;index"`
+	Locale     string     `json:"locale" gorm:"not null;default:ja"`
+	LastSentAt *time.Time `json:"last_sent_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	User       User       `json:"user" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+type DigestSettingRequest struct {
+	Frequency string `json:"frequency"`
+	Locale    string `json:"locale"`
+}
+
+type DigestSettingResponse struct {
+	Frequency  string     `json:"frequency"`
+	Locale     string     `json:"locale"`
+	LastSentAt *time.Time `json:"last_sent_at"`
+}
+
+// 自分の計画ごとの、前回のダイジェスト以降の反応の数
+type DigestPlanActivity struct {
+	PlanID    uint   `json:"plan_id"`
+	Title     string `json:"title"`
+	Comments  int64  `json:"comments"`
+	Favorites int64  `json:"favorites"`
+	Questions int64  `json:"questions"`
+}
@@ -1,12 +1,36 @@
 package model
 
+import "time"
+
 type FavoritePlan struct {
-	ID     uint `json:"id" gorm:"primaryKey"`
-	UserID uint `json:"user_id" gorm:"not null"`
-	PlanID uint `json:"plan_id" gorm:"not null"`
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_favorite_plans_user_plan"`
+	PlanID    uint      `json:"plan_id" gorm:"not null;uniqueIndex:idx_favorite_plans_user_plan"`
+	Note      *string   `json:"note"` // 保存したユーザーだけが見られるメモ
+	CreatedAt time.Time `json:"created_at"`
+
+	User            User                     `json:"user" gorm:"foreignKey:UserID"`
+	Plan            Plan                     `json:"plan" gorm:"foreignKey:PlanID"`
+	CollectionItems []FavoriteCollectionItem `json:"collection_items" gorm:"foreignKey:FavoritePlanID;constraint:OnDelete:CASCADE"`
+}
+
+// お気に入りを分類するためのコレクション（「情報系の候補」「留学向け」など）
+type FavoriteCollection struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_favorite_collections_user_name"`
+	Name      string    `json:"name" gorm:"not null;uniqueIndex:idx_favorite_collections_user_name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Items []FavoriteCollectionItem `json:"items" gorm:"foreignKey:CollectionID;constraint:OnDelete:CASCADE"`
+}
 
-	User User `json:"user" gorm:"foreignKey:UserID"`
-	Plan Plan `json:"plan" gorm:"foreignKey:PlanID"`
+// 1件のお気に入りは複数のコレクションに入れられる
+type FavoriteCollectionItem struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	CollectionID   uint      `json:"collection_id" gorm:"not null;uniqueIndex:idx_favorite_collection_items_collection_favorite"`
+	FavoritePlanID uint      `json:"favorite_plan_id" gorm:"not null;uniqueIndex:idx_favorite_collection_items_collection_favorite"`
+	CreatedAt      time.Time `json:"created_at"`
 }
 
 type FavoritePlanResponse struct {
@@ -14,3 +38,24 @@ type FavoritePlanResponse struct {
 	UserID uint `json:"user_id"`
 	PlanID uint `json:"plan_id"`
 }
+
+// 自分のお気に入り一覧の1件
+type MyFavoritePlanResponse struct {
+	FavoritedAt   time.Time    `json:"favorited_at"`
+	Note          *string      `json:"note"`
+	CollectionIDs []uint       `json:"collection_ids"`
+	Plan          PlanResponse `json:"plan"`
+}
+
+type FavoriteCollectionResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	PlanCount int64     `json:"plan_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PUT /plans/:planId/favorite のリクエスト。noteを省略した場合はメモを変更しない
+type FavoritePlanRequest struct {
+	Note *string `json:"note"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// コメントや投稿の本文中で@ハンドルによって言及されたユーザー。本文とユーザーの組ごとに1件
+type Mention struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	UserID    uint           `json:"user_id" gorm:"not null;index"` // 言及されたユーザー
+	Handle    string         `json:"handle" gorm:"not null"`        // 言及した時点のハンドル
+	AuthorID  *uint          `json:"author_id"`                     // 言及したユーザー。匿名のコメントの場合はnil
+	CommentID *uint          `json:"comment_id" gorm:"index"`
+	PostID    *uint          `json:"post_id" gorm:"index"`
+	Ranges    []MentionRange `json:"ranges" gorm:"serializer:json"` // 本文中で言及している箇所
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// 本文中の位置。文字(rune)単位で、Endは含まない
+type MentionRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// クライアントが本文中の言及をリンクとして表示するための情報
+type MentionSpan struct {
+	UserID uint   `json:"user_id"`
+	Handle string `json:"handle"`
+	Start  int    `json:"start"` // @を含む位置
+	End    int    `json:"end"`
+}
+
+type MentionResponse struct {
+	ID        uint             `json:"id"`
+	AuthorID  *uint            `json:"author_id"`
+	CreatedAt time.Time        `json:"created_at"`
+	Comment   *CommentResponse `json:"comment,omitempty"`
+	Post      *PostResponse    `json:"post,omitempty"`
+}
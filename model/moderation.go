@@ -0,0 +1,122 @@
+package model
+
+import "time"
+
+// 通報の理由
+const (
+	ReportReasonSpam          = "spam"
+	ReportReasonHarassment    = "harassment"
+	ReportReasonInappropriate = "inappropriate"
+	ReportReasonPersonalInfo  = "personal_info"
+	ReportReasonOther         = "other"
+)
+
+// モデレーションの操作
+const (
+	ModerationActionHold    = "hold" // NGワードによる自動の保留
+	ModerationActionHide    = "hide"
+	ModerationActionRestore = "restore" // 非表示・保留を解除して公開する
+	ModerationActionDelete  = "delete"
+)
+
+// モデレーションの対象の種類
+const (
+	ModerationTargetComment = "comment"
+	ModerationTargetReview  = "review"
+)
+
+type CommentReport struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	CommentID  uint       `json:"comment_id" gorm:"not null;index"`
+	UserID     *uint      `json:"user_id" gorm:"index"` // 未ログインの通報はnil
+	Reason     string     `json:"reason" gorm:"not null"`
+	Detail     *string    `json:"detail"`
+	ResolvedAt *time.Time `json:"resolved_at"` // モデレーターが対応した日時
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// 科目レビューの通報。ログインユーザーのみ通報できる
+type ReviewReport struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	ReviewID   uint       `json:"review_id" gorm:"not null;index"`
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	Reason     string     `json:"reason" gorm:"not null"`
+	Detail     *string    `json:"detail"`
+	ResolvedAt *time.Time `json:"resolved_at"` // モデレーターが対応した日時
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// コメント・レビューを削除しても履歴は残すため、外部キーは張らない。
+// CommentIDとReviewIDはどちらか一方だけを設定する
+type ModerationAction struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	CommentID   *uint     `json:"comment_id" gorm:"index"`
+	ReviewID    *uint     `json:"review_id" gorm:"index"`
+	ModeratorID *uint     `json:"moderator_id"` // 自動の操作はnil
+	Action      string    `json:"action" gorm:"not null"`
+	Reason      *string   `json:"reason"`
+	Content     string    `json:"content"` // 操作時点の本文
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// 該当するコメント・レビューは確認待ちとして保留する
+type NGWord struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Word      string    `json:"word" gorm:"not null;uniqueIndex"`
+	CreatedBy *uint     `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ReportRequest struct {
+	Reason string  `json:"reason"`
+	Detail *string `json:"detail"`
+}
+
+type ModerationActionRequest struct {
+	Reason *string `json:"reason"`
+}
+
+type ReportResponse struct {
+	ID        uint      `json:"id"`
+	UserID    *uint     `json:"user_id"`
+	Reason    string    `json:"reason"`
+	Detail    *string   `json:"detail"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// kindに応じてcommentかreviewのどちらか一方を返す
+type ModerationQueueItemResponse struct {
+	Kind           string                `json:"kind"`
+	Comment        *CommentResponse      `json:"comment,omitempty"`
+	Review         *CourseReviewResponse `json:"review,omitempty"`
+	Content        string                `json:"content"` // 非表示・保留中でも確認できるよう本文を別に返す
+	ReportCount    int                   `json:"report_count"`
+	ReasonCounts   map[string]int        `json:"reason_counts"`
+	LastReportedAt *time.Time            `json:"last_reported_at"`
+	Reports        []ReportResponse      `json:"reports"`
+}
+
+type ModerationActionResponse struct {
+	ID          uint      `json:"id"`
+	CommentID   *uint     `json:"comment_id"`
+	ReviewID    *uint     `json:"review_id"`
+	ModeratorID *uint     `json:"moderator_id"`
+	Action      string    `json:"action"`
+	Reason      *string   `json:"reason"`
+	Content     string    `json:"content"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type NGWordResponse struct {
+	ID        uint      `json:"id"`
+	Word      string    `json:"word"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// モデレーション待ちのコメント・レビューと未対応の通報の集計
+type ModerationQueueEntry struct {
+	Kind           string
+	TargetID       uint
+	ReportCount    int
+	LastReportedAt *time.Time
+}
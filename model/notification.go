@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// 通知の種類
+const (
+	NotificationTypeComment        = "comment"         // 自分の計画へのコメント
+	NotificationTypeReply          = "reply"           // 自分のコメントへの返信
+	NotificationTypeMention        = "mention"         // コメント・投稿での@による言及
+	NotificationTypeFavorite       = "favorite"        // 自分の計画のお気に入り登録
+	NotificationTypeFork           = "fork"            // 自分の計画のコピー
+	NotificationTypeQuestion       = "question"        // 自分の計画への質問
+	NotificationTypeAnswer         = "answer"          // 自分の質問への回答
+	NotificationTypeAnswerAccepted = "answer_accepted" // 自分の回答がベストアンサーに選ばれた
+)
+
+// 設定画面に表示する順
+var NotificationTypes = []string{
+	NotificationTypeComment,
+	NotificationTypeReply,
+	NotificationTypeMention,
+	NotificationTypeFavorite,
+	NotificationTypeFork,
+	NotificationTypeQuestion,
+	NotificationTypeAnswer,
+	NotificationTypeAnswerAccepted,
+}
+
+// 通知元のコメントや計画が削除されても通知は残すため、外部キーは張らない
+type Notification struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"` // 通知を受け取るユーザー
+	Type      string     `json:"type" gorm:"not null"`
+	ActorID   *uint      `json:"actor_id"` // 通知のきっかけを作ったユーザー。匿名の場合はnil
+	PlanID    *uint      `json:"plan_id"`
+	CommentID *uint      `json:"comment_id"`
+	PostID    *uint      `json:"post_id"`
+	AnswerID  *uint      `json:"answer_id"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"index"`
+}
+
+// 受け取らないように設定した通知の種類
+type NotificationOptOut struct {
+	ID     uint   `json:"id" gorm:"primaryKey"`
+	UserID uint   `json:"user_id" gorm:"not null;uniqueIndex:idx_notification_opt_outs_user_type"`
+	Type   string `json:"type" gorm:"not null;uniqueIndex:idx_notification_opt_outs_user_type"`
+}
+
+type NotificationResponse struct {
+	ID        uint       `json:"id"`
+	Type      string     `json:"type"`
+	ActorID   *uint      `json:"actor_id"`
+	PlanID    *uint      `json:"plan_id"`
+	CommentID *uint      `json:"comment_id"`
+	PostID    *uint      `json:"post_id"`
+	AnswerID  *uint      `json:"answer_id"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type NotificationsResponse struct {
+	UnreadCount   int64                  `json:"unread_count"`
+	Notifications []NotificationResponse `json:"notifications"`
+}
+
+type NotificationPreference struct {
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
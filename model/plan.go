@@ -2,53 +2,73 @@ package model
 
 import "time"
 
+// 計画の公開範囲
+const (
+	PlanVisibilityPublic  = "public"  // ログインユーザー全員が閲覧できる
+	PlanVisibilityPrivate = "private" // 作成者のみ閲覧できる
+)
+
 type Plan struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Title     string    `json:"title" gorm:"not null"`
-	Content   *string   `json:"content"`
-	UserID    uint      `json:"user_id" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	Title        string    `json:"title" gorm:"not null"`
+	Content      *string   `json:"content"`
+	UserID       uint      `json:"user_id" gorm:"not null"`
+	Visibility   string    `json:"visibility" gorm:"not null;default:public"`
+	ForkedFromID *uint     `json:"forked_from_id" gorm:"index"` // コピー元の計画
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 
 	User      User           `json:"user" gorm:"foreignKey:UserID"`
 	Courses   []Course       `json:"courses" gorm:"foreignKey:PlanID"`
 	Posts     []Post         `json:"posts" gorm:"foreignKey:PlanID"`
 	Favorites []FavoritePlan `json:"favorites" gorm:"foreignKey:PlanID"`
+	Terms     []PlanTerm     `json:"terms" gorm:"foreignKey:PlanID"`
 }
 
 type PlanResponse struct {
-	ID           uint         `json:"id" gorm:"primaryKey"`
-	Title        string       `json:"title"`
-	Content      *string      `json:"content"`
-	UserID       uint         `json:"user_id"`
-	CreatedAt    time.Time    `json:"created_at"`
-	UpdatedAt    time.Time    `json:"updated_at"`
-	UserResponse UserResponse `json:"user"`
+	ID            uint         `json:"id" gorm:"primaryKey"`
+	Title         string       `json:"title"`
+	Content       *string      `json:"content"`
+	UserID        uint         `json:"user_id"`
+	Visibility    string       `json:"visibility"`
+	FavoriteCount int64        `json:"favorite_count"`
+	FavoritedByMe bool         `json:"favorited_by_me"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	UserResponse  UserResponse `json:"user"`
 }
 type PlanBaseResponse struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Title     string    `json:"title"`
-	Content   *string   `json:"content"`
-	UserID    uint      `json:"user_id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	Title        string    `json:"title"`
+	Content      *string   `json:"content"`
+	UserID       uint      `json:"user_id"`
+	Visibility   string    `json:"visibility"`
+	ForkedFromID *uint     `json:"forked_from_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 type PlanDetailResponse struct {
-	ID        uint                   `json:"id" gorm:"primaryKey"`
-	Title     string                 `json:"title"`
-	Content   *string                `json:"content"`
-	UserID    uint                   `json:"user_id"`
-	CreatedAt time.Time              `json:"created_at"`
-	UpdatedAt time.Time              `json:"updated_at"`
-	User      UserResponse           `json:"user"`
-	Courses   []CourseResponse       `json:"courses" gorm:"foreignKey:PlanID"`
-	Posts     []PostResponse         `json:"posts" gorm:"foreignKey:PlanID"`
-	Favorites []FavoritePlanResponse `json:"favorites" gorm:"foreignKey:PlanID"`
+	ID            uint                   `json:"id" gorm:"primaryKey"`
+	Title         string                 `json:"title"`
+	Content       *string                `json:"content"`
+	UserID        uint                   `json:"user_id"`
+	Visibility    string                 `json:"visibility"`
+	ForkedFromID  *uint                  `json:"forked_from_id"`
+	FavoriteCount int64                  `json:"favorite_count"`
+	FavoritedByMe bool                   `json:"favorited_by_me"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+	User          UserResponse           `json:"user"`
+	Courses       []CourseResponse       `json:"courses" gorm:"foreignKey:PlanID"`
+	Posts         []PostResponse         `json:"posts" gorm:"foreignKey:PlanID"`
+	Favorites     []FavoritePlanResponse `json:"favorites" gorm:"foreignKey:PlanID"`
+	Terms         PlanTermsResponse      `json:"terms"`
 }
 type PlanUpdateResponse struct {
-	ID        uint      `json:"id"`
-	Title     string    `json:"title"`
-	Content   *string   `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         uint      `json:"id"`
+	Title      string    `json:"title"`
+	Content    *string   `json:"content"`
+	Visibility string    `json:"visibility"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
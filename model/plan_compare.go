@@ -0,0 +1,37 @@
+package model
+
+// 計画の比較結果。科目はカタログの科目IDが同じもの、なければ正規化した科目名が同じものを同一とみなす
+type PlanCompareResponse struct {
+	Plans         []PlanCompareSummary         `json:"plans"`
+	SharedCourses []PlanCompareCourse          `json:"shared_courses"` // 2つ以上の計画にある科目
+	UniqueCourses map[uint][]PlanCompareCourse `json:"unique_courses"` // 計画IDごとの、その計画にしかない科目
+	Timetable     []PlanCompareSlot            `json:"timetable"`      // 科目があるコマのみ
+}
+
+type PlanCompareSummary struct {
+	ID                uint            `json:"id"`
+	Title             string          `json:"title"`
+	UserID            uint            `json:"user_id"`
+	CourseCount       int             `json:"course_count"`
+	TotalCredits      uint            `json:"total_credits"`
+	CreditsByCategory map[string]uint `json:"credits_by_category"` // 区分のない科目は空文字のキーに集計する
+	CreditDifference  int             `json:"credit_difference"`   // 最初の計画との差
+}
+
+type PlanCompareCourse struct {
+	Name            string `json:"name"`
+	CatalogCourseID *uint  `json:"catalog_course_id"`
+	Credits         uint   `json:"credits"`
+	PlanIDs         []uint `json:"plan_ids"`
+}
+
+type PlanCompareSlot struct {
+	DayOfWeek int                    `json:"day_of_week"`
+	Period    int                    `json:"period"`
+	Entries   []PlanCompareSlotEntry `json:"entries"`
+}
+
+type PlanCompareSlotEntry struct {
+	PlanID     uint   `json:"plan_id"`
+	CourseName string `json:"course_name"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// 計画のページにSSEで配信するイベントの種類
+const (
+	PlanEventCommentCreated = "comment.created"
+	PlanEventCommentUpdated = "comment.updated"
+	PlanEventCommentDeleted = "comment.deleted" // 削除のほか、非表示・確認待ちになった場合も送る
+	PlanEventFavoriteCount  = "favorite.count"
+	PlanEventResync         = "resync" // 送り直すイベントが多すぎる場合に、計画を読み込み直させる
+)
+
+// 再接続したクライアントにLast-Event-ID以降を送り直すため、配信したイベントを一定期間保存する
+type PlanEvent struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	PlanID    uint      `json:"plan_id" gorm:"not null;index"`
+	Type      string    `json:"type" gorm:"not null"`
+	Data      string    `json:"data" gorm:"type:jsonb;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+type CommentDeletedEvent struct {
+	ID     uint `json:"id"`
+	PlanID uint `json:"plan_id"`
+}
+
+type ResyncEvent struct {
+	PlanID uint `json:"plan_id"`
+}
+
+type FavoriteCountEvent struct {
+	PlanID        uint  `json:"plan_id"`
+	FavoriteCount int64 `json:"favorite_count"`
+}
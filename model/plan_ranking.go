@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// 計画の人気度。定期実行で再計算する
+type PlanScore struct {
+	PlanID        uint      `json:"plan_id" gorm:"primaryKey;autoIncrement:false"`
+	Score         float64   `json:"score" gorm:"not null;index"`
+	FavoriteCount int       `json:"favorite_count" gorm:"not null"`
+	CommentCount  int       `json:"comment_count" gorm:"not null"`
+	ForkCount     int       `json:"fork_count" gorm:"not null"`
+	ComputedAt    time.Time `json:"computed_at" gorm:"not null"`
+}
+
+// 週の始め(月曜日)時点のスコア。順位の変動を出すために使う
+type PlanRankingSnapshot struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	WeekStart time.Time `json:"week_start" gorm:"type:date;not null;uniqueIndex:idx_plan_ranking_snapshots_week_plan"`
+	PlanID    uint      `json:"plan_id" gorm:"not null;uniqueIndex:idx_plan_ranking_snapshots_week_plan"`
+	Score     float64   `json:"score" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TrendingPlanResponse struct {
+	Rank          int          `json:"rank"`
+	PreviousRank  *int         `json:"previous_rank"` // 先週の順位。ランク外だった場合はnil
+	RankChange    *int         `json:"rank_change"`   // 正の値は順位が上がったことを表す
+	Score         float64      `json:"score"`
+	FavoriteCount int          `json:"favorite_count"`
+	CommentCount  int          `json:"comment_count"`
+	ForkCount     int          `json:"fork_count"`
+	Plan          PlanResponse `json:"plan"`
+}
+
+type TrendingPlansResponse struct {
+	WeekStart *time.Time             `json:"week_start"` // 比較に使ったスナップショットの週
+	Plans     []TrendingPlanResponse `json:"plans"`
+}
+
+// スコア計算に使う計画への反応(お気に入り・コメント・コピー)
+type PlanActivity struct {
+	PlanID    uint
+	CreatedAt time.Time
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// 書き出し形式の版。項目を互換性なく変えた場合に上げる
+const PlanExportVersion = 1
+
+// 計画の書き出し・取り込みに使う形式。アカウントや環境をまたいで使えるよう計画・科目のIDは含めない
+type PlanExport struct {
+	Version    int                `json:"version"`
+	ExportedAt time.Time          `json:"exported_at"`
+	Title      string             `json:"title"`
+	Content    *string            `json:"content"`
+	Terms      []PlanExportTerm   `json:"terms"`
+	Courses    []PlanExportCourse `json:"courses"`
+}
+
+type PlanExportTerm struct {
+	Grade uint   `json:"grade"`
+	Kind  string `json:"kind"`
+}
+
+type PlanExportCourse struct {
+	Name            string         `json:"name"`
+	Content         *string        `json:"content"`
+	Credits         uint           `json:"credits"`
+	Category        *string        `json:"category"`
+	Room            *string        `json:"room"`
+	Color           *string        `json:"color"`
+	CatalogCourseID *uint          `json:"catalog_course_id"`
+	Grade           *uint          `json:"grade"`     // 学期未割り当ての場合はnil
+	TermKind        *string        `json:"term_kind"` // 学期未割り当ての場合はnil
+	Slots           []SlotResponse `json:"slots"`
+}
+
+// 取り込みでエラーになった行。CSVは行番号、JSONはcoursesの1始まりの位置
+type PlanImportRowError struct {
+	Row    int      `json:"row"`
+	Name   string   `json:"name"`
+	Errors []string `json:"errors"`
+}
+
+type PlanImportReport struct {
+	Valid  bool                 `json:"valid"`
+	Plan   *PlanBaseResponse    `json:"plan"`
+	Errors []PlanImportRowError `json:"errors"`
+}
+
+type PlanImportOptions struct {
+	Format  string  `json:"format"`  // json または csv
+	Title   string  `json:"title"`   // 指定した場合はファイルの値より優先する
+	Content *string `json:"content"` // 指定した場合はファイルの値より優先する
+}
@@ -2,17 +2,28 @@ package model
 
 import "time"
 
+// 計画に対する質問として使う投稿。回答はAnswerに保存する
 type Post struct {
-	ID        uint       `json:"id" gorm:"primaryKey;autoIncrement"`
-	Content   *string    `json:"content"`
-	PlanID    *uint      `json:"plan_id"`
-	CreatedAt *time.Time `json:"created_at" gorm:"autoCreateTime"`
-	Author    User       `json:"author" gorm:"foreignKey:AuthorID"`
-	AuthorID  uint       `json:"author_id"`
-	Plan      *Plan      `json:"plan" gorm:"foreignKey:PlanID"`
+	ID               uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Content          *string    `json:"content"`
+	PlanID           *uint      `json:"plan_id"`
+	CreatedAt        *time.Time `json:"created_at" gorm:"autoCreateTime"`
+	Author           User       `json:"author" gorm:"foreignKey:AuthorID"`
+	AuthorID         uint       `json:"author_id"`
+	Plan             *Plan      `json:"plan" gorm:"foreignKey:PlanID"`
+	AcceptedAnswerID *uint      `json:"accepted_answer_id"` // 質問者が選んだベストアンサー
+	Answers          []Answer   `json:"answers" gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE"`
+	Mentions         []Mention  `json:"mentions" gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE"`
+	Reactions        []Reaction `json:"reactions" gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE"`
 }
 type PostResponse struct {
-	ID        uint       `json:"id" gorm:"primaryKey"`
-	Content   *string    `json:"content"`
-	CreatedAt *time.Time `json:"created_at"`
+	ID               uint              `json:"id" gorm:"primaryKey"`
+	Content          *string           `json:"content"`
+	PlanID           *uint             `json:"plan_id"`
+	AuthorID         uint              `json:"author_id"`
+	AnswerCount      int               `json:"answer_count"`
+	AcceptedAnswerID *uint             `json:"accepted_answer_id"`
+	CreatedAt        *time.Time        `json:"created_at"`
+	Mentions         []MentionSpan     `json:"mentions"`
+	Reactions        []ReactionSummary `json:"reactions"`
 }
@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+const (
+	PrerequisiteTypePrerequisite = "prerequisite" // 先に履修が必要
+	PrerequisiteTypeCorequisite  = "corequisite"  // 同時またはそれ以前に履修が必要
+)
+
+// CatalogCourseIDの科目はRequiredCourseIDの科目を必要とする
+type CoursePrerequisite struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	CatalogCourseID  uint      `json:"catalog_course_id" gorm:"not null;uniqueIndex:idx_course_prerequisites_edge"`
+	RequiredCourseID uint      `json:"required_course_id" gorm:"not null;uniqueIndex:idx_course_prerequisites_edge;index"`
+	Type             string    `json:"type" gorm:"not null"`
+	CreatedAt        time.Time `json:"created_at"`
+
+	CatalogCourse  CatalogCourse `json:"catalog_course" gorm:"foreignKey:CatalogCourseID;constraint:OnDelete:CASCADE"`
+	RequiredCourse CatalogCourse `json:"required_course" gorm:"foreignKey:RequiredCourseID;constraint:OnDelete:CASCADE"`
+}
+
+type CoursePrerequisiteResponse struct {
+	ID               uint   `json:"id"`
+	CatalogCourseID  uint   `json:"catalog_course_id"`
+	RequiredCourseID uint   `json:"required_course_id"`
+	Type             string `json:"type"`
+}
+
+type PrerequisiteGraphNode struct {
+	ID   uint   `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// 依存関係の描画用。辺はRequiredCourseIDからCatalogCourseIDへ向かう
+type PrerequisiteGraphResponse struct {
+	Nodes []PrerequisiteGraphNode      `json:"nodes"`
+	Edges []CoursePrerequisiteResponse `json:"edges"`
+}
+
+const (
+	PrerequisiteIssueMissing = "missing"         // 必要な科目が計画にない
+	PrerequisiteIssueOrder   = "scheduled_later" // 必要な科目が後の学期に置かれている
+)
+
+type PrerequisiteIssueResponse struct {
+	CourseID           uint   `json:"course_id"`
+	CourseName         string `json:"course_name"`
+	RequiredCourseID   uint   `json:"required_course_id"`
+	RequiredCourseName string `json:"required_course_name"`
+	Type               string `json:"type"`
+	Issue              string `json:"issue"`
+}
+
+type PrerequisiteCheckResponse struct {
+	PlanID uint                        `json:"plan_id"`
+	Valid  bool                        `json:"valid"`
+	Issues []PrerequisiteIssueResponse `json:"issues"`
+}
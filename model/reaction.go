@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// コメントまたは投稿への絵文字のリアクション。同じ対象に同じ絵文字は1人1つまで
+type Reaction struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_reactions_user_comment_emoji;uniqueIndex:idx_reactions_user_post_emoji"`
+	CommentID *uint     `json:"comment_id" gorm:"index;uniqueIndex:idx_reactions_user_comment_emoji"`
+	PostID    *uint     `json:"post_id" gorm:"index;uniqueIndex:idx_reactions_user_post_emoji"`
+	Emoji     string    `json:"emoji" gorm:"not null;uniqueIndex:idx_reactions_user_comment_emoji;uniqueIndex:idx_reactions_user_post_emoji"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 絵文字ごとの集計
+type ReactionSummary struct {
+	Emoji       string `json:"emoji"`
+	Count       int    `json:"count"`
+	ReactedByMe bool   `json:"reacted_by_me"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// 同じ学科の計画で一緒に履修されている科目の組。定期的に集計し直す。
+// 科目はカタログの科目ID、なければ正規化した科目名で識別する
+type CourseCooccurrence struct {
+	ID                    uint      `json:"id" gorm:"primaryKey"`
+	DepartmentID          uint      `json:"department_id" gorm:"not null;uniqueIndex:idx_course_cooccurrences_pair"`
+	CourseKey             string    `json:"course_key" gorm:"not null;uniqueIndex:idx_course_cooccurrences_pair"`
+	PairedKey             string    `json:"paired_key" gorm:"not null;uniqueIndex:idx_course_cooccurrences_pair"`
+	PairedName            string    `json:"paired_name" gorm:"not null"`
+	PairedCatalogCourseID *uint     `json:"paired_catalog_course_id"`
+	PlanCount             int       `json:"plan_count" gorm:"not null"` // 両方を含む計画の数
+	Score                 float64   `json:"score" gorm:"not null"`      // CourseKeyを含む計画のうちPairedKeyも含む割合（お気に入り数で重み付け）
+	ComputedAt            time.Time `json:"computed_at"`
+}
+
+type RecommendedCourseResponse struct {
+	Name            string   `json:"name"`
+	CatalogCourseID *uint    `json:"catalog_course_id"`
+	Score           float64  `json:"score"`
+	PlanCount       int      `json:"plan_count"`
+	PairedWith      []string `json:"paired_with"` // 推薦の根拠になった科目
+}
@@ -0,0 +1,85 @@
+package model
+
+import "time"
+
+// 卒業要件のルール種別
+const (
+	RequirementTypeMinCredits = "min_credits" // 区分ごとの最低単位数
+	RequirementTypeMandatory  = "mandatory"   // 必修科目
+	RequirementTypeChooseN    = "choose_n"    // 指定科目からN科目以上
+)
+
+// 学科・入学年度ごとの卒業要件
+type RequirementSet struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	Name         string    `json:"name" gorm:"not null"`
+	DepartmentID uint      `json:"department_id" gorm:"not null;uniqueIndex:idx_requirement_sets_department_entry_year"`
+	EntryYear    uint      `json:"entry_year" gorm:"not null;uniqueIndex:idx_requirement_sets_department_entry_year"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	Department Department        `json:"department" gorm:"foreignKey:DepartmentID"`
+	Rules      []RequirementRule `json:"rules" gorm:"foreignKey:RequirementSetID;constraint:OnDelete:CASCADE"`
+}
+
+type RequirementRule struct {
+	ID               uint    `json:"id" gorm:"primaryKey"`
+	RequirementSetID uint    `json:"requirement_set_id" gorm:"not null"`
+	Type             string  `json:"type" gorm:"not null"`
+	Name             string  `json:"name" gorm:"not null"`
+	Category         *string `json:"category"` // min_credits で未指定の場合は全科目の合計
+	MinCredits       uint    `json:"min_credits"`
+	MinCount         uint    `json:"min_count"`
+
+	Courses []RequirementCourse `json:"courses" gorm:"foreignKey:RequirementRuleID;constraint:OnDelete:CASCADE"`
+}
+
+type RequirementCourse struct {
+	ID                uint   `json:"id" gorm:"primaryKey"`
+	RequirementRuleID uint   `json:"requirement_rule_id" gorm:"not null"`
+	CourseName        string `json:"course_name" gorm:"not null"`
+	CatalogCourseID   *uint  `json:"catalog_course_id"` // 指定した場合は科目名より優先して照合する
+}
+
+type RequirementSetResponse struct {
+	ID           uint                      `json:"id"`
+	Name         string                    `json:"name"`
+	DepartmentID uint                      `json:"department_id"`
+	EntryYear    uint                      `json:"entry_year"`
+	Rules        []RequirementRuleResponse `json:"rules"`
+	CreatedAt    time.Time                 `json:"created_at"`
+	UpdatedAt    time.Time                 `json:"updated_at"`
+}
+
+type RequirementRuleResponse struct {
+	ID         uint     `json:"id"`
+	Type       string   `json:"type"`
+	Name       string   `json:"name"`
+	Category   *string  `json:"category"`
+	MinCredits uint     `json:"min_credits"`
+	MinCount   uint     `json:"min_count"`
+	Courses    []string `json:"courses"`
+}
+
+// 履修計画に対する卒業要件の判定結果
+type RequirementEvaluationResponse struct {
+	PlanID           uint                        `json:"plan_id"`
+	RequirementSetID uint                        `json:"requirement_set_id"`
+	Name             string                      `json:"name"`
+	Satisfied        bool                        `json:"satisfied"`
+	TotalCredits     uint                        `json:"total_credits"`
+	SatisfiedRules   []RequirementResultResponse `json:"satisfied_requirements"`
+	MissingRules     []RequirementResultResponse `json:"missing_requirements"`
+}
+
+type RequirementResultResponse struct {
+	RuleID          uint     `json:"rule_id"`
+	Type            string   `json:"type"`
+	Name            string   `json:"name"`
+	Category        *string  `json:"category,omitempty"`
+	RequiredCredits uint     `json:"required_credits,omitempty"`
+	EarnedCredits   uint     `json:"earned_credits,omitempty"`
+	RequiredCount   uint     `json:"required_count,omitempty"`
+	MatchedCourses  []string `json:"matched_courses"`
+	MissingCourses  []string `json:"missing_courses"`
+}
@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// レビューの公開状態。値はコメントと同じ
+const (
+	ReviewStatusVisible = CommentStatusVisible
+	ReviewStatusPending = CommentStatusPending
+	ReviewStatusHidden  = CommentStatusHidden
+)
+
+// 科目カタログへのレビュー。同じ科目・同じ学期に履修した分は1人1件まで
+type CourseReview struct {
+	ID                   uint      `json:"id" gorm:"primaryKey"`
+	CatalogCourseID      uint      `json:"catalog_course_id" gorm:"not null;uniqueIndex:idx_course_reviews_user_course_term"`
+	UserID               uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_course_reviews_user_course_term"`
+	AcademicYear         uint      `json:"academic_year" gorm:"not null;uniqueIndex:idx_course_reviews_user_course_term"` // 履修した年度
+	TermKind             string    `json:"term_kind" gorm:"not null;uniqueIndex:idx_course_reviews_user_course_term"`     // 履修した学期
+	Rating               int       `json:"rating" gorm:"not null"`                                                        // 総合評価 1〜5
+	Difficulty           int       `json:"difficulty" gorm:"not null"`                                                    // 難易度 1〜5
+	Workload             int       `json:"workload" gorm:"not null"`                                                      // 課題の量 1〜5
+	AttendanceStrictness int       `json:"attendance_strictness" gorm:"not null"`                                         // 出席の厳しさ 1〜5
+	Content              string    `json:"content"`
+	Status               string    `json:"status" gorm:"not null;default:visible;index"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+
+	User          User          `json:"user" gorm:"foreignKey:UserID"`
+	CatalogCourse CatalogCourse `json:"catalog_course" gorm:"foreignKey:CatalogCourseID"`
+}
+
+// 集計値。レビューがない場合の平均はnil
+type CourseReviewSummary struct {
+	ReviewCount                 int64
+	AverageRating               *float64
+	AverageDifficulty           *float64
+	AverageWorkload             *float64
+	AverageAttendanceStrictness *float64
+}
+
+type CourseReviewResponse struct {
+	ID                   uint      `json:"id"`
+	CatalogCourseID      uint      `json:"catalog_course_id"`
+	UserID               uint      `json:"user_id"`
+	AcademicYear         uint      `json:"academic_year"`
+	TermKind             string    `json:"term_kind"`
+	Rating               int       `json:"rating"`
+	Difficulty           int       `json:"difficulty"`
+	Workload             int       `json:"workload"`
+	AttendanceStrictness int       `json:"attendance_strictness"`
+	Content              string    `json:"content"`
+	Status               string    `json:"status"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+type CourseReviewSummaryResponse struct {
+	CatalogCourseID             uint     `json:"catalog_course_id"`
+	ReviewCount                 int64    `json:"review_count"`
+	AverageRating               *float64 `json:"average_rating"`
+	AverageDifficulty           *float64 `json:"average_difficulty"`
+	AverageWorkload             *float64 `json:"average_workload"`
+	AverageAttendanceStrictness *float64 `json:"average_attendance_strictness"`
+}
@@ -0,0 +1,79 @@
+package model
+
+import "time"
+
+// 学期の種別
+const (
+	TermKindFirst     = "first"     // 前期
+	TermKindSecond    = "second"    // 後期
+	TermKindIntensive = "intensive" // 集中講義
+)
+
+// 大学ごとの学年暦
+type AcademicTerm struct {
+	ID                  uint       `json:"id" gorm:"primaryKey"`
+	UniversityID        uint       `json:"university_id" gorm:"not null;uniqueIndex:idx_academic_terms_university_year_kind"`
+	AcademicYear        uint       `json:"academic_year" gorm:"not null;uniqueIndex:idx_academic_terms_university_year_kind"` // 年度
+	Kind                string     `json:"kind" gorm:"not null;uniqueIndex:idx_academic_terms_university_year_kind"`
+	Name                string     `json:"name" gorm:"not null"`
+	StartDate           time.Time  `json:"start_date" gorm:"type:date;not null"`
+	EndDate             time.Time  `json:"end_date" gorm:"type:date;not null"`
+	RegistrationStartAt *time.Time `json:"registration_start_at"` // 履修登録期間
+	RegistrationEndAt   *time.Time `json:"registration_end_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+
+	University University `json:"university" gorm:"foreignKey:UniversityID"`
+}
+
+// 履修計画内の学期（1年前期、1年後期、...）
+type PlanTerm struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	PlanID         uint      `json:"plan_id" gorm:"not null;uniqueIndex:idx_plan_terms_plan_grade_kind"`
+	Grade          uint      `json:"grade" gorm:"not null;uniqueIndex:idx_plan_terms_plan_grade_kind"`
+	Kind           string    `json:"kind" gorm:"not null;uniqueIndex:idx_plan_terms_plan_grade_kind"`
+	Sequence       int       `json:"sequence" gorm:"not null"` // 学期の前後関係の比較に使う通し番号
+	AcademicTermID *uint     `json:"academic_term_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	Plan         Plan          `json:"plan" gorm:"foreignKey:PlanID"`
+	AcademicTerm *AcademicTerm `json:"academic_term" gorm:"foreignKey:AcademicTermID"`
+	Courses      []Course      `json:"courses" gorm:"foreignKey:PlanTermID"`
+}
+
+type AcademicTermResponse struct {
+	ID                  uint       `json:"id"`
+	UniversityID        uint       `json:"university_id"`
+	AcademicYear        uint       `json:"academic_year"`
+	Kind                string     `json:"kind"`
+	Name                string     `json:"name"`
+	StartDate           time.Time  `json:"start_date"`
+	EndDate             time.Time  `json:"end_date"`
+	RegistrationStartAt *time.Time `json:"registration_start_at"`
+	RegistrationEndAt   *time.Time `json:"registration_end_at"`
+}
+
+type PlanTermResponse struct {
+	ID             uint                  `json:"id"`
+	PlanID         uint                  `json:"plan_id"`
+	Grade          uint                  `json:"grade"`
+	Kind           string                `json:"kind"`
+	Name           string                `json:"name"`
+	Sequence       int                   `json:"sequence"`
+	AcademicTermID *uint                 `json:"academic_term_id"`
+	AcademicTerm   *AcademicTermResponse `json:"academic_term"`
+	Credits        uint                  `json:"credits"`
+	Courses        []CourseResponse      `json:"courses"`
+}
+
+// 学期ごとにまとめた科目一覧
+type PlanTermsResponse struct {
+	PlanID     uint               `json:"plan_id"`
+	Terms      []PlanTermResponse `json:"terms"`
+	Unassigned []CourseResponse   `json:"unassigned"`
+}
+
+type CourseTermMoveRequest struct {
+	PlanTermID *uint `json:"plan_term_id"` // nilの場合は学期の割り当てを外す
+}
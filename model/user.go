@@ -1,14 +1,23 @@
 package model
 
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator" // コメントの編集履歴の閲覧など、投稿の監視ができる
+	RoleAdmin     = "admin"
+)
+
 type User struct {
-	ID           uint   `json:"id" gorm:"primaryKey"`
-	Email        string `json:"email" gorm:"unique"`
-	Password     string `json:"password"`
-	Name         string `json:"name"`
-	UniversityID *uint  `json:"university_id"`
-	FacultyID    *uint  `json:"faculty_id"`
-	DepartmentID *uint  `json:"department_id"`
-	Grade        *uint  `json:"grade"`
+	ID           uint    `json:"id" gorm:"primaryKey"`
+	Email        string  `json:"email" gorm:"unique"`
+	Password     string  `json:"password"`
+	Name         string  `json:"name"`
+	Handle       *string `json:"handle" gorm:"uniqueIndex"` // @で言及するときに使う公開ID。小文字・半角に揃えて保存する
+	UniversityID *uint   `json:"university_id"`
+	FacultyID    *uint   `json:"faculty_id"`
+	DepartmentID *uint   `json:"department_id"`
+	Grade        *uint   `json:"grade"`
+	EntryYear    *uint   `json:"entry_year"` // 入学年度
+	Role         string  `json:"role" gorm:"not null;default:user"`
 
 	University *University    `json:"university" gorm:"foreignKey:UniversityID"`
 	Faculty    *Faculty       `json:"faculty" gorm:"foreignKey:FacultyID"`
@@ -21,6 +30,7 @@ type User struct {
 type UserResponse struct {
 	ID         uint        `json:"id" gorm:"primaryKey"`
 	Email      string      `json:"email" gorm:"unique"`
+	Handle     *string     `json:"handle"`
 	University *University `json:"university"`
 	Faculty    *Faculty    `json:"faculty"`
 	Department *Department `json:"department"`
@@ -30,3 +40,7 @@ type GoogleUserInfo struct {
 	Email string `json:"email"`
 	Name  string `json:"name"`
 }
+
+type UserHandleRequest struct {
+	Handle string `json:"handle"`
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhookで送るイベントの種類
+const (
+	WebhookEventPlanCreated     = "plan.created" // コピーで作成した場合も送る
+	WebhookEventPlanUpdated     = "plan.updated"
+	WebhookEventCommentCreated  = "comment.created" // 確認待ちのコメントは公開された時点で送る
+	WebhookEventFavoriteToggled = "favorite.toggled"
+)
+
+var WebhookEventTypes = []string{
+	WebhookEventPlanCreated,
+	WebhookEventPlanUpdated,
+	WebhookEventCommentCreated,
+	WebhookEventFavoriteToggled,
+}
+
+// 送信結果
+const (
+	WebhookDeliveryPending   = "pending" // 送信待ち・再送待ち
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed" // 再送の上限に達した
+)
+
+// 通常は自分の計画のイベントのみ受け取る。
+// AllPlansは管理者のみ設定でき、すべての公開計画のイベントを受け取る
+type Webhook struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	URL       string    `json:"url" gorm:"not null"`
+	Secret    string    `json:"-" gorm:"not null"` // 署名に使う。作成時に一度だけ返す
+	Events    []string  `json:"events" gorm:"type:jsonb;not null;serializer:json"`
+	AllPlans  bool      `json:"all_plans" gorm:"not null;default:false;index"`
+	Active    bool      `json:"active" gorm:"not null;default:true"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	User       User              `json:"user" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Deliveries []WebhookDelivery `json:"deliveries" gorm:"foreignKey:WebhookID;constraint:OnDelete:CASCADE"`
+}
+
+// 送信1件ごとの記録。再送のたびにAttemptsと結果を更新する
+type WebhookDelivery struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	WebhookID      uint       `json:"webhook_id" gorm:"not null;index"`
+	EventType      string     `json:"event_type" gorm:"not null"`
+	Payload        string     `json:"payload" gorm:"type:jsonb;not null"`
+	Status         string     `json:"status" gorm:"not null;default:pending;index:idx_webhook_deliveries_due"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"index:idx_webhook_deliveries_due"`
+	ResponseStatus *int       `json:"response_status"`
+	ResponseBody   *string    `json:"response_body"` // 先頭の一部のみ保存する
+	Error          *string    `json:"error"`
+	RedeliveryOfID *uint      `json:"redelivery_of_id"` // 手動で再送した場合の元の送信
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	Webhook Webhook `json:"webhook" gorm:"foreignKey:WebhookID"`
+}
+
+type WebhookRequest struct {
+	URL      string   `json:"url"`
+	Events   []string `json:"events"`
+	AllPlans bool     `json:"all_plans"`
+	Active   *bool    `json:"active"` // 省略した場合は有効にする
+}
+
+type WebhookResponse struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	AllPlans  bool      `json:"all_plans"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"secret,omitempty"` // 作成時のみ
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uint            `json:"id"`
+	WebhookID      uint            `json:"webhook_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"` // 送信待ちの場合のみ
+	ResponseStatus *int            `json:"response_status"`
+	ResponseBody   *string         `json:"response_body"`
+	Error          *string         `json:"error"`
+	RedeliveryOfID *uint           `json:"redelivery_of_id"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// 受信側に送る本文
+type WebhookPayload struct {
+	Type      string      `json:"type"`
+	PlanID    uint        `json:"plan_id"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+type FavoriteToggledEvent struct {
+	PlanID        uint  `json:"plan_id"`
+	UserID        uint  `json:"user_id"`
+	Favorited     bool  `json:"favorited"`
+	FavoriteCount int64 `json:"favorite_count"`
+}
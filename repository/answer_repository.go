@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"backend/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IAnswerRepository interface {
+	GetAnswersByPostID(answers *[]model.Answer, postId uint) error
+	GetAnswerByID(answer *model.Answer, answerId uint) error
+	CreateAnswer(answer *model.Answer) error
+	DeleteAnswer(answerId uint, authorId uint) error
+	SetAcceptedAnswer(postId uint, answerId *uint) error
+	SaveVote(vote *model.AnswerVote) error
+	DeleteVote(answerId uint, userId uint) error
+	GetUnansweredPosts(posts *[]model.Post, userId uint, universityId *uint, departmentId *uint, offset int, limit int) error
+}
+
+type answerRepository struct {
+	db *gorm.DB
+}
+
+func NewAnswerRepository(db *gorm.DB) IAnswerRepository {
+	return &answerRepository{db: db}
+}
+
+func (ar *answerRepository) GetAnswersByPostID(answers *[]model.Answer, postId uint) error {
+	return ar.db.Preload("Votes").Where("post_id = ?", postId).Order("created_at, id").Find(answers).Error
+}
+
+func (ar *answerRepository) GetAnswerByID(answer *model.Answer, answerId uint) error {
+	return ar.db.Preload("Votes").Where("id = ?", answerId).First(answer).Error
+}
+
+func (ar *answerRepository) CreateAnswer(answer *model.Answer) error {
+	return ar.db.Create(answer).Error
+}
+
+// ベストアンサーに選ばれていた場合は選択を解除してから削除する
+func (ar *answerRepository) DeleteAnswer(answerId uint, authorId uint) error {
+	return ar.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Post{}).Where("accepted_answer_id = ?", answerId).
+			Update("accepted_answer_id", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ? AND author_id = ?", answerId, authorId).Delete(&model.Answer{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (ar *answerRepository) SetAcceptedAnswer(postId uint, answerId *uint) error {
+	return ar.db.Model(&model.Post{}).Where("id = ?", postId).Update("accepted_answer_id", answerId).Error
+}
+
+// すでに評価している場合は値を置き換える
+func (ar *answerRepository) SaveVote(vote *model.AnswerVote) error {
+	return ar.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "answer_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(vote).Error
+}
+
+func (ar *answerRepository) DeleteVote(answerId uint, userId uint) error {
+	return ar.db.Where("answer_id = ? AND user_id = ?", answerId, userId).Delete(&model.AnswerVote{}).Error
+}
+
+// 回答がまだない質問を新しい順に返す。大学・学科は質問者の所属で絞り込む。
+// 非公開の計画への質問は計画の作成者にのみ返す
+func (ar *answerRepository) GetUnansweredPosts(posts *[]model.Post, userId uint, universityId *uint, departmentId *uint, offset int, limit int) error {
+	query := ar.db.Preload("Mentions").
+		Preload("Reactions").
+		Joins("LEFT JOIN plans ON plans.id = posts.plan_id").
+		Where("NOT EXISTS (SELECT 1 FROM answers WHERE answers.post_id = posts.id)").
+		Where("plans.id IS NULL OR plans.visibility = ? OR plans.user_id = ?", model.PlanVisibilityPublic, userId)
+	if universityId != nil || departmentId != nil {
+		query = query.Joins("JOIN users ON users.id = posts.author_id")
+		if universityId != nil {
+			query = query.Where("users.university_id = ?", *universityId)
+		}
+		if departmentId != nil {
+			query = query.Where("users.department_id = ?", *departmentId)
+		}
+	}
+	return query.Order("posts.created_at desc, posts.id desc").
+		Offset(offset).
+		Limit(limit).
+		Find(posts).Error
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"backend/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ICalendarRepository interface {
+	GetPeriodTimes(periods *[]model.PeriodTime, universityId uint) error
+	ReplacePeriodTimes(universityId uint, periods *[]model.PeriodTime) error
+	GetHolidays(holidays *[]model.AcademicHoliday, universityId uint, from time.Time, to time.Time) error
+	CreateHoliday(holiday *model.AcademicHoliday) error
+	DeleteHolidayByID(holidayId uint) error
+	CreateCalendarFeed(feed *model.CalendarFeed) error
+	GetActiveCalendarFeedByTokenHash(feed *model.CalendarFeed, tokenHash string) error
+	RevokeCalendarFeeds(planId uint) error
+}
+
+type calendarRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepository(db *gorm.DB) ICalendarRepository {
+	return &calendarRepository{db: db}
+}
+
+func (cr *calendarRepository) GetPeriodTimes(periods *[]model.PeriodTime, universityId uint) error {
+	return cr.db.Where("university_id = ?", universityId).Order("period").Find(periods).Error
+}
+
+func (cr *calendarRepository) ReplacePeriodTimes(universityId uint, periods *[]model.PeriodTime) error {
+	return cr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("university_id = ?", universityId).Delete(&model.PeriodTime{}).Error; err != nil {
+			return err
+		}
+		if len(*periods) == 0 {
+			return nil
+		}
+		return tx.Create(periods).Error
+	})
+}
+
+func (cr *calendarRepository) GetHolidays(holidays *[]model.AcademicHoliday, universityId uint, from time.Time, to time.Time) error {
+	return cr.db.Where("university_id = ? AND date BETWEEN ? AND ?", universityId, from, to).
+		Order("date").
+		Find(holidays).Error
+}
+
+func (cr *calendarRepository) CreateHoliday(holiday *model.AcademicHoliday) error {
+	return cr.db.Create(holiday).Error
+}
+
+func (cr *calendarRepository) DeleteHolidayByID(holidayId uint) error {
+	result := cr.db.Where("id = ?", holidayId).Delete(&model.AcademicHoliday{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected < 1 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (cr *calendarRepository) CreateCalendarFeed(feed *model.CalendarFeed) error {
+	return cr.db.Omit("Plan").Create(feed).Error
+}
+
+func (cr *calendarRepository) GetActiveCalendarFeedByTokenHash(feed *model.CalendarFeed, tokenHash string) error {
+	return cr.db.Where("token_hash = ? AND revoked_at IS NULL", tokenHash).First(feed).Error
+}
+
+func (cr *calendarRepository) RevokeCalendarFeeds(planId uint) error {
+	return cr.db.Model(&model.CalendarFeed{}).
+		Where("plan_id = ? AND revoked_at IS NULL", planId).
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"backend/model"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ICatalogRepository interface {
+	SearchCatalogCourses(courses *[]model.CatalogCourse, params model.CatalogCourseSearchParams, offset int, limit int) error
+	GetCatalogCourseByID(course *model.CatalogCourse, catalogCourseId uint) error
+	GetCatalogCoursesByIDs(courses *[]model.CatalogCourse, catalogCourseIds []uint) error
+	GetCatalogCoursesByCodes(courses *[]model.CatalogCourse, universityId uint, codes []string) error
+	UpsertCatalogCourses(courses []model.CatalogCourse) error
+	CreateCatalogCourse(course *model.CatalogCourse) error
+	UpdateCatalogCourse(course *model.CatalogCourse, catalogCourseId uint) error
+	DeleteCatalogCourseByID(catalogCourseId uint) error
+}
+
+type catalogRepository struct {
+	db *gorm.DB
+}
+
+func NewCatalogRepository(db *gorm.DB) ICatalogRepository {
+	return &catalogRepository{db: db}
+}
+
+func (cr *catalogRepository) SearchCatalogCourses(courses *[]model.CatalogCourse, params model.CatalogCourseSearchParams, offset int, limit int) error {
+	query := cr.db.Preload("Slots")
+	if params.UniversityID != 0 {
+		query = query.Where("university_id = ?", params.UniversityID)
+	}
+	if params.Query != "" {
+		like := "%" + escapeLike(params.Query) + "%"
+		query = query.Where(`name ILIKE ? ESCAPE '\' OR code ILIKE ? ESCAPE '\' OR instructor ILIKE ? ESCAPE '\'`, like, like, like)
+	}
+	if params.Term != "" {
+		query = query.Where("term = ?", params.Term)
+	}
+	if params.DayOfWeek != nil || params.Period != nil {
+		slots := cr.db.Model(&model.CatalogCourseSlot{}).
+			Select("1").
+			Where("catalog_course_slots.catalog_course_id = catalog_courses.id")
+		if params.DayOfWeek != nil {
+			slots = slots.Where("day_of_week = ?", *params.DayOfWeek)
+		}
+		if params.Period != nil {
+			slots = slots.Where("period = ?", *params.Period)
+		}
+		query = query.Where("EXISTS (?)", slots)
+	}
+	return query.Order("code").
+		Offset(offset).
+		Limit(limit).
+		Find(courses).Error
+}
+
+func (cr *catalogRepository) GetCatalogCourseByID(course *model.CatalogCourse, catalogCourseId uint) error {
+	return cr.db.Preload("Slots").Where("id = ?", catalogCourseId).First(course).Error
+}
+
+func (cr *catalogRepository) GetCatalogCoursesByIDs(courses *[]model.CatalogCourse, catalogCourseIds []uint) error {
+	if len(catalogCourseIds) == 0 {
+		return nil
+	}
+	return cr.db.Preload("Slots").Where("id IN ?", catalogCourseIds).Find(courses).Error
+}
+
+func (cr *catalogRepository) GetCatalogCoursesByCodes(courses *[]model.CatalogCourse, universityId uint, codes []string) error {
+	if len(codes) == 0 {
+		return nil
+	}
+	return cr.db.Preload("Slots").
+		Where("university_id = ? AND code IN ?", universityId, codes).
+		Find(courses).Error
+}
+
+// 大学と科目コードをキーに一括で登録・更新する。1件でも失敗した場合は全てロールバックする
+func (cr *catalogRepository) UpsertCatalogCourses(courses []model.CatalogCourse) error {
+	return cr.db.Transaction(func(tx *gorm.DB) error {
+		for i := range courses {
+			slots := courses[i].Slots
+			courses[i].Slots = nil
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "university_id"}, {Name: "code"}},
+				DoUpdates: clause.AssignmentColumns([]string{"name", "instructor", "credits", "term", "updated_at"}),
+			}).Create(&courses[i]).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("catalog_course_id = ?", courses[i].ID).Delete(&model.CatalogCourseSlot{}).Error; err != nil {
+				return err
+			}
+			for j := range slots {
+				slots[j].ID = 0
+				slots[j].CatalogCourseID = courses[i].ID
+			}
+			if len(slots) > 0 {
+				if err := tx.Create(&slots).Error; err != nil {
+					return err
+				}
+			}
+			courses[i].Slots = slots
+		}
+		return nil
+	})
+}
+
+func (cr *catalogRepository) CreateCatalogCourse(course *model.CatalogCourse) error {
+	return cr.db.Create(course).Error
+}
+
+// 時限は丸ごと置き換える
+func (cr *catalogRepository) UpdateCatalogCourse(course *model.CatalogCourse, catalogCourseId uint) error {
+	return cr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.CatalogCourse{}).
+			Where("id = ?", catalogCourseId).
+			Updates(map[string]interface{}{
+				"university_id": course.UniversityID,
+				"code":          course.Code,
+				"name":          course.Name,
+				"instructor":    course.Instructor,
+				"credits":       course.Credits,
+				"term":          course.Term,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected < 1 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("catalog_course_id = ?", catalogCourseId).Delete(&model.CatalogCourseSlot{}).Error; err != nil {
+			return err
+		}
+		for i := range course.Slots {
+			course.Slots[i].ID = 0
+			course.Slots[i].CatalogCourseID = catalogCourseId
+		}
+		if len(course.Slots) > 0 {
+			if err := tx.Create(&course.Slots).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Preload("Slots").Where("id = ?", catalogCourseId).First(course).Error
+	})
+}
+
+func (cr *catalogRepository) DeleteCatalogCourseByID(catalogCourseId uint) error {
+	return cr.db.Transaction(func(tx *gorm.DB) error {
+		// 計画側の科目は自由入力として残す
+		if err := tx.Model(&model.Course{}).
+			Where("catalog_course_id = ?", catalogCourseId).
+			Update("catalog_course_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("catalog_course_id = ?", catalogCourseId).Delete(&model.CatalogCourseSlot{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("catalog_course_id = ?", catalogCourseId).Delete(&model.CourseReview{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", catalogCourseId).Delete(&model.CatalogCourse{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected < 1 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// 検索語の % と _ をワイルドカードではなく文字として扱う
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -2,13 +2,19 @@ package repository
 
 import (
 	"backend/model"
+	"fmt"
 
 	"gorm.io/gorm"
 )
 
 type ICommentRepository interface {
 	CreateComment(comment *model.Comment) error
+	GetCommentByID(comment *model.Comment, commentID uint) error
+	UpdateCommentContent(comment *model.Comment, content string, mentions []model.Mention) error
+	GetRevisions(revisions *[]model.CommentRevision, commentID uint) error
 	GetCommentsByPlanID(planID uint) ([]model.Comment, error)
+	GetReplies(comments *[]model.Comment, parentID uint, levels int, offset int, limit int) error
+	GetDescendants(comments *[]model.Comment, commentIDs []uint) error
 	GetCommentsByUserID(userID uint) ([]model.Comment, error)
 	DeleteComment(commentID uint, userID *uint) error
 }
@@ -25,18 +31,109 @@ func (cr *commentRepository) CreateComment(comment *model.Comment) error {
 	return cr.db.Create(comment).Error
 }
 
+func (cr *commentRepository) GetCommentByID(comment *model.Comment, commentID uint) error {
+	return cr.db.Preload("Mentions").Preload("Reactions").Where("id = ?", commentID).First(comment).Error
+}
+
+// 編集前の本文を履歴に残してから本文と公開状態を書き換え、言及を新しい本文のものに置き換える
+func (cr *commentRepository) UpdateCommentContent(comment *model.Comment, content string, mentions []model.Mention) error {
+	return cr.db.Transaction(func(tx *gorm.DB) error {
+		writtenAt := comment.CreatedAt
+		if comment.EditedAt != nil {
+			writtenAt = *comment.EditedAt
+		}
+		revision := model.CommentRevision{
+			CommentID: comment.ID,
+			Content:   comment.Content,
+			WrittenAt: writtenAt,
+		}
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+		editedAt := revision.CreatedAt
+		if err := tx.Model(comment).Updates(map[string]interface{}{
+			"content":   content,
+			"status":    comment.Status,
+			"edited_at": editedAt,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id = ?", comment.ID).Delete(&model.Mention{}).Error; err != nil {
+			return err
+		}
+		for i := range mentions {
+			mentions[i].CommentID = &comment.ID
+		}
+		if len(mentions) > 0 {
+			if err := tx.Create(&mentions).Error; err != nil {
+				return err
+			}
+		}
+		comment.Content = content
+		comment.EditedAt = &editedAt
+		comment.Mentions = mentions
+		return nil
+	})
+}
+
+func (cr *commentRepository) GetRevisions(revisions *[]model.CommentRevision, commentID uint) error {
+	return cr.db.Where("comment_id = ?", commentID).Order("created_at, id").Find(revisions).Error
+}
+
 func (cr *commentRepository) GetCommentsByPlanID(planID uint) ([]model.Comment, error) {
 	var comments []model.Comment
-	err := cr.db.Preload("User").Where("plan_id = ?", planID).Order("created_at desc").Find(&comments).Error
+	err := cr.db.Preload("User").Preload("Mentions").Preload("Reactions").Where("plan_id = ?", planID).Order("created_at desc").Find(&comments).Error
 	return comments, err
 }
 
+// 直下の返信を古い順に返す。公開されていない返信は、levels階層下までに公開中の返信がある場合のみ含める
+func (cr *commentRepository) GetReplies(comments *[]model.Comment, parentID uint, levels int, offset int, limit int) error {
+	return cr.db.Preload("User").Preload("Mentions").Preload("Reactions").
+		Where("comments.parent_id = ?", parentID).
+		Where(visibleThreadCondition("comments", levels)).
+		Order("comments.created_at, comments.id").
+		Offset(offset).
+		Limit(limit).
+		Find(comments).Error
+}
+
+// 指定したコメントへの返信を、返信の返信も含めてすべて返す
+func (cr *commentRepository) GetDescendants(comments *[]model.Comment, commentIDs []uint) error {
+	parentIDs := commentIDs
+	for len(parentIDs) > 0 {
+		var children []model.Comment
+		if err := cr.db.Preload("User").Preload("Mentions").Preload("Reactions").
+			Where("parent_id IN ?", parentIDs).
+			Find(&children).Error; err != nil {
+			return err
+		}
+		parentIDs = make([]uint, 0, len(children))
+		for _, child := range children {
+			parentIDs = append(parentIDs, child.ID)
+		}
+		*comments = append(*comments, children...)
+	}
+	return nil
+}
+
+// 公開中、またはlevels階層下までに公開中の返信があるコメントの条件
+func visibleThreadCondition(table string, levels int) string {
+	condition := fmt.Sprintf("%s.status = '%s'", table, model.CommentStatusVisible)
+	if levels <= 0 {
+		return condition
+	}
+	child := fmt.Sprintf("replies%d", levels)
+	return fmt.Sprintf("(%s OR EXISTS (SELECT 1 FROM comments AS %s WHERE %s.parent_id = %s.id AND %s))",
+		condition, child, child, table, visibleThreadCondition(child, levels-1))
+}
+
 func (cr *commentRepository) GetCommentsByUserID(userID uint) ([]model.Comment, error) {
 	var comments []model.Comment
-	err := cr.db.Preload("Plan").Where("user_id = ?", userID).Order("created_at desc").Find(&comments).Error
+	err := cr.db.Preload("Plan").Preload("Mentions").Preload("Reactions").Where("user_id = ?", userID).Order("created_at desc").Find(&comments).Error
 	return comments, err
 }
 
+// 返信もまとめて削除される
 func (cr *commentRepository) DeleteComment(commentID uint, userID *uint) error {
 	query := cr.db.Where("id = ?", commentID)
 	if userID != nil {
@@ -8,9 +8,11 @@ import (
 
 type ICourseRepository interface {
 	GetAllCourses(courses *[]model.Course, planId uint) error
+	GetCourseByID(course *model.Course, courseId uint) error
 	CreateCourses(courses *[]model.Course) error
 	UpdateCourse(course *model.Course, courseId int) error
 	DeleteCourseByID(courseId uint) error
+	UpdateCourseTerm(courseId uint, planTermId *uint) error
 }
 
 type courseRepository struct {
@@ -22,12 +24,16 @@ func NewCourseRepository(db *gorm.DB) ICourseRepository {
 }
 
 func (cr *courseRepository) GetAllCourses(courses *[]model.Course, planId uint) error {
-	if err := cr.db.Where("plan_id = ?", planId).Find(courses).Error; err != nil {
+	if err := cr.db.Preload("Slots").Where("plan_id = ?", planId).Find(courses).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
+func (cr *courseRepository) GetCourseByID(course *model.Course, courseId uint) error {
+	return cr.db.Preload("Slots").Where("id = ?", courseId).First(course).Error
+}
+
 func (cr *courseRepository) CreateCourses(courses *[]model.Course) error {
 	if err := cr.db.Create(courses).Error; err != nil {
 		return err
@@ -35,15 +41,51 @@ func (cr *courseRepository) CreateCourses(courses *[]model.Course) error {
 	return nil
 }
 
+// 時限が指定された場合は丸ごと置き換える。所属する計画・学期は更新しない
 func (cr *courseRepository) UpdateCourse(course *model.Course, courseId int) error {
-	if err := cr.db.Model(&model.Course{}).Where("id = ?", courseId).Updates(course).Error; err != nil {
-		return err
-	}
-	return nil
+	return cr.db.Transaction(func(tx *gorm.DB) error {
+		slots := course.Slots
+		course.Slots = nil
+		if err := tx.Model(&model.Course{}).Where("id = ?", courseId).Omit("PlanID", "PlanTermID").Updates(course).Error; err != nil {
+			return err
+		}
+		if slots == nil {
+			return tx.Preload("Slots").Where("id = ?", courseId).First(course).Error
+		}
+		if err := tx.Where("course_id = ?", courseId).Delete(&model.CourseSlot{}).Error; err != nil {
+			return err
+		}
+		for i := range slots {
+			slots[i].ID = 0
+			slots[i].CourseID = uint(courseId)
+		}
+		if len(slots) > 0 {
+			if err := tx.Create(&slots).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Preload("Slots").Where("id = ?", courseId).First(course).Error
+	})
 }
 
 func (cr *courseRepository) DeleteCourseByID(courseId uint) error {
-	result := cr.db.Where("id = ?", courseId).Delete(&model.Course{})
+	return cr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("course_id = ?", courseId).Delete(&model.CourseSlot{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", courseId).Delete(&model.Course{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected < 1 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (cr *courseRepository) UpdateCourseTerm(courseId uint, planTermId *uint) error {
+	result := cr.db.Model(&model.Course{}).Where("id = ?", courseId).Update("plan_term_id", planTermId)
 	if result.Error != nil {
 		return result.Error
 	}
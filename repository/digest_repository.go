@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"backend/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IDigestRepository interface {
+	GetSetting(setting *model.DigestSetting, userId uint) error
+	SaveSetting(setting *model.DigestSetting) error
+	DisableDigest(userId uint) error
+	GetDueSettings(settings *[]model.DigestSetting, frequency string, sentBefore time.Time) error
+	ClaimDue(settingId uint, sentBefore time.Time, sentAt time.Time) (bool, error)
+	RestoreLastSent(settingId uint, lastSentAt *time.Time) error
+	GetPlanActivities(activities *[]model.DigestPlanActivity, userId uint, since time.Time) error
+	GetDepartmentPlans(plans *[]model.Plan, departmentId uint, excludeUserId uint, since time.Time, limit int) error
+}
+
+type digestRepository struct {
+	db *gorm.DB
+}
+
+func NewDigestRepository(db *gorm.DB) IDigestRepository {
+	return &digestRepository{db: db}
+}
+
+func (dr *digestRepository) GetSetting(setting *model.DigestSetting, userId uint) error {
+	return dr.db.Where("user_id = ?", userId).First(setting).Error
+}
+
+// ユーザーごとに1件なので、既にあれば頻度と言語だけ更新する
+func (dr *digestRepository) SaveSetting(setting *model.DigestSetting) error {
+	return dr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"frequency", "locale", "updated_at"}),
+	}).Create(setting).Error
+}
+
+// 設定がないユーザーにはもともと送らないので、該当がなくてもエラーにしない
+func (dr *digestRepository) DisableDigest(userId uint) error {
+	return dr.db.Model(&model.DigestSetting{}).
+		Where("user_id = ?", userId).
+		Update("frequency", model.DigestFrequencyOff).Error
+}
+
+func (dr *digestRepository) GetDueSettings(settings *[]model.DigestSetting, frequency string, sentBefore time.Time) error {
+	return dr.db.Preload("User").
+		Where("frequency = ?", frequency).
+		Where("last_sent_at IS NULL OR last_sent_at < ?", sentBefore).
+		Order("id").
+		Find(settings).Error
+}
+
+// 送信前に送信済みにする。複数のインスタンスで同じユーザーに送らないよう、更新できた場合のみtrueを返す
+func (dr *digestRepository) ClaimDue(settingId uint, sentBefore time.Time, sentAt time.Time) (bool, error) {
+	result := dr.db.Model(&model.DigestSetting{}).
+		Where("id = ?", settingId).
+		Where("last_sent_at IS NULL OR last_sent_at < ?", sentBefore).
+		Update("last_sent_at", sentAt)
+	return result.RowsAffected > 0, result.Error
+}
+
+// 送信に失敗した場合に次回の実行で再送されるよう戻す
+func (dr *digestRepository) RestoreLastSent(settingId uint, lastSentAt *time.Time) error {
+	return dr.db.Model(&model.DigestSetting{}).Where("id = ?", settingId).Update("last_sent_at", lastSentAt).Error
+}
+
+// 作成者自身の操作と、公開中でないコメントは数えない。反応のなかった計画は返さない
+func (dr *digestRepository) GetPlanActivities(activities *[]model.DigestPlanActivity, userId uint, since time.Time) error {
+	return dr.db.Raw(`
+SELECT * FROM (
+	SELECT plans.id AS plan_id, plans.title,
+		(SELECT COUNT(*) FROM comments
+			WHERE comments.plan_id = plans.id AND comments.created_at >= @since AND comments.status = @visible
+				AND (comments.user_id IS NULL OR comments.user_id <> plans.user_id)) AS comments,
+		(SELECT COUNT(*) FROM favorite_plans
+			WHERE favorite_plans.plan_id = plans.id AND favorite_plans.created_at >= @since
+				AND favorite_plans.user_id <> plans.user_id) AS favorites,
+		(SELECT COUNT(*) FROM posts
+			WHERE posts.plan_id = plans.id AND posts.created_at >= @since
+				AND posts.author_id <> plans.user_id) AS questions
+	FROM plans
+	WHERE plans.user_id = @user
+) AS activities
+WHERE comments + favorites + questions > 0
+ORDER BY plan_id`,
+		map[string]interface{}{"since": since, "visible": model.CommentStatusVisible, "user": userId},
+	).Scan(activities).Error
+}
+
+// 同じ学科のユーザーが期間内に作成した公開の計画を新しい順に返す
+func (dr *digestRepository) GetDepartmentPlans(plans *[]model.Plan, departmentId uint, excludeUserId uint, since time.Time, limit int) error {
+	return dr.db.
+		Joins("JOIN users ON users.id = plans.user_id").
+		Where("users.department_id = ? AND plans.user_id <> ?", departmentId, excludeUserId).
+		Where("plans.visibility = ? AND plans.created_at >= ?", model.PlanVisibilityPublic, since).
+		Order("plans.created_at desc, plans.id desc").
+		Limit(limit).
+		Find(plans).Error
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"backend/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IFavoriteRepository interface {
+	SaveFavorite(favorite *model.FavoritePlan) (bool, error)
+	DeleteFavorite(userId uint, planId uint) (bool, error)
+	GetCollectionsByUserID(collections *[]model.FavoriteCollection, userId uint) error
+	GetCollectionPlanCounts(collectionIds []uint) (map[uint]int64, error)
+	GetCollectionByID(collection *model.FavoriteCollection, collectionId uint) error
+	ExistsCollectionName(userId uint, name string, excludeId uint) (bool, error)
+	CreateCollection(collection *model.FavoriteCollection) error
+	UpdateCollection(collection *model.FavoriteCollection) error
+	DeleteCollectionByID(collectionId uint) error
+	GetCollectionPlans(favorites *[]model.FavoritePlan, collectionId uint, userId uint, offset int, limit int) error
+	AddToCollection(collectionId uint, favorite *model.FavoritePlan) (bool, error)
+	RemoveFromCollection(collectionId uint, userId uint, planId uint) error
+}
+
+type favoriteRepository struct {
+	db *gorm.DB
+}
+
+func NewFavoriteRepository(db *gorm.DB) IFavoriteRepository {
+	return &favoriteRepository{db: db}
+}
+
+// 既にお気に入りの場合は何もしない。同時に呼ばれても一意制約で重複しない。
+// 新しくお気に入りに追加した場合はtrueを返す
+func (fr *favoriteRepository) SaveFavorite(favorite *model.FavoritePlan) (bool, error) {
+	created := false
+	err := fr.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		created, err = saveFavorite(tx, favorite)
+		return err
+	})
+	return created && err == nil, err
+}
+
+// 削除した場合はtrueを返す
+func (fr *favoriteRepository) DeleteFavorite(userId uint, planId uint) (bool, error) {
+	result := fr.db.Where("user_id = ? AND plan_id = ?", userId, planId).Delete(&model.FavoritePlan{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (fr *favoriteRepository) GetCollectionsByUserID(collections *[]model.FavoriteCollection, userId uint) error {
+	return fr.db.Where("user_id = ?", userId).Order("name").Find(collections).Error
+}
+
+func (fr *favoriteRepository) GetCollectionPlanCounts(collectionIds []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(collectionIds))
+	if len(collectionIds) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		CollectionID uint
+		Count        int64
+	}
+	if err := fr.db.Model(&model.FavoriteCollectionItem{}).
+		Select("collection_id, COUNT(*) AS count").
+		Where("collection_id IN ?", collectionIds).
+		Group("collection_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.CollectionID] = row.Count
+	}
+	return counts, nil
+}
+
+func (fr *favoriteRepository) GetCollectionByID(collection *model.FavoriteCollection, collectionId uint) error {
+	return fr.db.Where("id = ?", collectionId).First(collection).Error
+}
+
+func (fr *favoriteRepository) ExistsCollectionName(userId uint, name string, excludeId uint) (bool, error) {
+	var count int64
+	err := fr.db.Model(&model.FavoriteCollection{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userId, name, excludeId).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (fr *favoriteRepository) CreateCollection(collection *model.FavoriteCollection) error {
+	return fr.db.Create(collection).Error
+}
+
+func (fr *favoriteRepository) UpdateCollection(collection *model.FavoriteCollection) error {
+	return fr.db.Model(collection).Update("name", collection.Name).Error
+}
+
+// コレクションを消してもお気に入り自体は残す
+func (fr *favoriteRepository) DeleteCollectionByID(collectionId uint) error {
+	result := fr.db.Where("id = ?", collectionId).Delete(&model.FavoriteCollection{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected < 1 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// コレクションに追加した新しい順。非公開になった他人の計画は含めない
+func (fr *favoriteRepository) GetCollectionPlans(favorites *[]model.FavoritePlan, collectionId uint, userId uint, offset int, limit int) error {
+	return fr.db.Preload("Plan").
+		Preload("Plan.User").
+		Preload("Plan.User.University").
+		Preload("Plan.User.Faculty").
+		Preload("Plan.User.Department").
+		Preload("CollectionItems").
+		Joins("JOIN favorite_collection_items ON favorite_collection_items.favorite_plan_id = favorite_plans.id").
+		Joins("JOIN plans ON plans.id = favorite_plans.plan_id").
+		Where("favorite_collection_items.collection_id = ? AND favorite_plans.user_id = ?", collectionId, userId).
+		Where("plans.visibility = ? OR plans.user_id = ?", model.PlanVisibilityPublic, userId).
+		Order("favorite_collection_items.created_at desc, favorite_collection_items.id desc").
+		Offset(offset).
+		Limit(limit).
+		Find(favorites).Error
+}
+
+// お気に入りでない計画はお気に入りに追加してからコレクションに入れる。
+// 新しくお気に入りに追加した場合はtrueを返す
+func (fr *favoriteRepository) AddToCollection(collectionId uint, favorite *model.FavoritePlan) (bool, error) {
+	created := false
+	err := fr.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if created, err = saveFavorite(tx, favorite); err != nil {
+			return err
+		}
+		item := model.FavoriteCollectionItem{CollectionID: collectionId, FavoritePlanID: favorite.ID}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "collection_id"}, {Name: "favorite_plan_id"}},
+			DoNothing: true,
+		}).Create(&item).Error
+	})
+	return created && err == nil, err
+}
+
+func (fr *favoriteRepository) RemoveFromCollection(collectionId uint, userId uint, planId uint) error {
+	return fr.db.Where("collection_id = ? AND favorite_plan_id IN (?)", collectionId,
+		fr.db.Model(&model.FavoritePlan{}).Select("id").Where("user_id = ? AND plan_id = ?", userId, planId),
+	).Delete(&model.FavoriteCollectionItem{}).Error
+}
+
+// favoriteのNoteがnilでなければメモも更新し、保存後の行をfavoriteに読み込む。
+// 新しく作成した場合はtrueを返す
+func saveFavorite(tx *gorm.DB, favorite *model.FavoritePlan) (bool, error) {
+	note := favorite.Note
+	result := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "plan_id"}},
+		DoNothing: true,
+	}).Omit(clause.Associations).Create(favorite)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	query := tx.Model(&model.FavoritePlan{}).Where("user_id = ? AND plan_id = ?", favorite.UserID, favorite.PlanID)
+	if note != nil {
+		if err := query.Update("note", note).Error; err != nil {
+			return false, err
+		}
+	}
+	err := tx.Where("user_id = ? AND plan_id = ?", favorite.UserID, favorite.PlanID).
+		Preload("CollectionItems").
+		First(favorite).Error
+	return result.RowsAffected > 0, err
+}
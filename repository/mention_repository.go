@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"backend/model"
+
+	"gorm.io/gorm"
+)
+
+type IMentionRepository interface {
+	GetMentionsByUserID(mentions *[]model.Mention, userId uint, offset int, limit int) error
+	GetCommentsByIDs(comments *[]model.Comment, commentIds []uint) error
+	GetPostsByIDs(posts *[]model.Post, postIds []uint) error
+}
+
+type mentionRepository struct {
+	db *gorm.DB
+}
+
+func NewMentionRepository(db *gorm.DB) IMentionRepository {
+	return &mentionRepository{db: db}
+}
+
+// 公開中のコメントと、閲覧できる計画への投稿での言及のみを新しい順に返す
+func (mr *mentionRepository) GetMentionsByUserID(mentions *[]model.Mention, userId uint, offset int, limit int) error {
+	return mr.db.
+		Joins("LEFT JOIN comments ON comments.id = mentions.comment_id").
+		Joins("LEFT JOIN posts ON posts.id = mentions.post_id").
+		Joins("LEFT JOIN plans ON plans.id = COALESCE(comments.plan_id, posts.plan_id)").
+		Where("mentions.user_id = ?", userId).
+		Where("mentions.comment_id IS NULL OR comments.status = ?", model.CommentStatusVisible).
+		Where("plans.id IS NULL OR plans.visibility <> ? OR plans.user_id = ?", model.PlanVisibilityPrivate, userId).
+		Order("mentions.created_at desc, mentions.id desc").
+		Offset(offset).
+		Limit(limit).
+		Find(mentions).Error
+}
+
+func (mr *mentionRepository) GetCommentsByIDs(comments *[]model.Comment, commentIds []uint) error {
+	if len(commentIds) == 0 {
+		return nil
+	}
+	return mr.db.Preload("Mentions").Preload("Reactions").Where("id IN ?", commentIds).Find(comments).Error
+}
+
+func (mr *mentionRepository) GetPostsByIDs(posts *[]model.Post, postIds []uint) error {
+	if len(postIds) == 0 {
+		return nil
+	}
+	return mr.db.Preload("Mentions").Preload("Reactions").Preload("Answers", selectAnswerIDs).Where("id IN ?", postIds).Find(posts).Error
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"backend/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IModerationRepository interface {
+	CreateReport(report *model.CommentReport) error
+	ExistsReport(commentId uint, userId uint) (bool, error)
+	CreateReviewReport(report *model.ReviewReport) error
+	ExistsReviewReport(reviewId uint, userId uint) (bool, error)
+	GetQueue(entries *[]model.ModerationQueueEntry, offset int, limit int) error
+	GetOpenReports(reports *[]model.CommentReport, commentIds []uint) error
+	GetOpenReviewReports(reports *[]model.ReviewReport, reviewIds []uint) error
+	GetCommentsByIDs(comments *[]model.Comment, commentIds []uint) error
+	GetReviewsByIDs(reviews *[]model.CourseReview, reviewIds []uint) error
+	CreateAction(action *model.ModerationAction) error
+	ApplyAction(action *model.ModerationAction, status string) error
+	GetActions(actions *[]model.ModerationAction, commentId *uint, reviewId *uint, offset int, limit int) error
+	GetNGWords(words *[]model.NGWord) error
+	ExistsNGWord(word string) (bool, error)
+	CreateNGWord(word *model.NGWord) error
+	DeleteNGWordByID(ngWordId uint) error
+}
+
+type moderationRepository struct {
+	db *gorm.DB
+}
+
+func NewModerationRepository(db *gorm.DB) IModerationRepository {
+	return &moderationRepository{db: db}
+}
+
+func (mr *moderationRepository) CreateReport(report *model.CommentReport) error {
+	return mr.db.Create(report).Error
+}
+
+// 未対応の通報が既にあるか
+func (mr *moderationRepository) ExistsReport(commentId uint, userId uint) (bool, error) {
+	var count int64
+	err := mr.db.Model(&model.CommentReport{}).
+		Where("comment_id = ? AND user_id = ? AND resolved_at IS NULL", commentId, userId).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (mr *moderationRepository) CreateReviewReport(report *model.ReviewReport) error {
+	return mr.db.Create(report).Error
+}
+
+// 未対応の通報が既にあるか
+func (mr *moderationRepository) ExistsReviewReport(reviewId uint, userId uint) (bool, error) {
+	var count int64
+	err := mr.db.Model(&model.ReviewReport{}).
+		Where("review_id = ? AND user_id = ? AND resolved_at IS NULL", reviewId, userId).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// 確認待ちのコメント・レビューと未対応の通報があるコメント・レビューを、通報の多い順に返す
+func (mr *moderationRepository) GetQueue(entries *[]model.ModerationQueueEntry, offset int, limit int) error {
+	return mr.db.Raw(`SELECT * FROM (
+			SELECT CAST(? AS text) AS kind, comments.id AS target_id,
+				COUNT(comment_reports.id) AS report_count, MAX(comment_reports.created_at) AS last_reported_at
+			FROM comments
+			LEFT JOIN comment_reports ON comment_reports.comment_id = comments.id AND comment_reports.resolved_at IS NULL
+			WHERE comments.status = ? OR comment_reports.id IS NOT NULL
+			GROUP BY comments.id
+			UNION ALL
+			SELECT CAST(? AS text), course_reviews.id,
+				COUNT(review_reports.id), MAX(review_reports.created_at)
+			FROM course_reviews
+			LEFT JOIN review_reports ON review_reports.review_id = course_reviews.id AND review_reports.resolved_at IS NULL
+			WHERE course_reviews.status = ? OR review_reports.id IS NOT NULL
+			GROUP BY course_reviews.id
+		) AS queue
+		ORDER BY report_count desc, last_reported_at desc NULLS LAST, kind, target_id
+		OFFSET ? LIMIT ?`,
+		model.ModerationTargetComment, model.CommentStatusPending,
+		model.ModerationTargetReview, model.ReviewStatusPending,
+		offset, limit).
+		Scan(entries).Error
+}
+
+func (mr *moderationRepository) GetOpenReports(reports *[]model.CommentReport, commentIds []uint) error {
+	if len(commentIds) == 0 {
+		return nil
+	}
+	return mr.db.Where("comment_id IN ? AND resolved_at IS NULL", commentIds).
+		Order("created_at desc").
+		Find(reports).Error
+}
+
+func (mr *moderationRepository) GetOpenReviewReports(reports *[]model.ReviewReport, reviewIds []uint) error {
+	if len(reviewIds) == 0 {
+		return nil
+	}
+	return mr.db.Where("review_id IN ? AND resolved_at IS NULL", reviewIds).
+		Order("created_at desc").
+		Find(reports).Error
+}
+
+func (mr *moderationRepository) GetCommentsByIDs(comments *[]model.Comment, commentIds []uint) error {
+	if len(commentIds) == 0 {
+		return nil
+	}
+	return mr.db.Where("id IN ?", commentIds).Find(comments).Error
+}
+
+func (mr *moderationRepository) GetReviewsByIDs(reviews *[]model.CourseReview, reviewIds []uint) error {
+	if len(reviewIds) == 0 {
+		return nil
+	}
+	return mr.db.Where("id IN ?", reviewIds).Find(reviews).Error
+}
+
+func (mr *moderationRepository) CreateAction(action *model.ModerationAction) error {
+	return mr.db.Create(action).Error
+}
+
+// コメント・レビューの状態を変え、未対応の通報を対応済みにして操作を記録する。
+// 削除の場合はコメントを返信ごと削除する
+func (mr *moderationRepository) ApplyAction(action *model.ModerationAction, status string) error {
+	var target, report interface{}
+	var targetId uint
+	var reportColumn string
+	if action.ReviewID != nil {
+		target, report, targetId, reportColumn = &model.CourseReview{}, &model.ReviewReport{}, *action.ReviewID, "review_id"
+	} else {
+		target, report, targetId, reportColumn = &model.Comment{}, &model.CommentReport{}, *action.CommentID, "comment_id"
+	}
+	return mr.db.Transaction(func(tx *gorm.DB) error {
+		var result *gorm.DB
+		if action.Action == model.ModerationActionDelete {
+			result = tx.Where("id = ?", targetId).Delete(target)
+		} else {
+			result = tx.Model(target).Where("id = ?", targetId).Update("status", status)
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected < 1 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Model(report).
+			Where(reportColumn+" = ? AND resolved_at IS NULL", targetId).
+			Update("resolved_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(action).Error
+	})
+}
+
+func (mr *moderationRepository) GetActions(actions *[]model.ModerationAction, commentId *uint, reviewId *uint, offset int, limit int) error {
+	query := mr.db.Order("created_at desc, id desc")
+	if commentId != nil {
+		query = query.Where("comment_id = ?", *commentId)
+	}
+	if reviewId != nil {
+		query = query.Where("review_id = ?", *reviewId)
+	}
+	return query.Offset(offset).Limit(limit).Find(actions).Error
+}
+
+func (mr *moderationRepository) GetNGWords(words *[]model.NGWord) error {
+	return mr.db.Order("word").Find(words).Error
+}
+
+func (mr *moderationRepository) ExistsNGWord(word string) (bool, error) {
+	var count int64
+	err := mr.db.Model(&model.NGWord{}).Where("word = ?", word).Count(&count).Error
+	return count > 0, err
+}
+
+func (mr *moderationRepository) CreateNGWord(word *model.NGWord) error {
+	return mr.db.Create(word).Error
+}
+
+func (mr *moderationRepository) DeleteNGWordByID(ngWordId uint) error {
+	result := mr.db.Where("id = ?", ngWordId).Delete(&model.NGWord{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected < 1 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
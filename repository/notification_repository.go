@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"backend/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type INotificationRepository interface {
+	CreateNotifications(notifications *[]model.Notification) error
+	GetNotifications(notifications *[]model.Notification, userId uint, unreadOnly bool, offset int, limit int) error
+	CountUnread(userId uint) (int64, error)
+	MarkRead(userId uint, notificationId uint) error
+	MarkAllRead(userId uint) error
+	GetOptOutTypes(userId uint) ([]string, error)
+	GetOptOuts(userIds []uint) (map[uint]map[string]bool, error)
+	UpdateOptOuts(userId uint, enabled map[string]bool) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) INotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (nr *notificationRepository) CreateNotifications(notifications *[]model.Notification) error {
+	if len(*notifications) == 0 {
+		return nil
+	}
+	return nr.db.Create(notifications).Error
+}
+
+func (nr *notificationRepository) GetNotifications(notifications *[]model.Notification, userId uint, unreadOnly bool, offset int, limit int) error {
+	query := nr.db.Where("user_id = ?", userId)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	return query.Order("created_at desc, id desc").
+		Offset(offset).
+		Limit(limit).
+		Find(notifications).Error
+}
+
+func (nr *notificationRepository) CountUnread(userId uint) (int64, error) {
+	var count int64
+	err := nr.db.Model(&model.Notification{}).Where("user_id = ? AND read_at IS NULL", userId).Count(&count).Error
+	return count, err
+}
+
+// 既読の通知は既読にした日時を変えない
+func (nr *notificationRepository) MarkRead(userId uint, notificationId uint) error {
+	result := nr.db.Model(&model.Notification{}).
+		Where("id = ? AND user_id = ?", notificationId, userId).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (nr *notificationRepository) MarkAllRead(userId uint) error {
+	return nr.db.Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId).
+		Update("read_at", time.Now()).Error
+}
+
+func (nr *notificationRepository) GetOptOutTypes(userId uint) ([]string, error) {
+	var types []string
+	err := nr.db.Model(&model.NotificationOptOut{}).Where("user_id = ?", userId).Pluck("type", &types).Error
+	return types, err
+}
+
+// ユーザーIDごとに受け取らない種類を返す
+func (nr *notificationRepository) GetOptOuts(userIds []uint) (map[uint]map[string]bool, error) {
+	optOuts := map[uint]map[string]bool{}
+	if len(userIds) == 0 {
+		return optOuts, nil
+	}
+	var rows []model.NotificationOptOut
+	if err := nr.db.Where("user_id IN ?", userIds).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if optOuts[row.UserID] == nil {
+			optOuts[row.UserID] = map[string]bool{}
+		}
+		optOuts[row.UserID][row.Type] = true
+	}
+	return optOuts, nil
+}
+
+// enabledに含まれる種類のみ更新する
+func (nr *notificationRepository) UpdateOptOuts(userId uint, enabled map[string]bool) error {
+	return nr.db.Transaction(func(tx *gorm.DB) error {
+		for notificationType, on := range enabled {
+			if on {
+				if err := tx.Where("user_id = ? AND type = ?", userId, notificationType).
+					Delete(&model.NotificationOptOut{}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			optOut := model.NotificationOptOut{UserID: userId, Type: notificationType}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+				DoNothing: true,
+			}).Create(&optOut).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
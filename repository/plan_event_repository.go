@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"backend/model"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 計画のイベントを他のAPIインスタンスに知らせるPostgresのチャンネル
+const PlanEventChannel = "plan_events"
+
+// イベントの採番で使うアドバイザリロックのキー(計画IDと組で使う)
+const planEventLockKey = 1
+
+type IPlanEventRepository interface {
+	CreateEvent(event *model.PlanEvent) error
+	GetEventByID(event *model.PlanEvent, eventId uint) error
+	GetEventsAfter(events *[]model.PlanEvent, planId uint, afterId uint, limit int) error
+	GetLatestEvent(event *model.PlanEvent, planId uint) error
+	DeleteEventsBefore(before time.Time) error
+}
+
+type planEventRepository struct {
+	db *gorm.DB
+}
+
+func NewPlanEventRepository(db *gorm.DB) IPlanEventRepository {
+	return &planEventRepository{db: db}
+}
+
+// 保存と同じトランザクションでNOTIFYし、コミットされたイベントだけが通知されるようにする。
+// 計画ごとにロックしてから採番し、同じ計画のイベントIDがコミット順に並ぶようにする
+// (後から採番したイベントが先にコミットされると、Last-Event-ID以降の送り直しで抜けるため)。
+// ペイロードは "計画ID:イベントID"
+func (per *planEventRepository) CreateEvent(event *model.PlanEvent) error {
+	return per.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", planEventLockKey, event.PlanID).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+		payload := fmt.Sprintf("%d:%d", event.PlanID, event.ID)
+		return tx.Exec("SELECT pg_notify(?, ?)", PlanEventChannel, payload).Error
+	})
+}
+
+func (per *planEventRepository) GetEventByID(event *model.PlanEvent, eventId uint) error {
+	return per.db.Where("id = ?", eventId).First(event).Error
+}
+
+func (per *planEventRepository) GetEventsAfter(events *[]model.PlanEvent, planId uint, afterId uint, limit int) error {
+	return per.db.Where("plan_id = ? AND id > ?", planId, afterId).
+		Order("id").
+		Limit(limit).
+		Find(events).Error
+}
+
+func (per *planEventRepository) GetLatestEvent(event *model.PlanEvent, planId uint) error {
+	return per.db.Where("plan_id = ?", planId).Order("id DESC").First(event).Error
+}
+
+func (per *planEventRepository) DeleteEventsBefore(before time.Time) error {
+	return per.db.Where("created_at < ?", before).Delete(&model.PlanEvent{}).Error
+}
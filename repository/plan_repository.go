@@ -2,19 +2,23 @@ package repository
 
 import (
 	"backend/model"
-	"errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type IPlanRepository interface {
-	GetAllPlans(plans *[]model.Plan, offset int, limit int) error
+	GetAllPlans(plans *[]model.Plan, userId uint, offset int, limit int) error
 	GetPlanByID(plan *model.Plan, planId uint) error
 	CreatePlan(plan *model.Plan) error
+	ImportPlan(plan *model.Plan) error
 	UpdatePlan(plan *model.Plan, planId uint) error
 	DeletePlanByID(planId uint) error
-	ToggleFavoritePlan(userId uint, planId uint) error
+	ToggleFavoritePlan(userId uint, planId uint) (bool, error)
 	GetFavoriteCount(planId uint) (int64, error)
+	GetFavoriteCounts(planIds []uint) (map[uint]int64, error)
+	GetFavoritedPlanIDs(userId uint, planIds []uint) (map[uint]bool, error)
+	GetFavoritePlans(favorites *[]model.FavoritePlan, userId uint, offset int, limit int) error
 }
 
 type planRepository struct {
@@ -25,11 +29,13 @@ func NewPlanRepository(db *gorm.DB) IPlanRepository {
 	return &planRepository{db: db}
 }
 
-func (pr *planRepository) GetAllPlans(plans *[]model.Plan, offset int, limit int) error {
+// 公開されている計画と自分の計画のみを返す
+func (pr *planRepository) GetAllPlans(plans *[]model.Plan, userId uint, offset int, limit int) error {
 	return pr.db.Preload("User").
 		Preload("User.University").
 		Preload("User.Faculty").
 		Preload("User.Department").
+		Where("visibility = ? OR user_id = ?", model.PlanVisibilityPublic, userId).
 		Offset(offset).
 		Limit(limit).
 		Find(plans).Error
@@ -41,7 +47,16 @@ func (pr *planRepository) GetPlanByID(plan *model.Plan, planId uint) error {
 		Preload("User.Faculty").
 		Preload("User.Department").
 		Preload("Courses").
+		Preload("Courses.Slots").
+		Preload("Courses.PlanTerm").
+		Preload("Terms", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sequence")
+		}).
+		Preload("Terms.AcademicTerm").
 		Preload("Posts").
+		Preload("Posts.Mentions").
+		Preload("Posts.Reactions").
+		Preload("Posts.Answers", selectAnswerIDs).
 		Preload("Favorites").
 		Where("id = ?", planId).
 		First(plan).Error
@@ -51,9 +66,45 @@ func (pr *planRepository) CreatePlan(plan *model.Plan) error {
 	return pr.db.Create(plan).Error
 }
 
+// 計画・学期・科目をまとめて登録する。学期ごとの科目はTerms[i].Courses、未割り当ての科目はCoursesに入れる
+func (pr *planRepository) ImportPlan(plan *model.Plan) error {
+	return pr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(plan).Error; err != nil {
+			return err
+		}
+		for i := range plan.Terms {
+			term := &plan.Terms[i]
+			term.PlanID = plan.ID
+			if err := tx.Omit("Plan", "AcademicTerm", "Courses").Create(term).Error; err != nil {
+				return err
+			}
+			for j := range term.Courses {
+				term.Courses[j].PlanID = plan.ID
+				term.Courses[j].PlanTermID = &term.ID
+			}
+			if len(term.Courses) > 0 {
+				if err := tx.Omit("Plan", "CatalogCourse", "PlanTerm").Create(&term.Courses).Error; err != nil {
+					return err
+				}
+			}
+		}
+		for i := range plan.Courses {
+			plan.Courses[i].PlanID = plan.ID
+		}
+		if len(plan.Courses) > 0 {
+			if err := tx.Omit("Plan", "CatalogCourse", "PlanTerm").Create(&plan.Courses).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// 作成者とフォーク元は変更しない
 func (pr *planRepository) UpdatePlan(plan *model.Plan, planId uint) error {
 	if err := pr.db.Model(&model.Plan{}).
 		Where("id = ?", planId).
+		Omit("UserID", "ForkedFromID").
 		Updates(plan).Error; err != nil {
 		return err
 	}
@@ -71,23 +122,30 @@ func (pr *planRepository) DeletePlanByID(planId uint) error {
 	return nil
 }
 
-func (pr *planRepository) ToggleFavoritePlan(userId uint, planId uint) error {
-	var favoritePlan model.FavoritePlan
-	result := pr.db.Where("user_id = ? AND plan_id = ?", userId, planId).First(&favoritePlan)
-
-	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		// お気に入りが存在しない場合は新規作成
+// お気に入りが存在すれば削除し、存在しなければ作成する。作成した場合はtrueを返す。
+// 同時に追加された場合も一意制約で重複しないよう、作成時の衝突は無視する
+func (pr *planRepository) ToggleFavoritePlan(userId uint, planId uint) (bool, error) {
+	created := false
+	err := pr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND plan_id = ?", userId, planId).Delete(&model.FavoritePlan{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return nil
+		}
 		newFavorite := model.FavoritePlan{
 			UserID: userId,
 			PlanID: planId,
 		}
-		return pr.db.Create(&newFavorite).Error
-	} else if result.Error != nil {
+		result = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "plan_id"}},
+			DoNothing: true,
+		}).Create(&newFavorite)
+		created = result.RowsAffected > 0
 		return result.Error
-	}
-
-	// お気に入りが既に存在する場合は削除
-	return pr.db.Delete(&favoritePlan).Error
+	})
+	return created && err == nil, err
 }
 
 func (pr *planRepository) GetFavoriteCount(planId uint) (int64, error) {
@@ -97,3 +155,60 @@ func (pr *planRepository) GetFavoriteCount(planId uint) (int64, error) {
 		Count(&count).Error
 	return count, err
 }
+
+// 複数の計画のお気に入り数を1回のクエリで取得する
+func (pr *planRepository) GetFavoriteCounts(planIds []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(planIds))
+	if len(planIds) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		PlanID uint
+		Count  int64
+	}
+	if err := pr.db.Model(&model.FavoritePlan{}).
+		Select("plan_id, COUNT(*) AS count").
+		Where("plan_id IN ?", planIds).
+		Group("plan_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.PlanID] = row.Count
+	}
+	return counts, nil
+}
+
+func (pr *planRepository) GetFavoritedPlanIDs(userId uint, planIds []uint) (map[uint]bool, error) {
+	favorited := make(map[uint]bool, len(planIds))
+	if len(planIds) == 0 {
+		return favorited, nil
+	}
+	var ids []uint
+	if err := pr.db.Model(&model.FavoritePlan{}).
+		Where("user_id = ? AND plan_id IN ?", userId, planIds).
+		Pluck("plan_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		favorited[id] = true
+	}
+	return favorited, nil
+}
+
+// 新しくお気に入りにした順。非公開になった他人の計画は含めない
+func (pr *planRepository) GetFavoritePlans(favorites *[]model.FavoritePlan, userId uint, offset int, limit int) error {
+	return pr.db.Preload("Plan").
+		Preload("Plan.User").
+		Preload("Plan.User.University").
+		Preload("Plan.User.Faculty").
+		Preload("Plan.User.Department").
+		Preload("CollectionItems").
+		Joins("JOIN plans ON plans.id = favorite_plans.plan_id").
+		Where("favorite_plans.user_id = ?", userId).
+		Where("plans.visibility = ? OR plans.user_id = ?", model.PlanVisibilityPublic, userId).
+		Order("favorite_plans.created_at desc NULLS LAST, favorite_plans.id desc").
+		Offset(offset).
+		Limit(limit).
+		Find(favorites).Error
+}
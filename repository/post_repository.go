@@ -10,6 +10,7 @@ import (
 type IPostRepository interface {
 	GetAllPosts(posts *[]model.Post, author_id uint) error //ユーザーが作成した全ての投稿を取得
 	GetPostByID(post *[]model.Post, planId uint) error
+	GetPost(post *model.Post, postId uint) error
 	CreatePost(post *model.Post) error
 	DeletePostByID(id uint) error
 }
@@ -24,7 +25,7 @@ func NewPostRepository(db *gorm.DB) IPostRepository {
 
 // すべての投稿を取得
 func (pr *postRepository) GetAllPosts(posts *[]model.Post, author_id uint) error {
-	if err := pr.db.Joins("JOIN users ON users.id = posts.author_id").Where("author_id = ?", author_id).Order("created_at").Find(posts).Error; err != nil {
+	if err := pr.db.Preload("Mentions").Preload("Reactions").Preload("Answers", selectAnswerIDs).Joins("JOIN users ON users.id = posts.author_id").Where("author_id = ?", author_id).Order("created_at").Find(posts).Error; err != nil {
 		return err
 	}
 	return nil
@@ -32,12 +33,17 @@ func (pr *postRepository) GetAllPosts(posts *[]model.Post, author_id uint) error
 
 // 投稿IDで投稿を取得
 func (pr *postRepository) GetPostByID(posts *[]model.Post, planId uint) error {
-	if err := pr.db.Joins("JOIN plans ON plans.id = posts.plan_id").Where("plan_id = ?", planId).Find(posts).Error; err != nil {
+	if err := pr.db.Preload("Mentions").Preload("Reactions").Preload("Answers", selectAnswerIDs).Joins("JOIN plans ON plans.id = posts.plan_id").Where("plan_id = ?", planId).Find(posts).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
+// 投稿IDで投稿を1件取得
+func (pr *postRepository) GetPost(post *model.Post, postId uint) error {
+	return pr.db.Preload("Mentions").Preload("Reactions").Preload("Answers", selectAnswerIDs).Where("id = ?", postId).First(post).Error
+}
+
 // 投稿を作成
 func (pr *postRepository) CreatePost(post *model.Post) error {
 	if err := pr.db.Create(post).Error; err != nil {
@@ -57,3 +63,8 @@ func (pr *postRepository) DeletePostByID(postId uint) error {
 	}
 	return nil
 }
+
+// 回答数を数えるため、投稿と一緒に読み込む回答はIDのみにする
+func selectAnswerIDs(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "post_id")
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"backend/model"
+
+	"gorm.io/gorm"
+)
+
+type IPrerequisiteRepository interface {
+	GetPrerequisitesByUniversityID(prerequisites *[]model.CoursePrerequisite, universityId uint) error
+	GetPrerequisitesByCourseIDs(prerequisites *[]model.CoursePrerequisite, catalogCourseIds []uint) error
+	CreatePrerequisite(prerequisite *model.CoursePrerequisite) error
+	DeletePrerequisiteByID(prerequisiteId uint) error
+}
+
+type prerequisiteRepository struct {
+	db *gorm.DB
+}
+
+func NewPrerequisiteRepository(db *gorm.DB) IPrerequisiteRepository {
+	return &prerequisiteRepository{db: db}
+}
+
+func (pr *prerequisiteRepository) GetPrerequisitesByUniversityID(prerequisites *[]model.CoursePrerequisite, universityId uint) error {
+	return pr.db.Preload("CatalogCourse").
+		Preload("RequiredCourse").
+		Joins("JOIN catalog_courses ON catalog_courses.id = course_prerequisites.catalog_course_id").
+		Where("catalog_courses.university_id = ?", universityId).
+		Find(prerequisites).Error
+}
+
+// 指定した科目が必要とする科目の辺を取得する
+func (pr *prerequisiteRepository) GetPrerequisitesByCourseIDs(prerequisites *[]model.CoursePrerequisite, catalogCourseIds []uint) error {
+	if len(catalogCourseIds) == 0 {
+		return nil
+	}
+	return pr.db.Preload("RequiredCourse").
+		Where("catalog_course_id IN ?", catalogCourseIds).
+		Find(prerequisites).Error
+}
+
+func (pr *prerequisiteRepository) CreatePrerequisite(prerequisite *model.CoursePrerequisite) error {
+	return pr.db.Omit("CatalogCourse", "RequiredCourse").Create(prerequisite).Error
+}
+
+func (pr *prerequisiteRepository) DeletePrerequisiteByID(prerequisiteId uint) error {
+	result := pr.db.Where("id = ?", prerequisiteId).Delete(&model.CoursePrerequisite{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected < 1 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
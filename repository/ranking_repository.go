@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"backend/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IRankingRepository interface {
+	GetFavoriteActivities(activities *[]model.PlanActivity) error
+	GetCommentActivities(activities *[]model.PlanActivity) error
+	GetForkActivities(activities *[]model.PlanActivity) error
+	ReplacePlanScores(scores *[]model.PlanScore) error
+	ExistsSnapshot(weekStart time.Time) (bool, error)
+	CreateSnapshot(weekStart time.Time) error
+	GetLatestSnapshotWeek(weekStart *time.Time) error
+	GetTrendingScores(scores *[]model.PlanScore, universityId *uint, departmentId *uint, offset int, limit int) error
+	GetSnapshotRanks(weekStart time.Time, universityId *uint, departmentId *uint, planIds []uint) (map[uint]int, error)
+	GetPlansByIDs(plans *[]model.Plan, planIds []uint) error
+}
+
+type rankingRepository struct {
+	db *gorm.DB
+}
+
+func NewRankingRepository(db *gorm.DB) IRankingRepository {
+	return &rankingRepository{db: db}
+}
+
+// 公開されている計画へのお気に入り。日時のない古いお気に入りは計画の作成日時で代用する
+func (rr *rankingRepository) GetFavoriteActivities(activities *[]model.PlanActivity) error {
+	return rr.db.Model(&model.FavoritePlan{}).
+		Select("favorite_plans.plan_id, COALESCE(favorite_plans.created_at, plans.created_at) AS created_at").
+		Joins("JOIN plans ON plans.id = favorite_plans.plan_id").
+		Where("plans.visibility = ?", model.PlanVisibilityPublic).
+		Scan(activities).Error
+}
+
+func (rr *rankingRepository) GetCommentActivities(activities *[]model.PlanActivity) error {
+	return rr.db.Model(&model.Comment{}).
+		Select("comments.plan_id, comments.created_at").
+		Joins("JOIN plans ON plans.id = comments.plan_id").
+		Where("plans.visibility = ?", model.PlanVisibilityPublic).
+		Scan(activities).Error
+}
+
+// コピーされた計画ではなくコピー元の計画の反応として返す
+func (rr *rankingRepository) GetForkActivities(activities *[]model.PlanActivity) error {
+	return rr.db.Table("plans AS forks").
+		Select("forks.forked_from_id AS plan_id, forks.created_at").
+		Joins("JOIN plans ON plans.id = forks.forked_from_id").
+		Where("plans.visibility = ?", model.PlanVisibilityPublic).
+		Scan(activities).Error
+}
+
+func (rr *rankingRepository) ReplacePlanScores(scores *[]model.PlanScore) error {
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&model.PlanScore{}).Error; err != nil {
+			return err
+		}
+		if len(*scores) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(scores, 500).Error
+	})
+}
+
+func (rr *rankingRepository) ExistsSnapshot(weekStart time.Time) (bool, error) {
+	var count int64
+	err := rr.db.Model(&model.PlanRankingSnapshot{}).
+		Where("week_start = ?", weekStart).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// 現在のスコアをその週のスナップショットとして保存する
+func (rr *rankingRepository) CreateSnapshot(weekStart time.Time) error {
+	var scores []model.PlanScore
+	if err := rr.db.Where("score > 0").Find(&scores).Error; err != nil {
+		return err
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+	snapshots := make([]model.PlanRankingSnapshot, 0, len(scores))
+	for _, score := range scores {
+		snapshots = append(snapshots, model.PlanRankingSnapshot{
+			WeekStart: weekStart,
+			PlanID:    score.PlanID,
+			Score:     score.Score,
+		})
+	}
+	return rr.db.CreateInBatches(&snapshots, 500).Error
+}
+
+func (rr *rankingRepository) GetLatestSnapshotWeek(weekStart *time.Time) error {
+	var snapshot model.PlanRankingSnapshot
+	if err := rr.db.Order("week_start desc").First(&snapshot).Error; err != nil {
+		return err
+	}
+	*weekStart = snapshot.WeekStart
+	return nil
+}
+
+func (rr *rankingRepository) GetTrendingScores(scores *[]model.PlanScore, universityId *uint, departmentId *uint, offset int, limit int) error {
+	query := rr.db.Model(&model.PlanScore{}).
+		Joins("JOIN plans ON plans.id = plan_scores.plan_id").
+		Where("plan_scores.score > 0 AND plans.visibility = ?", model.PlanVisibilityPublic)
+	query = scopeByAffiliation(query, universityId, departmentId)
+	return query.Order("plan_scores.score desc, plan_scores.plan_id").
+		Offset(offset).
+		Limit(limit).
+		Find(scores).Error
+}
+
+// 指定した計画の、スナップショット時点での同じ範囲内の順位を返す
+func (rr *rankingRepository) GetSnapshotRanks(weekStart time.Time, universityId *uint, departmentId *uint, planIds []uint) (map[uint]int, error) {
+	ranks := map[uint]int{}
+	if len(planIds) == 0 {
+		return ranks, nil
+	}
+	ranked := rr.db.Model(&model.PlanRankingSnapshot{}).
+		Select("plan_ranking_snapshots.plan_id, ROW_NUMBER() OVER (ORDER BY plan_ranking_snapshots.score desc, plan_ranking_snapshots.plan_id) AS rank").
+		Joins("JOIN plans ON plans.id = plan_ranking_snapshots.plan_id").
+		Where("plan_ranking_snapshots.week_start = ? AND plans.visibility = ?", weekStart, model.PlanVisibilityPublic)
+	ranked = scopeByAffiliation(ranked, universityId, departmentId)
+
+	var rows []struct {
+		PlanID uint
+		Rank   int
+	}
+	if err := rr.db.Table("(?) AS ranked", ranked).
+		Where("plan_id IN ?", planIds).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		ranks[row.PlanID] = row.Rank
+	}
+	return ranks, nil
+}
+
+func (rr *rankingRepository) GetPlansByIDs(plans *[]model.Plan, planIds []uint) error {
+	return rr.db.Preload("User").
+		Preload("User.University").
+		Preload("User.Faculty").
+		Preload("User.Department").
+		Where("id IN ?", planIds).
+		Find(plans).Error
+}
+
+// 計画の作成者の大学・学科で絞り込む
+func scopeByAffiliation(query *gorm.DB, universityId *uint, departmentId *uint) *gorm.DB {
+	if universityId == nil && departmentId == nil {
+		return query
+	}
+	query = query.Joins("JOIN users ON users.id = plans.user_id")
+	if universityId != nil {
+		query = query.Where("users.university_id = ?", *universityId)
+	}
+	if departmentId != nil {
+		query = query.Where("users.department_id = ?", *departmentId)
+	}
+	return query
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"backend/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IReactionRepository interface {
+	AddCommentReaction(userId uint, commentId uint, emoji string) error
+	RemoveCommentReaction(userId uint, commentId uint, emoji string) error
+	GetCommentReactions(reactions *[]model.Reaction, commentId uint) error
+	AddPostReaction(userId uint, postId uint, emoji string) error
+	RemovePostReaction(userId uint, postId uint, emoji string) error
+	GetPostReactions(reactions *[]model.Reaction, postId uint) error
+}
+
+type reactionRepository struct {
+	db *gorm.DB
+}
+
+func NewReactionRepository(db *gorm.DB) IReactionRepository {
+	return &reactionRepository{db: db}
+}
+
+// すでに同じリアクションがある場合は何もしない
+func (rr *reactionRepository) AddCommentReaction(userId uint, commentId uint, emoji string) error {
+	reaction := model.Reaction{UserID: userId, CommentID: &commentId, Emoji: emoji}
+	return rr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "comment_id"}, {Name: "emoji"}},
+		DoNothing: true,
+	}).Create(&reaction).Error
+}
+
+func (rr *reactionRepository) RemoveCommentReaction(userId uint, commentId uint, emoji string) error {
+	return rr.db.Where("user_id = ? AND comment_id = ? AND emoji = ?", userId, commentId, emoji).
+		Delete(&model.Reaction{}).Error
+}
+
+func (rr *reactionRepository) GetCommentReactions(reactions *[]model.Reaction, commentId uint) error {
+	return rr.db.Where("comment_id = ?", commentId).Order("created_at, id").Find(reactions).Error
+}
+
+// すでに同じリアクションがある場合は何もしない
+func (rr *reactionRepository) AddPostReaction(userId uint, postId uint, emoji string) error {
+	reaction := model.Reaction{UserID: userId, PostID: &postId, Emoji: emoji}
+	return rr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "post_id"}, {Name: "emoji"}},
+		DoNothing: true,
+	}).Create(&reaction).Error
+}
+
+func (rr *reactionRepository) RemovePostReaction(userId uint, postId uint, emoji string) error {
+	return rr.db.Where("user_id = ? AND post_id = ? AND emoji = ?", userId, postId, emoji).
+		Delete(&model.Reaction{}).Error
+}
+
+func (rr *reactionRepository) GetPostReactions(reactions *[]model.Reaction, postId uint) error {
+	return rr.db.Where("post_id = ?", postId).Order("created_at, id").Find(reactions).Error
+}
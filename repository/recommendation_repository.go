@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"backend/model"
+
+	"gorm.io/gorm"
+)
+
+type IRecommendationRepository interface {
+	GetPlansForCooccurrence(plans *[]model.Plan) error
+	ReplaceCooccurrences(cooccurrences *[]model.CourseCooccurrence) error
+	GetCooccurrences(cooccurrences *[]model.CourseCooccurrence, departmentId uint, courseKeys []string) error
+}
+
+type recommendationRepository struct {
+	db *gorm.DB
+}
+
+func NewRecommendationRepository(db *gorm.DB) IRecommendationRepository {
+	return &recommendationRepository{db: db}
+}
+
+// 学科が分かるユーザーの公開されている計画のみを集計に使う
+func (rr *recommendationRepository) GetPlansForCooccurrence(plans *[]model.Plan) error {
+	return rr.db.Preload("User").
+		Preload("Courses").
+		Preload("Favorites").
+		Joins("JOIN users ON users.id = plans.user_id").
+		Where("users.department_id IS NOT NULL AND plans.visibility = ?", model.PlanVisibilityPublic).
+		Find(plans).Error
+}
+
+func (rr *recommendationRepository) ReplaceCooccurrences(cooccurrences *[]model.CourseCooccurrence) error {
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&model.CourseCooccurrence{}).Error; err != nil {
+			return err
+		}
+		if len(*cooccurrences) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(cooccurrences, 500).Error
+	})
+}
+
+func (rr *recommendationRepository) GetCooccurrences(cooccurrences *[]model.CourseCooccurrence, departmentId uint, courseKeys []string) error {
+	if len(courseKeys) == 0 {
+		return nil
+	}
+	return rr.db.Where("department_id = ? AND course_key IN ?", departmentId, courseKeys).
+		Order("score desc").
+		Find(cooccurrences).Error
+}
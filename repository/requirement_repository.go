@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"backend/model"
+
+	"gorm.io/gorm"
+)
+
+type IRequirementRepository interface {
+	GetRequirementSets(sets *[]model.RequirementSet, departmentId uint) error
+	GetRequirementSetByID(set *model.RequirementSet, requirementId uint) error
+	GetApplicableRequirementSet(set *model.RequirementSet, departmentId uint, entryYear uint) error
+	CreateRequirementSet(set *model.RequirementSet) error
+	UpdateRequirementSet(set *model.RequirementSet, requirementId uint) error
+	DeleteRequirementSetByID(requirementId uint) error
+}
+
+type requirementRepository struct {
+	db *gorm.DB
+}
+
+func NewRequirementRepository(db *gorm.DB) IRequirementRepository {
+	return &requirementRepository{db: db}
+}
+
+func (rr *requirementRepository) GetRequirementSets(sets *[]model.RequirementSet, departmentId uint) error {
+	query := rr.db.Preload("Rules").Preload("Rules.Courses")
+	if departmentId != 0 {
+		query = query.Where("department_id = ?", departmentId)
+	}
+	return query.Order("department_id, entry_year desc").Find(sets).Error
+}
+
+func (rr *requirementRepository) GetRequirementSetByID(set *model.RequirementSet, requirementId uint) error {
+	return rr.db.Preload("Rules").
+		Preload("Rules.Courses").
+		Where("id = ?", requirementId).
+		First(set).Error
+}
+
+// 入学年度以前に定義された要件のうち最も新しいものを適用する
+func (rr *requirementRepository) GetApplicableRequirementSet(set *model.RequirementSet, departmentId uint, entryYear uint) error {
+	return rr.db.Preload("Rules").
+		Preload("Rules.Courses").
+		Where("department_id = ? AND entry_year <= ?", departmentId, entryYear).
+		Order("entry_year desc").
+		First(set).Error
+}
+
+func (rr *requirementRepository) CreateRequirementSet(set *model.RequirementSet) error {
+	return rr.db.Create(set).Error
+}
+
+// ルールは丸ごと置き換える
+func (rr *requirementRepository) UpdateRequirementSet(set *model.RequirementSet, requirementId uint) error {
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		var current model.RequirementSet
+		if err := tx.Where("id = ?", requirementId).First(&current).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&current).Updates(map[string]interface{}{
+			"name":          set.Name,
+			"department_id": set.DepartmentID,
+			"entry_year":    set.EntryYear,
+		}).Error; err != nil {
+			return err
+		}
+
+		var ruleIds []uint
+		if err := tx.Model(&model.RequirementRule{}).
+			Where("requirement_set_id = ?", requirementId).
+			Pluck("id", &ruleIds).Error; err != nil {
+			return err
+		}
+		if len(ruleIds) > 0 {
+			if err := tx.Where("requirement_rule_id IN ?", ruleIds).Delete(&model.RequirementCourse{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", ruleIds).Delete(&model.RequirementRule{}).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range set.Rules {
+			set.Rules[i].ID = 0
+			set.Rules[i].RequirementSetID = requirementId
+			for j := range set.Rules[i].Courses {
+				set.Rules[i].Courses[j].ID = 0
+			}
+		}
+		if len(set.Rules) > 0 {
+			if err := tx.Create(&set.Rules).Error; err != nil {
+				return err
+			}
+		}
+
+		set.ID = requirementId
+		return tx.Preload("Rules").Preload("Rules.Courses").First(set, requirementId).Error
+	})
+}
+
+func (rr *requirementRepository) DeleteRequirementSetByID(requirementId uint) error {
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		var ruleIds []uint
+		if err := tx.Model(&model.RequirementRule{}).
+			Where("requirement_set_id = ?", requirementId).
+			Pluck("id", &ruleIds).Error; err != nil {
+			return err
+		}
+		if len(ruleIds) > 0 {
+			if err := tx.Where("requirement_rule_id IN ?", ruleIds).Delete(&model.RequirementCourse{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", ruleIds).Delete(&model.RequirementRule{}).Error; err != nil {
+				return err
+			}
+		}
+		result := tx.Where("id = ?", requirementId).Delete(&model.RequirementSet{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected < 1 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"backend/model"
+
+	"gorm.io/gorm"
+)
+
+type IReviewRepository interface {
+	GetReviewsByCatalogCourseID(reviews *[]model.CourseReview, catalogCourseId uint, order string, offset int, limit int) error
+	GetReviewsByUserID(reviews *[]model.CourseReview, userId uint) error
+	GetReviewSummary(summary *model.CourseReviewSummary, catalogCourseId uint) error
+	GetReviewByID(review *model.CourseReview, reviewId uint) error
+	ExistsReview(userId uint, catalogCourseId uint, academicYear uint, termKind string, excludeId uint) (bool, error)
+	CreateReview(review *model.CourseReview) error
+	UpdateReview(review *model.CourseReview, reviewId uint) error
+	DeleteReviewByID(reviewId uint) error
+}
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) IReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+// 公開中のレビューだけを返す
+func (rr *reviewRepository) GetReviewsByCatalogCourseID(reviews *[]model.CourseReview, catalogCourseId uint, order string, offset int, limit int) error {
+	return rr.db.Where("catalog_course_id = ? AND status = ?", catalogCourseId, model.ReviewStatusVisible).
+		Order(order).
+		Offset(offset).
+		Limit(limit).
+		Find(reviews).Error
+}
+
+func (rr *reviewRepository) GetReviewsByUserID(reviews *[]model.CourseReview, userId uint) error {
+	return rr.db.Where("user_id = ?", userId).Order("created_at desc").Find(reviews).Error
+}
+
+// 公開中のレビューだけを集計する
+func (rr *reviewRepository) GetReviewSummary(summary *model.CourseReviewSummary, catalogCourseId uint) error {
+	return rr.db.Model(&model.CourseReview{}).
+		Select(`count(*) AS review_count,
+			avg(rating) AS average_rating,
+			avg(difficulty) AS average_difficulty,
+			avg(workload) AS average_workload,
+			avg(attendance_strictness) AS average_attendance_strictness`).
+		Where("catalog_course_id = ? AND status = ?", catalogCourseId, model.ReviewStatusVisible).
+		Scan(summary).Error
+}
+
+func (rr *reviewRepository) GetReviewByID(review *model.CourseReview, reviewId uint) error {
+	return rr.db.Where("id = ?", reviewId).First(review).Error
+}
+
+func (rr *reviewRepository) ExistsReview(userId uint, catalogCourseId uint, academicYear uint, termKind string, excludeId uint) (bool, error) {
+	var count int64
+	err := rr.db.Model(&model.CourseReview{}).
+		Where("user_id = ? AND catalog_course_id = ? AND academic_year = ? AND term_kind = ? AND id <> ?",
+			userId, catalogCourseId, academicYear, termKind, excludeId).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (rr *reviewRepository) CreateReview(review *model.CourseReview) error {
+	return rr.db.Omit("User", "CatalogCourse").Create(review).Error
+}
+
+func (rr *reviewRepository) UpdateReview(review *model.CourseReview, reviewId uint) error {
+	result := rr.db.Model(&model.CourseReview{}).
+		Where("id = ?", reviewId).
+		Updates(map[string]interface{}{
+			"academic_year":         review.AcademicYear,
+			"term_kind":             review.TermKind,
+			"rating":                review.Rating,
+			"difficulty":            review.Difficulty,
+			"workload":              review.Workload,
+			"attendance_strictness": review.AttendanceStrictness,
+			"content":               review.Content,
+			"status":                review.Status,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected < 1 {
+		return gorm.ErrRecordNotFound
+	}
+	return rr.db.Where("id = ?", reviewId).First(review).Error
+}
+
+func (rr *reviewRepository) DeleteReviewByID(reviewId uint) error {
+	result := rr.db.Where("id = ?", reviewId).Delete(&model.CourseReview{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected < 1 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"backend/model"
+
+	"gorm.io/gorm"
+)
+
+type ITermRepository interface {
+	GetAcademicTerms(terms *[]model.AcademicTerm, universityId uint, academicYear uint) error
+	GetAcademicTermByID(term *model.AcademicTerm, academicTermId uint) error
+	CreateAcademicTerm(term *model.AcademicTerm) error
+	UpdateAcademicTerm(term *model.AcademicTerm, academicTermId uint) error
+	DeleteAcademicTermByID(academicTermId uint) error
+	GetPlanTerms(terms *[]model.PlanTerm, planId uint) error
+	GetPlanTermByID(term *model.PlanTerm, planTermId uint) error
+	CreatePlanTerms(terms *[]model.PlanTerm) error
+	UpdatePlanTerm(term *model.PlanTerm, planTermId uint) error
+	DeletePlanTermByID(planTermId uint) error
+}
+
+type termRepository struct {
+	db *gorm.DB
+}
+
+func NewTermRepository(db *gorm.DB) ITermRepository {
+	return &termRepository{db: db}
+}
+
+func (tr *termRepository) GetAcademicTerms(terms *[]model.AcademicTerm, universityId uint, academicYear uint) error {
+	query := tr.db.Where("university_id = ?", universityId)
+	if academicYear != 0 {
+		query = query.Where("academic_year = ?", academicYear)
+	}
+	return query.Order("start_date").Find(terms).Error
+}
+
+func (tr *termRepository) GetAcademicTermByID(term *model.AcademicTerm, academicTermId uint) error {
+	return tr.db.Where("id = ?", academicTermId).First(term).Error
+}
+
+func (tr *termRepository) CreateAcademicTerm(term *model.AcademicTerm) error {
+	return tr.db.Create(term).Error
+}
+
+func (tr *termRepository) UpdateAcademicTerm(term *model.AcademicTerm, academicTermId uint) error {
+	result := tr.db.Model(&model.AcademicTerm{}).
+		Where("id = ?", academicTermId).
+		Updates(map[string]interface{}{
+			"university_id":         term.UniversityID,
+			"academic_year":         term.AcademicYear,
+			"kind":                  term.Kind,
+			"name":                  term.Name,
+			"start_date":            term.StartDate,
+			"end_date":              term.EndDate,
+			"registration_start_at": term.RegistrationStartAt,
+			"registration_end_at":   term.RegistrationEndAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected < 1 {
+		return gorm.ErrRecordNotFound
+	}
+	return tr.db.Where("id = ?", academicTermId).First(term).Error
+}
+
+func (tr *termRepository) DeleteAcademicTermByID(academicTermId uint) error {
+	return tr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.PlanTerm{}).
+			Where("academic_term_id = ?", academicTermId).
+			Update("academic_term_id", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", academicTermId).Delete(&model.AcademicTerm{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected < 1 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (tr *termRepository) GetPlanTerms(terms *[]model.PlanTerm, planId uint) error {
+	return tr.db.Preload("AcademicTerm").
+		Preload("Courses").
+		Preload("Courses.Slots").
+		Where("plan_id = ?", planId).
+		Order("sequence").
+		Find(terms).Error
+}
+
+func (tr *termRepository) GetPlanTermByID(term *model.PlanTerm, planTermId uint) error {
+	return tr.db.Preload("AcademicTerm").Where("id = ?", planTermId).First(term).Error
+}
+
+func (tr *termRepository) CreatePlanTerms(terms *[]model.PlanTerm) error {
+	return tr.db.Omit("Plan", "AcademicTerm", "Courses").Create(terms).Error
+}
+
+func (tr *termRepository) UpdatePlanTerm(term *model.PlanTerm, planTermId uint) error {
+	result := tr.db.Model(&model.PlanTerm{}).
+		Where("id = ?", planTermId).
+		Updates(map[string]interface{}{
+			"grade":            term.Grade,
+			"kind":             term.Kind,
+			"sequence":         term.Sequence,
+			"academic_term_id": term.AcademicTermID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected < 1 {
+		return gorm.ErrRecordNotFound
+	}
+	return tr.db.Preload("AcademicTerm").Where("id = ?", planTermId).First(term).Error
+}
+
+// 学期に割り当てられていた科目は未割り当てに戻す
+func (tr *termRepository) DeletePlanTermByID(planTermId uint) error {
+	return tr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Course{}).
+			Where("plan_term_id = ?", planTermId).
+			Update("plan_term_id", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", planTermId).Delete(&model.PlanTerm{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected < 1 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
@@ -8,8 +8,12 @@ import (
 
 type IUserRepository interface {
 	GetUserByEmail(user *model.User, email string) error
+	GetUserByID(user *model.User, userId uint) error
 	CreateUser(user *model.User) error
 	ExistsUserByEmail(email string) (bool, error)
+	ExistsHandle(handle string, excludeUserId uint) (bool, error)
+	UpdateHandle(userId uint, handle string) error
+	GetUsersByHandles(users *[]model.User, handles []string) error
 }
 
 type userRepository struct {
@@ -28,6 +32,10 @@ func (ur *userRepository) GetUserByEmail(user *model.User, email string) error {
 	return nil
 }
 
+func (ur *userRepository) GetUserByID(user *model.User, userId uint) error {
+	return ur.db.Where("id = ?", userId).First(user).Error
+}
+
 // ユーザーを作成
 func (ur *userRepository) CreateUser(user *model.User) error {
 	if err := ur.db.Create(user).Error; err != nil {
@@ -43,3 +51,22 @@ func (ur *userRepository) ExistsUserByEmail(email string) (bool, error) {
 	}
 	return count > 0, nil
 }
+
+func (ur *userRepository) ExistsHandle(handle string, excludeUserId uint) (bool, error) {
+	var count int64
+	if err := ur.db.Model(&model.User{}).Where("handle = ? AND id <> ?", handle, excludeUserId).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (ur *userRepository) UpdateHandle(userId uint, handle string) error {
+	return ur.db.Model(&model.User{}).Where("id = ?", userId).Update("handle", handle).Error
+}
+
+func (ur *userRepository) GetUsersByHandles(users *[]model.User, handles []string) error {
+	if len(handles) == 0 {
+		return nil
+	}
+	return ur.db.Where("handle IN ?", handles).Find(users).Error
+}
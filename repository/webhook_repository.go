@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"backend/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IWebhookRepository interface {
+	GetWebhooksByUserID(webhooks *[]model.Webhook, userId uint) error
+	GetWebhookByID(webhook *model.Webhook, webhookId uint) error
+	CreateWebhook(webhook *model.Webhook) error
+	UpdateWebhook(webhook *model.Webhook) error
+	DeleteWebhook(webhookId uint) error
+	GetSubscribedWebhooks(webhooks *[]model.Webhook, planId uint) error
+	CreateDeliveries(deliveries *[]model.WebhookDelivery) error
+	GetDeliveries(deliveries *[]model.WebhookDelivery, webhookId uint, offset int, limit int) error
+	GetDelivery(delivery *model.WebhookDelivery, webhookId uint, deliveryId uint) error
+	GetDueDeliveries(deliveries *[]model.WebhookDelivery, now time.Time, limit int) error
+	ClaimDelivery(delivery model.WebhookDelivery, leaseUntil time.Time) (bool, error)
+	SaveDeliveryResult(delivery *model.WebhookDelivery) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) IWebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (wr *webhookRepository) GetWebhooksByUserID(webhooks *[]model.Webhook, userId uint) error {
+	return wr.db.Where("user_id = ?", userId).Order("id").Find(webhooks).Error
+}
+
+func (wr *webhookRepository) GetWebhookByID(webhook *model.Webhook, webhookId uint) error {
+	return wr.db.First(webhook, webhookId).Error
+}
+
+func (wr *webhookRepository) CreateWebhook(webhook *model.Webhook) error {
+	return wr.db.Create(webhook).Error
+}
+
+// falseの値も保存するため、Updatesではなく列を指定して更新する
+func (wr *webhookRepository) UpdateWebhook(webhook *model.Webhook) error {
+	return wr.db.Model(webhook).
+		Select("url", "events", "all_plans", "active", "updated_at").
+		Updates(webhook).Error
+}
+
+func (wr *webhookRepository) DeleteWebhook(webhookId uint) error {
+	return wr.db.Delete(&model.Webhook{}, webhookId).Error
+}
+
+// 計画の作成者のWebhookと、公開計画の場合はすべての計画を対象にしたWebhookを返す
+func (wr *webhookRepository) GetSubscribedWebhooks(webhooks *[]model.Webhook, planId uint) error {
+	return wr.db.
+		Joins("JOIN plans ON plans.id = ?", planId).
+		Where("webhooks.active").
+		Where("webhooks.user_id = plans.user_id OR (webhooks.all_plans AND plans.visibility = ?)", model.PlanVisibilityPublic).
+		Find(webhooks).Error
+}
+
+func (wr *webhookRepository) CreateDeliveries(deliveries *[]model.WebhookDelivery) error {
+	if len(*deliveries) == 0 {
+		return nil
+	}
+	return wr.db.Create(deliveries).Error
+}
+
+func (wr *webhookRepository) GetDeliveries(deliveries *[]model.WebhookDelivery, webhookId uint, offset int, limit int) error {
+	return wr.db.Where("webhook_id = ?", webhookId).
+		Order("created_at desc, id desc").
+		Offset(offset).
+		Limit(limit).
+		Find(deliveries).Error
+}
+
+func (wr *webhookRepository) GetDelivery(delivery *model.WebhookDelivery, webhookId uint, deliveryId uint) error {
+	return wr.db.Where("id = ? AND webhook_id = ?", deliveryId, webhookId).First(delivery).Error
+}
+
+// 無効にしたWebhookの送信は、有効に戻すまで保留する
+func (wr *webhookRepository) GetDueDeliveries(deliveries *[]model.WebhookDelivery, now time.Time, limit int) error {
+	return wr.db.Preload("Webhook").
+		Joins("JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id").
+		Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+		Where("webhooks.active").
+		Order("webhook_deliveries.next_attempt_at, webhook_deliveries.id").
+		Limit(limit).
+		Find(deliveries).Error
+}
+
+// 送信中に他のインスタンスが同じ送信を行わないよう、次の送信予定を先に延ばす。
+// 取得してから他で更新されていなければtrueを返す
+func (wr *webhookRepository) ClaimDelivery(delivery model.WebhookDelivery, leaseUntil time.Time) (bool, error) {
+	result := wr.db.Model(&model.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND attempts = ? AND next_attempt_at = ?",
+			delivery.ID, model.WebhookDeliveryPending, delivery.Attempts, delivery.NextAttemptAt).
+		Update("next_attempt_at", leaseUntil)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (wr *webhookRepository) SaveDeliveryResult(delivery *model.WebhookDelivery) error {
+	return wr.db.Model(delivery).
+		Omit("Webhook").
+		Select("status", "attempts", "next_attempt_at", "response_status", "response_body", "error", "delivered_at", "updated_at").
+		Updates(delivery).Error
+}
@@ -3,6 +3,8 @@ package router
 import (
 	"backend/controller"
 	"backend/middleware"
+	"backend/model"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -12,18 +14,48 @@ func NewRouter(
 	pc controller.IPostController,
 	plc controller.IPlanController,
 	cc controller.ICourseController,
-	ccu controller.ICommentController) *echo.Echo {
+	ccu controller.ICommentController,
+	rc controller.IRequirementController,
+	ctc controller.ICatalogController,
+	prc controller.IPrerequisiteController,
+	tc controller.ITermController,
+	clc controller.ICalendarController,
+	ttc controller.ITimetableController,
+	rvc controller.IReviewController,
+	rcc controller.IRecommendationController,
+	rkc controller.IRankingController,
+	fc controller.IFavoriteController,
+	mc controller.IModerationController,
+	mnc controller.IMentionController,
+	rac controller.IReactionController,
+	ac controller.IAnswerController,
+	nc controller.INotificationController,
+	pec controller.IPlanEventController,
+	dgc controller.IDigestController,
+	wc controller.IWebhookController) *echo.Echo {
 	e := echo.New()
+	e.IPExtractor = middleware.IPExtractor()
 
 	e.Use(middleware.CorsMiddleware())
 	e.Use(middleware.CsrfMiddleware())
 
 	// グループ化
 	p := e.Group("/posts")
+	answers := e.Group("/answers")
+	notifications := e.Group("/notifications")
+	webhooks := e.Group("/webhooks")
 	pl := e.Group("/plans")
 	c := e.Group("/courses")
+	authCourses := e.Group("/courses")
 	comments := e.Group("/comments")
 	authComments := e.Group("/comments")
+	r := e.Group("/requirements")
+	catalog := e.Group("/catalog")
+	t := e.Group("/terms")
+	rv := e.Group("/reviews")
+	users := e.Group("/users")
+	moderation := e.Group("/moderation")
+	admin := e.Group("/admin")
 
 	// 認証に関するエンドポイント
 	e.POST("/signup", uc.SignUp)
@@ -33,38 +65,180 @@ func NewRouter(
 	e.GET("/auth/google/login", uc.GoogleLogin)
 	e.GET("/auth/google/callback", uc.GoogleCallback)
 
+	// カレンダーアプリからの購読（トークンで認証）
+	e.GET("/calendar/feeds/:token", clc.GetFeedCalendar)
+
+	// ダイジェストメールの配信停止（トークンで認証）
+	e.GET(middleware.DigestUnsubscribePath, dgc.ConfirmUnsubscribe)
+	e.POST(middleware.DigestUnsubscribePath, dgc.Unsubscribe)
+
+	// ログインユーザー自身に関するエンドポイント
+	users.Use(middleware.JwtMiddleware())
+	users.PUT("/me/handle", uc.UpdateHandle)
+	users.GET("/me/mentions", mnc.GetMyMentions)
+	users.GET("/me/digest", dgc.GetSetting)
+	users.PUT("/me/digest", dgc.UpdateSetting)
+	users.GET("/me/favorites", plc.GetMyFavoritePlans)
+	users.GET("/me/collections", fc.GetCollections)
+	users.POST("/me/collections", fc.CreateCollection)
+	users.PUT("/me/collections/:collectionId", fc.UpdateCollection)
+	users.DELETE("/me/collections/:collectionId", fc.DeleteCollectionByID)
+	users.GET("/me/collections/:collectionId/plans", fc.GetCollectionPlans)
+	users.PUT("/me/collections/:collectionId/plans/:planId", fc.AddPlanToCollection)
+	users.DELETE("/me/collections/:collectionId/plans/:planId", fc.RemovePlanFromCollection)
+
 	// postに関するエンドポイント
 	p.Use(middleware.JwtMiddleware())
 	p.GET("", pc.GetAllPosts)
+	p.GET("/unanswered", ac.GetUnansweredQuestions)
 	p.GET("/:planId", pc.GetPostByID)
 	p.POST("", pc.CreatePost)
 	p.DELETE("/:postId", pc.DeletePostByID)
+	p.PUT("/:postId/reactions/:emoji", rac.AddPostReaction)
+	p.DELETE("/:postId/reactions/:emoji", rac.RemovePostReaction)
+	p.GET("/:postId/answers", ac.GetAnswers)
+	p.POST("/:postId/answers", ac.CreateAnswer)
+	p.PUT("/:postId/accepted-answer", ac.AcceptAnswer)
+	p.DELETE("/:postId/accepted-answer", ac.UnacceptAnswer)
+
+	// 質問への回答
+	answers.Use(middleware.JwtMiddleware())
+	answers.DELETE("/:answerId", ac.DeleteAnswer)
+	answers.PUT("/:answerId/vote", ac.VoteAnswer)
+	answers.DELETE("/:answerId/vote", ac.DeleteVote)
+
+	// 通知
+	notifications.Use(middleware.JwtMiddleware())
+	notifications.GET("", nc.GetNotifications)
+	notifications.PUT("/read", nc.MarkAllRead)
+	notifications.PUT("/:notificationId/read", nc.MarkRead)
+	notifications.GET("/preferences", nc.GetPreferences)
+	notifications.PUT("/preferences", nc.UpdatePreferences)
+
+	// 外部サービス向けのWebhook
+	webhooks.Use(middleware.JwtMiddleware())
+	webhooks.GET("", wc.GetWebhooks)
+	webhooks.POST("", wc.CreateWebhook)
+	webhooks.PUT("/:webhookId", wc.UpdateWebhook)
+	webhooks.DELETE("/:webhookId", wc.DeleteWebhook)
+	webhooks.GET("/:webhookId/deliveries", wc.GetDeliveries)
+	webhooks.POST("/:webhookId/deliveries/:deliveryId/redeliver", wc.Redeliver)
 
 	// planに関するエンドポイント
 	pl.Use(middleware.JwtMiddleware())
 	pl.GET("", plc.GetAllPlans)
+	pl.GET("/compare", plc.ComparePlans)
+	pl.GET("/trending", rkc.GetTrendingPlans)
 	pl.GET("/:planId", plc.GetPlansByID)
 	pl.POST("", plc.CreatePlan)
+	pl.POST("/import", plc.ImportPlan)
 	pl.PUT("/:planId", plc.UpdatePlan)
 	pl.DELETE("/:planId", plc.DeletePlanByID)
+	pl.POST("/:planId/fork", plc.ForkPlan)
+	pl.GET("/:planId/export", plc.ExportPlan)
+	pl.GET("/:planId/timetable", ttc.GetTimetable)
 	pl.POST("/:planId/favorite", plc.ToggleFavoritePlan)
+	pl.PUT("/:planId/favorite", fc.SaveFavorite)
+	pl.DELETE("/:planId/favorite", fc.DeleteFavorite)
 	pl.GET("/:planId/favorite/count", plc.GetFavoriteCount)
+	pl.GET("/:planId/requirements", rc.EvaluatePlan)
+	pl.GET("/:planId/prerequisites", prc.CheckPlan)
+	pl.GET("/:planId/recommendations", rcc.GetPlanRecommendations)
+	pl.GET("/:planId/events", pec.StreamPlanEvents)
+	pl.GET("/:planId/terms", tc.GetPlanTerms)
+	pl.POST("/:planId/terms", tc.CreatePlanTerms)
+	pl.PUT("/:planId/terms/:planTermId", tc.UpdatePlanTerm)
+	pl.DELETE("/:planId/terms/:planTermId", tc.DeletePlanTermByID)
+	pl.GET("/:planId/calendar.ics", clc.GetPlanCalendar)
+	pl.POST("/:planId/calendar/feed", clc.CreateCalendarFeed)
+	pl.DELETE("/:planId/calendar/feed", clc.RevokeCalendarFeeds)
 
 	// courseに関するエンドポイント
-	c.GET("/:courseId", cc.GetAllCourses)
-	c.POST("", cc.CreateCourses)
-	c.PUT("/:courseId", cc.UpdateCourse)
-	c.DELETE("/:courseId", cc.DeleteCourseByID)
+	c.GET("/:courseId", cc.GetAllCourses, middleware.OptionalJwtMiddleware())
+
+	// 認証が必要なcourseのエンドポイント
+	authCourses.Use(middleware.JwtMiddleware())
+	authCourses.POST("", cc.CreateCourses)
+	authCourses.PUT("/:courseId", cc.UpdateCourse)
+	authCourses.DELETE("/:courseId", cc.DeleteCourseByID)
+	authCourses.PUT("/:courseId/term", cc.MoveCourseToTerm)
 
 	// コメント関連のルート（認証不要）
 	comments.Use(middleware.OptionalJwtMiddleware())
-	comments.POST("", ccu.CreateComment)
+	comments.POST("", ccu.CreateComment, middleware.AnonymousRateLimitMiddleware(20*time.Second, 3))
 	comments.GET("/plan/:planId", ccu.GetCommentsByPlanID)
+	comments.GET("/:commentId/replies", ccu.GetReplies)
+	comments.POST("/:commentId/report", mc.ReportComment, middleware.AnonymousRateLimitMiddleware(20*time.Second, 3))
+	comments.DELETE("/:commentId", ccu.DeleteComment)
 
 	// 認証が必要なコメント関連のルート
 	authComments.Use(middleware.JwtMiddleware())
 	authComments.GET("/me", ccu.GetMyComments)
-	authComments.DELETE("/:commentId", ccu.DeleteComment)
+	authComments.PUT("/:commentId", ccu.UpdateComment)
+	authComments.PUT("/:commentId/reactions/:emoji", rac.AddCommentReaction)
+	authComments.DELETE("/:commentId/reactions/:emoji", rac.RemoveCommentReaction)
+
+	// 卒業要件に関するエンドポイント
+	r.Use(middleware.JwtMiddleware())
+	r.GET("", rc.GetRequirementSets)
+	r.GET("/:requirementId", rc.GetRequirementSetByID)
+
+	// 科目カタログに関するエンドポイント
+	catalog.Use(middleware.JwtMiddleware())
+	catalog.GET("/courses", ctc.SearchCatalogCourses)
+	catalog.GET("/courses/:catalogCourseId", ctc.GetCatalogCourseByID)
+	catalog.GET("/courses/:catalogCourseId/prerequisites", prc.GetPrerequisitesByCourseID)
+	catalog.GET("/prerequisites", prc.GetPrerequisiteGraph)
+	catalog.GET("/courses/:catalogCourseId/reviews", rvc.GetReviewsByCatalogCourseID)
+	catalog.GET("/courses/:catalogCourseId/reviews/summary", rvc.GetReviewSummary)
+	catalog.POST("/courses/:catalogCourseId/reviews", rvc.CreateReview)
+	catalog.GET("/courses/:catalogCourseId/recommendations", rcc.GetCourseRecommendations)
+
+	// 科目レビューに関するエンドポイント
+	rv.Use(middleware.JwtMiddleware())
+	rv.GET("/me", rvc.GetMyReviews)
+	rv.PUT("/:reviewId", rvc.UpdateReview)
+	rv.DELETE("/:reviewId", rvc.DeleteReviewByID)
+	rv.POST("/:reviewId/report", mc.ReportReview)
+
+	// 学年暦に関するエンドポイント
+	t.Use(middleware.JwtMiddleware())
+	t.GET("", tc.GetAcademicTerms)
+	t.GET("/periods", clc.GetPeriodTimes)
+	t.GET("/holidays", clc.GetHolidays)
+
+	// モデレーター用のエンドポイント
+	moderation.Use(middleware.JwtMiddleware(), middleware.RoleMiddleware(model.RoleModerator, model.RoleAdmin))
+	moderation.GET("/queue", mc.GetQueue)
+	moderation.GET("/actions", mc.GetActions)
+	moderation.GET("/comments/:commentId/history", ccu.GetCommentHistory)
+	moderation.POST("/comments/:commentId/hide", mc.HideComment)
+	moderation.POST("/comments/:commentId/restore", mc.RestoreComment)
+	moderation.DELETE("/comments/:commentId", mc.DeleteComment)
+	moderation.POST("/reviews/:reviewId/hide", mc.HideReview)
+	moderation.POST("/reviews/:reviewId/restore", mc.RestoreReview)
+	moderation.DELETE("/reviews/:reviewId", mc.DeleteReview)
+	moderation.GET("/ng-words", mc.GetNGWords)
+	moderation.POST("/ng-words", mc.CreateNGWord)
+	moderation.DELETE("/ng-words/:ngWordId", mc.DeleteNGWordByID)
+
+	// 管理者用のエンドポイント
+	admin.Use(middleware.JwtMiddleware(), middleware.RoleMiddleware(model.RoleAdmin))
+	admin.POST("/requirements", rc.CreateRequirementSet)
+	admin.PUT("/requirements/:requirementId", rc.UpdateRequirementSet)
+	admin.DELETE("/requirements/:requirementId", rc.DeleteRequirementSetByID)
+	admin.POST("/catalog/courses", ctc.CreateCatalogCourse)
+	admin.POST("/catalog/import", ctc.ImportCatalogCourses)
+	admin.POST("/catalog/prerequisites", prc.CreatePrerequisite)
+	admin.DELETE("/catalog/prerequisites/:prerequisiteId", prc.DeletePrerequisiteByID)
+	admin.POST("/terms", tc.CreateAcademicTerm)
+	admin.PUT("/terms/:academicTermId", tc.UpdateAcademicTerm)
+	admin.DELETE("/terms/:academicTermId", tc.DeleteAcademicTermByID)
+	admin.PUT("/universities/:universityId/periods", clc.ReplacePeriodTimes)
+	admin.POST("/holidays", clc.CreateHoliday)
+	admin.DELETE("/holidays/:holidayId", clc.DeleteHolidayByID)
+	admin.PUT("/catalog/courses/:catalogCourseId", ctc.UpdateCatalogCourse)
+	admin.DELETE("/catalog/courses/:catalogCourseId", ctc.DeleteCatalogCourseByID)
 
 	return e
 }
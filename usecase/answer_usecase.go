@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"backend/validator"
+	"errors"
+	"sort"
+	"strings"
+)
+
+type IAnswerUsecase interface {
+	GetAnswers(userId uint, postId uint) ([]model.AnswerResponse, error)
+	CreateAnswer(userId uint, postId uint, content string) (model.AnswerResponse, error)
+	DeleteAnswer(userId uint, answerId uint) error
+	VoteAnswer(userId uint, answerId uint, value int) (model.AnswerResponse, error)
+	DeleteVote(userId uint, answerId uint) (model.AnswerResponse, error)
+	AcceptAnswer(userId uint, postId uint, answerId uint) (model.PostResponse, error)
+	UnacceptAnswer(userId uint, postId uint) (model.PostResponse, error)
+	GetUnansweredQuestions(userId uint, universityId *uint, departmentId *uint, offset int, limit int) ([]model.PostResponse, error)
+}
+
+type answerUsecase struct {
+	ar  repository.IAnswerRepository
+	por repository.IPostRepository
+	pr  repository.IPlanRepository
+	av  validator.IAnswerValidator
+	m   IModerator
+	n   INotifier
+}
+
+func NewAnswerUsecase(
+	ar repository.IAnswerRepository,
+	por repository.IPostRepository,
+	pr repository.IPlanRepository,
+	av validator.IAnswerValidator,
+	m IModerator,
+	n INotifier,
+) IAnswerUsecase {
+	return &answerUsecase{ar: ar, por: por, pr: pr, av: av, m: m, n: n}
+}
+
+// 別の質問への回答をベストアンサーに選ぼうとした場合に返す
+var ErrAnswerPostMismatch = errors.New("answer belongs to another post")
+
+// 自分の回答を評価しようとした場合に返す
+var ErrOwnAnswerVote = errors.New("cannot vote on your own answer")
+
+// 質問の計画を閲覧できる場合のみ質問を返す
+func (au *answerUsecase) getVisiblePost(post *model.Post, postId uint, userId uint) error {
+	if err := au.por.GetPost(post, postId); err != nil {
+		return err
+	}
+	if post.PlanID == nil {
+		return nil
+	}
+	var plan model.Plan
+	return getVisiblePlan(au.pr, &plan, *post.PlanID, userId)
+}
+
+// ベストアンサーを先頭に、評価の高い順、同じ評価の場合は古い順に返す
+func (au *answerUsecase) GetAnswers(userId uint, postId uint) ([]model.AnswerResponse, error) {
+	var post model.Post
+	if err := au.getVisiblePost(&post, postId, userId); err != nil {
+		return nil, err
+	}
+	answers := []model.Answer{}
+	if err := au.ar.GetAnswersByPostID(&answers, postId); err != nil {
+		return nil, err
+	}
+	responses := make([]model.AnswerResponse, 0, len(answers))
+	for _, answer := range answers {
+		responses = append(responses, toAnswerResponse(answer, post.AcceptedAnswerID, userId))
+	}
+	sort.SliceStable(responses, func(i, j int) bool {
+		if responses[i].Accepted != responses[j].Accepted {
+			return responses[i].Accepted
+		}
+		return responses[i].Score > responses[j].Score
+	})
+	return responses, nil
+}
+
+func (au *answerUsecase) CreateAnswer(userId uint, postId uint, content string) (model.AnswerResponse, error) {
+	var post model.Post
+	if err := au.getVisiblePost(&post, postId, userId); err != nil {
+		return model.AnswerResponse{}, err
+	}
+	answer := model.Answer{PostID: postId, AuthorID: userId, Content: strings.TrimSpace(content)}
+	if err := au.av.AnswerValidate(answer); err != nil {
+		return model.AnswerResponse{}, err
+	}
+	if err := au.m.Moderate(ModerationTarget{Kind: ModerationKindAnswer, UserID: &userId, Text: answer.Content}); err != nil {
+		return model.AnswerResponse{}, err
+	}
+	if err := au.ar.CreateAnswer(&answer); err != nil {
+		return model.AnswerResponse{}, err
+	}
+	au.n.Notify(model.Notification{
+		UserID:   post.AuthorID,
+		Type:     model.NotificationTypeAnswer,
+		ActorID:  &userId,
+		PlanID:   post.PlanID,
+		PostID:   &post.ID,
+		AnswerID: &answer.ID,
+	})
+	return toAnswerResponse(answer, post.AcceptedAnswerID, userId), nil
+}
+
+// 回答者本人のみ削除できる
+func (au *answerUsecase) DeleteAnswer(userId uint, answerId uint) error {
+	return au.ar.DeleteAnswer(answerId, userId)
+}
+
+func (au *answerUsecase) VoteAnswer(userId uint, answerId uint, value int) (model.AnswerResponse, error) {
+	vote := model.AnswerVote{AnswerID: answerId, UserID: userId, Value: value}
+	if err := au.av.VoteValidate(vote); err != nil {
+		return model.AnswerResponse{}, err
+	}
+	var answer model.Answer
+	var post model.Post
+	if err := au.getVisibleAnswer(&answer, &post, answerId, userId); err != nil {
+		return model.AnswerResponse{}, err
+	}
+	if answer.AuthorID == userId {
+		return model.AnswerResponse{}, ErrOwnAnswerVote
+	}
+	if err := au.ar.SaveVote(&vote); err != nil {
+		return model.AnswerResponse{}, err
+	}
+	return au.reloadAnswer(answerId, post.AcceptedAnswerID, userId)
+}
+
+func (au *answerUsecase) DeleteVote(userId uint, answerId uint) (model.AnswerResponse, error) {
+	var answer model.Answer
+	var post model.Post
+	if err := au.getVisibleAnswer(&answer, &post, answerId, userId); err != nil {
+		return model.AnswerResponse{}, err
+	}
+	if err := au.ar.DeleteVote(answerId, userId); err != nil {
+		return model.AnswerResponse{}, err
+	}
+	return au.reloadAnswer(answerId, post.AcceptedAnswerID, userId)
+}
+
+func (au *answerUsecase) getVisibleAnswer(answer *model.Answer, post *model.Post, answerId uint, userId uint) error {
+	if err := au.ar.GetAnswerByID(answer, answerId); err != nil {
+		return err
+	}
+	return au.getVisiblePost(post, answer.PostID, userId)
+}
+
+func (au *answerUsecase) reloadAnswer(answerId uint, acceptedAnswerId *uint, userId uint) (model.AnswerResponse, error) {
+	var answer model.Answer
+	if err := au.ar.GetAnswerByID(&answer, answerId); err != nil {
+		return model.AnswerResponse{}, err
+	}
+	return toAnswerResponse(answer, acceptedAnswerId, userId), nil
+}
+
+// 質問者のみベストアンサーを選べる。選び直した場合は置き換える
+func (au *answerUsecase) AcceptAnswer(userId uint, postId uint, answerId uint) (model.PostResponse, error) {
+	var post model.Post
+	if err := au.por.GetPost(&post, postId); err != nil {
+		return model.PostResponse{}, err
+	}
+	if post.AuthorID != userId {
+		return model.PostResponse{}, ErrForbidden
+	}
+	var answer model.Answer
+	if err := au.ar.GetAnswerByID(&answer, answerId); err != nil {
+		return model.PostResponse{}, err
+	}
+	if answer.PostID != postId {
+		return model.PostResponse{}, ErrAnswerPostMismatch
+	}
+	if err := au.ar.SetAcceptedAnswer(postId, &answerId); err != nil {
+		return model.PostResponse{}, err
+	}
+	if post.AcceptedAnswerID == nil || *post.AcceptedAnswerID != answerId {
+		au.n.Notify(model.Notification{
+			UserID:   answer.AuthorID,
+			Type:     model.NotificationTypeAnswerAccepted,
+			ActorID:  &userId,
+			PlanID:   post.PlanID,
+			PostID:   &post.ID,
+			AnswerID: &answer.ID,
+		})
+	}
+	post.AcceptedAnswerID = &answerId
+	return toPostResponse(post, userId), nil
+}
+
+func (au *answerUsecase) UnacceptAnswer(userId uint, postId uint) (model.PostResponse, error) {
+	var post model.Post
+	if err := au.por.GetPost(&post, postId); err != nil {
+		return model.PostResponse{}, err
+	}
+	if post.AuthorID != userId {
+		return model.PostResponse{}, ErrForbidden
+	}
+	if err := au.ar.SetAcceptedAnswer(postId, nil); err != nil {
+		return model.PostResponse{}, err
+	}
+	post.AcceptedAnswerID = nil
+	return toPostResponse(post, userId), nil
+}
+
+func (au *answerUsecase) GetUnansweredQuestions(userId uint, universityId *uint, departmentId *uint, offset int, limit int) ([]model.PostResponse, error) {
+	posts := []model.Post{}
+	if err := au.ar.GetUnansweredPosts(&posts, userId, universityId, departmentId, offset, limit); err != nil {
+		return nil, err
+	}
+	responses := make([]model.PostResponse, 0, len(posts))
+	for _, post := range posts {
+		responses = append(responses, toPostResponse(post, userId))
+	}
+	return responses, nil
+}
+
+func toAnswerResponse(answer model.Answer, acceptedAnswerId *uint, userId uint) model.AnswerResponse {
+	res := model.AnswerResponse{
+		ID:        answer.ID,
+		PostID:    answer.PostID,
+		AuthorID:  answer.AuthorID,
+		Content:   answer.Content,
+		Accepted:  acceptedAnswerId != nil && *acceptedAnswerId == answer.ID,
+		CreatedAt: answer.CreatedAt,
+		UpdatedAt: answer.UpdatedAt,
+	}
+	for _, vote := range answer.Votes {
+		res.Score += vote.Value
+		if vote.UserID == userId {
+			res.MyVote = vote.Value
+		}
+	}
+	return res
+}
@@ -0,0 +1,325 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"backend/validator"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type ICalendarUsecase interface {
+	GetPlanCalendar(userId uint, planId uint) ([]byte, error)
+	GetFeedCalendar(token string) ([]byte, error)
+	CreateCalendarFeed(userId uint, planId uint) (model.CalendarFeedResponse, error)
+	RevokeCalendarFeeds(userId uint, planId uint) error
+	GetPeriodTimes(universityId uint) ([]model.PeriodTimeResponse, error)
+	ReplacePeriodTimes(universityId uint, periods []model.PeriodTime) ([]model.PeriodTimeResponse, error)
+	GetHolidays(universityId uint, from time.Time, to time.Time) ([]model.AcademicHolidayResponse, error)
+	CreateHoliday(holiday *model.AcademicHoliday) (model.AcademicHolidayResponse, error)
+	DeleteHolidayByID(holidayId uint) error
+}
+
+type calendarUsecase struct {
+	cr repository.ICalendarRepository
+	pr repository.IPlanRepository
+	cv validator.ICalendarValidator
+}
+
+func NewCalendarUsecase(cr repository.ICalendarRepository, pr repository.IPlanRepository, cv validator.ICalendarValidator) ICalendarUsecase {
+	return &calendarUsecase{cr: cr, pr: pr, cv: cv}
+}
+
+// 大学ごとの時限が登録されていない場合に使う一般的な90分授業の時間割
+var defaultPeriodTimes = []model.PeriodTime{
+	{Period: 1, StartTime: "09:00", EndTime: "10:30"},
+	{Period: 2, StartTime: "10:40", EndTime: "12:10"},
+	{Period: 3, StartTime: "13:00", EndTime: "14:30"},
+	{Period: 4, StartTime: "14:40", EndTime: "16:10"},
+	{Period: 5, StartTime: "16:20", EndTime: "17:50"},
+	{Period: 6, StartTime: "18:00", EndTime: "19:30"},
+	{Period: 7, StartTime: "19:40", EndTime: "21:10"},
+}
+
+func (cu *calendarUsecase) GetPlanCalendar(userId uint, planId uint) ([]byte, error) {
+	var plan model.Plan
+	if err := getVisiblePlan(cu.pr, &plan, planId, userId); err != nil {
+		return nil, err
+	}
+	return cu.buildPlanCalendar(plan)
+}
+
+func (cu *calendarUsecase) GetFeedCalendar(token string) ([]byte, error) {
+	var feed model.CalendarFeed
+	if err := cu.cr.GetActiveCalendarFeedByTokenHash(&feed, hashToken(token)); err != nil {
+		return nil, err
+	}
+	return cu.GetPlanCalendar(feed.UserID, feed.PlanID)
+}
+
+// 新しいフィードを発行すると以前のフィードURLは無効になる
+func (cu *calendarUsecase) CreateCalendarFeed(userId uint, planId uint) (model.CalendarFeedResponse, error) {
+	if err := cu.checkPlanOwner(userId, planId); err != nil {
+		return model.CalendarFeedResponse{}, err
+	}
+	token, err := generateToken()
+	if err != nil {
+		return model.CalendarFeedResponse{}, err
+	}
+	if err := cu.cr.RevokeCalendarFeeds(planId); err != nil {
+		return model.CalendarFeedResponse{}, err
+	}
+	feed := model.CalendarFeed{
+		PlanID:    planId,
+		UserID:    userId,
+		TokenHash: hashToken(token),
+	}
+	if err := cu.cr.CreateCalendarFeed(&feed); err != nil {
+		return model.CalendarFeedResponse{}, err
+	}
+	return model.CalendarFeedResponse{
+		ID:        feed.ID,
+		PlanID:    feed.PlanID,
+		Token:     token,
+		CreatedAt: feed.CreatedAt,
+	}, nil
+}
+
+func (cu *calendarUsecase) RevokeCalendarFeeds(userId uint, planId uint) error {
+	if err := cu.checkPlanOwner(userId, planId); err != nil {
+		return err
+	}
+	return cu.cr.RevokeCalendarFeeds(planId)
+}
+
+func (cu *calendarUsecase) GetPeriodTimes(universityId uint) ([]model.PeriodTimeResponse, error) {
+	periods, err := cu.periodTimes(universityId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]model.PeriodTimeResponse, 0, len(periods))
+	for _, period := range periods {
+		res = append(res, toPeriodTimeResponse(period))
+	}
+	return res, nil
+}
+
+func (cu *calendarUsecase) ReplacePeriodTimes(universityId uint, periods []model.PeriodTime) ([]model.PeriodTimeResponse, error) {
+	seen := map[int]bool{}
+	for i := range periods {
+		if err := cu.cv.PeriodTimeValidate(periods[i]); err != nil {
+			return nil, err
+		}
+		if seen[periods[i].Period] {
+			return nil, fmt.Errorf("period %d is duplicated", periods[i].Period)
+		}
+		seen[periods[i].Period] = true
+		periods[i].ID = 0
+		periods[i].UniversityID = universityId
+	}
+	if err := cu.cr.ReplacePeriodTimes(universityId, &periods); err != nil {
+		return nil, err
+	}
+	res := make([]model.PeriodTimeResponse, 0, len(periods))
+	for _, period := range periods {
+		res = append(res, toPeriodTimeResponse(period))
+	}
+	return res, nil
+}
+
+func (cu *calendarUsecase) GetHolidays(universityId uint, from time.Time, to time.Time) ([]model.AcademicHolidayResponse, error) {
+	var holidays []model.AcademicHoliday
+	if err := cu.cr.GetHolidays(&holidays, universityId, from, to); err != nil {
+		return nil, err
+	}
+	res := make([]model.AcademicHolidayResponse, 0, len(holidays))
+	for _, holiday := range holidays {
+		res = append(res, toAcademicHolidayResponse(holiday))
+	}
+	return res, nil
+}
+
+func (cu *calendarUsecase) CreateHoliday(holiday *model.AcademicHoliday) (model.AcademicHolidayResponse, error) {
+	if holiday == nil {
+		return model.AcademicHolidayResponse{}, errors.New("holiday is nil")
+	}
+	if err := cu.cv.HolidayValidate(*holiday); err != nil {
+		return model.AcademicHolidayResponse{}, err
+	}
+	if err := cu.cr.CreateHoliday(holiday); err != nil {
+		return model.AcademicHolidayResponse{}, err
+	}
+	return toAcademicHolidayResponse(*holiday), nil
+}
+
+func (cu *calendarUsecase) DeleteHolidayByID(holidayId uint) error {
+	return cu.cr.DeleteHolidayByID(holidayId)
+}
+
+// 学期に割り当てられ、学期の日程が分かる科目のみを毎週の予定として出力する
+func (cu *calendarUsecase) buildPlanCalendar(plan model.Plan) ([]byte, error) {
+	terms := make(map[uint]model.PlanTerm, len(plan.Terms))
+	for _, term := range plan.Terms {
+		terms[term.ID] = term
+	}
+	periodsByUniversity := map[uint]map[int]model.PeriodTime{}
+	holidaysByTerm := map[uint][]model.AcademicHoliday{}
+	now := time.Now()
+
+	w := &icsWriter{}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", "-//ClassPlanner//Timetable//JA")
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.text("X-WR-CALNAME", plan.Title)
+	w.line("X-WR-TIMEZONE", icsTimezone)
+	writeJSTTimezone(w)
+
+	for _, course := range plan.Courses {
+		if course.PlanTermID == nil {
+			continue
+		}
+		term, ok := terms[*course.PlanTermID]
+		if !ok || term.AcademicTerm == nil {
+			continue
+		}
+		academicTerm := *term.AcademicTerm
+
+		periods, ok := periodsByUniversity[academicTerm.UniversityID]
+		if !ok {
+			list, err := cu.periodTimes(academicTerm.UniversityID)
+			if err != nil {
+				return nil, err
+			}
+			periods = make(map[int]model.PeriodTime, len(list))
+			for _, period := range list {
+				periods[period.Period] = period
+			}
+			periodsByUniversity[academicTerm.UniversityID] = periods
+		}
+
+		holidays, ok := holidaysByTerm[academicTerm.ID]
+		if !ok {
+			if err := cu.cr.GetHolidays(&holidays, academicTerm.UniversityID, academicTerm.StartDate, academicTerm.EndDate); err != nil {
+				return nil, err
+			}
+			holidaysByTerm[academicTerm.ID] = holidays
+		}
+
+		startDate := dateInJST(academicTerm.StartDate)
+		endDate := dateInJST(academicTerm.EndDate)
+		for _, slot := range course.Slots {
+			period, ok := periods[slot.Period]
+			if !ok {
+				continue
+			}
+			first := firstWeekday(startDate, time.Weekday(slot.DayOfWeek))
+			if first.After(endDate) {
+				continue
+			}
+			start, err := atClock(first, period.StartTime)
+			if err != nil {
+				return nil, err
+			}
+			end, err := atClock(first, period.EndTime)
+			if err != nil {
+				return nil, err
+			}
+			until := endDate.Add(24*time.Hour - time.Second)
+
+			w.line("BEGIN", "VEVENT")
+			w.line("UID", fmt.Sprintf("course-%d-term-%d-%d-%d@class-planner", course.ID, term.ID, slot.DayOfWeek, slot.Period))
+			w.line("DTSTAMP", icsUTCTime(now))
+			w.line("DTSTART;TZID="+icsTimezone, icsLocalTime(start))
+			w.line("DTEND;TZID="+icsTimezone, icsLocalTime(end))
+			w.line("RRULE", "FREQ=WEEKLY;UNTIL="+icsUTCTime(until))
+			exdates := []string{}
+			for _, holiday := range holidays {
+				date := dateInJST(holiday.Date)
+				if date.Weekday() != time.Weekday(slot.DayOfWeek) || date.Before(first) {
+					continue
+				}
+				exdate, _ := atClock(date, period.StartTime)
+				exdates = append(exdates, icsLocalTime(exdate))
+			}
+			if len(exdates) > 0 {
+				w.line("EXDATE;TZID="+icsTimezone, strings.Join(exdates, ","))
+			}
+			w.text("SUMMARY", course.Name)
+			if course.Room != nil && *course.Room != "" {
+				w.text("LOCATION", *course.Room)
+			}
+			if course.Content != nil && *course.Content != "" {
+				w.text("DESCRIPTION", *course.Content)
+			}
+			w.line("END", "VEVENT")
+		}
+	}
+
+	w.line("END", "VCALENDAR")
+	return w.bytes(), nil
+}
+
+func (cu *calendarUsecase) periodTimes(universityId uint) ([]model.PeriodTime, error) {
+	var periods []model.PeriodTime
+	if err := cu.cr.GetPeriodTimes(&periods, universityId); err != nil {
+		return nil, err
+	}
+	if len(periods) == 0 {
+		return defaultPeriodTimes, nil
+	}
+	return periods, nil
+}
+
+func (cu *calendarUsecase) checkPlanOwner(userId uint, planId uint) error {
+	var plan model.Plan
+	if err := cu.pr.GetPlanByID(&plan, planId); err != nil {
+		return err
+	}
+	if plan.UserID != userId {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// date型の値は年月日のみを使い、日本時間の0時として扱う
+func dateInJST(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, jst)
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func toPeriodTimeResponse(period model.PeriodTime) model.PeriodTimeResponse {
+	return model.PeriodTimeResponse{
+		Period:    period.Period,
+		StartTime: period.StartTime,
+		EndTime:   period.EndTime,
+	}
+}
+
+func toAcademicHolidayResponse(holiday model.AcademicHoliday) model.AcademicHolidayResponse {
+	return model.AcademicHolidayResponse{
+		ID:           holiday.ID,
+		UniversityID: holiday.UniversityID,
+		Date:         holiday.Date,
+		Name:         holiday.Name,
+	}
+}
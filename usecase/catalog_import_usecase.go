@@ -0,0 +1,377 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"backend/validator"
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/width"
+)
+
+type ICatalogImportUsecase interface {
+	ImportCatalogCourses(r io.Reader, options model.CatalogImportOptions) (model.CatalogImportReport, error)
+}
+
+type catalogImportUsecase struct {
+	cr repository.ICatalogRepository
+	cv validator.ICatalogValidator
+}
+
+func NewCatalogImportUsecase(cr repository.ICatalogRepository, cv validator.ICatalogValidator) ICatalogImportUsecase {
+	return &catalogImportUsecase{cr: cr, cv: cv}
+}
+
+// 見出し名が指定されない項目はこの名前で探す
+var defaultCatalogColumnMapping = model.CatalogColumnMapping{
+	Code:       "科目コード",
+	Name:       "科目名",
+	Instructor: "担当教員",
+	Credits:    "単位数",
+	Term:       "開講学期",
+	Slots:      "曜日時限",
+}
+
+var weekdayByKanji = map[rune]int{'日': 0, '月': 1, '火': 2, '水': 3, '木': 4, '金': 5, '土': 6}
+
+const weekdayKanji = "日月火水木金土"
+
+type catalogImportRow struct {
+	line   int
+	course model.CatalogCourse
+	errors []string
+}
+
+// 全行を検証し、エラーが1件もない場合のみカタログへ反映する。DryRunの場合は差分のみを返す
+func (cu *catalogImportUsecase) ImportCatalogCourses(r io.Reader, options model.CatalogImportOptions) (model.CatalogImportReport, error) {
+	if options.UniversityID == 0 {
+		return model.CatalogImportReport{}, errors.New("university_id is required")
+	}
+	rows, err := cu.parseCatalogRows(r, options)
+	if err != nil {
+		return model.CatalogImportReport{}, err
+	}
+
+	codes := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.course.Code != "" {
+			codes = append(codes, row.course.Code)
+		}
+	}
+	existing := []model.CatalogCourse{}
+	if err := cu.cr.GetCatalogCoursesByCodes(&existing, options.UniversityID, codes); err != nil {
+		return model.CatalogImportReport{}, err
+	}
+	existingByCode := make(map[string]model.CatalogCourse, len(existing))
+	for _, v := range existing {
+		existingByCode[v.Code] = v
+	}
+
+	report := model.CatalogImportReport{
+		UniversityID: options.UniversityID,
+		DryRun:       options.DryRun,
+		Lines:        make([]model.CatalogImportLineResult, 0, len(rows)),
+	}
+	upserts := []model.CatalogCourse{}
+	seen := map[string]int{}
+	for _, row := range rows {
+		result := model.CatalogImportLineResult{
+			Line: row.line,
+			Code: row.course.Code,
+			Name: row.course.Name,
+		}
+		if line, ok := seen[row.course.Code]; ok && row.course.Code != "" {
+			row.errors = append(row.errors, fmt.Sprintf("code %s is duplicated with line %d", row.course.Code, line))
+		}
+		seen[row.course.Code] = row.line
+
+		if len(row.errors) > 0 {
+			result.Action = model.CatalogImportError
+			result.Errors = row.errors
+			report.Errors++
+			report.Lines = append(report.Lines, result)
+			continue
+		}
+
+		if current, ok := existingByCode[row.course.Code]; ok {
+			result.Changes = diffCatalogCourse(current, row.course)
+			if len(result.Changes) == 0 {
+				result.Action = model.CatalogImportUnchanged
+				report.Unchanged++
+			} else {
+				result.Action = model.CatalogImportUpdate
+				report.Updates++
+				upserts = append(upserts, row.course)
+			}
+		} else {
+			result.Action = model.CatalogImportInsert
+			report.Inserts++
+			upserts = append(upserts, row.course)
+		}
+		report.Lines = append(report.Lines, result)
+	}
+
+	if options.DryRun || report.Errors > 0 || len(upserts) == 0 {
+		return report, nil
+	}
+	if err := cu.cr.UpsertCatalogCourses(upserts); err != nil {
+		return report, err
+	}
+	report.Applied = true
+	return report, nil
+}
+
+func (cu *catalogImportUsecase) parseCatalogRows(r io.Reader, options model.CatalogImportOptions) ([]catalogImportRow, error) {
+	switch strings.ToLower(options.Encoding) {
+	case "", "utf-8", "utf8":
+	case "shift_jis", "shift-jis", "sjis", "cp932":
+		r = transform.NewReader(r, japanese.ShiftJIS.NewDecoder())
+	default:
+		return nil, fmt.Errorf("unsupported encoding: %s", options.Encoding)
+	}
+
+	reader := csv.NewReader(skipBOM(r))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	switch strings.ToLower(options.Delimiter) {
+	case "", "csv":
+	case "tsv":
+		reader.Comma = '\t'
+	default:
+		return nil, fmt.Errorf("unsupported delimiter: %s", options.Delimiter)
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	mapping := options.Mapping
+	indexOf := func(name string, fallback string, required bool) (int, error) {
+		if name == "" {
+			name = fallback
+		}
+		if i, ok := columns[name]; ok {
+			return i, nil
+		}
+		if required {
+			return -1, fmt.Errorf("column %s was not found in header", name)
+		}
+		return -1, nil
+	}
+	codeIdx, err := indexOf(mapping.Code, defaultCatalogColumnMapping.Code, true)
+	if err != nil {
+		return nil, err
+	}
+	nameIdx, err := indexOf(mapping.Name, defaultCatalogColumnMapping.Name, true)
+	if err != nil {
+		return nil, err
+	}
+	instructorIdx, _ := indexOf(mapping.Instructor, defaultCatalogColumnMapping.Instructor, false)
+	creditsIdx, _ := indexOf(mapping.Credits, defaultCatalogColumnMapping.Credits, false)
+	termIdx, _ := indexOf(mapping.Term, defaultCatalogColumnMapping.Term, false)
+	slotsIdx, _ := indexOf(mapping.Slots, defaultCatalogColumnMapping.Slots, false)
+
+	rows := []catalogImportRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, catalogImportRow{line: parseErr.StartLine, errors: []string{parseErr.Err.Error()}})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlankRecord(record) {
+			continue
+		}
+
+		field := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		row := catalogImportRow{line: line}
+		row.course = model.CatalogCourse{
+			UniversityID: options.UniversityID,
+			Code:         width.Fold.String(field(codeIdx)),
+			Name:         field(nameIdx),
+		}
+		if v := field(instructorIdx); v != "" {
+			row.course.Instructor = &v
+		}
+		if v := field(termIdx); v != "" {
+			row.course.Term = &v
+		}
+		if v := field(creditsIdx); v != "" {
+			credits, err := parseCredits(v)
+			if err != nil {
+				row.errors = append(row.errors, err.Error())
+			}
+			row.course.Credits = credits
+		}
+		slots, err := parseSlots(field(slotsIdx))
+		if err != nil {
+			row.errors = append(row.errors, err.Error())
+		}
+		row.course.Slots = slots
+
+		if err := cu.cv.CatalogCourseValidate(row.course); err != nil {
+			row.errors = append(row.errors, flattenValidationError(err)...)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Excelが出力するBOMを取り除く
+func skipBOM(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && string(bom) == "\xef\xbb\xbf" {
+		br.Discard(3)
+	}
+	return br
+}
+
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func parseCredits(v string) (uint, error) {
+	f, err := strconv.ParseFloat(width.Fold.String(v), 64)
+	if err != nil || f < 0 || f != float64(uint(f)) {
+		return 0, fmt.Errorf("credits %q is not a valid number", v)
+	}
+	return uint(f), nil
+}
+
+// "月1,水2" や "火3・4"、"金1-2" のような曜日時限表記を解釈する。集中講義など曜日のないものは空とする
+func parseSlots(v string) ([]model.CatalogCourseSlot, error) {
+	v = width.Fold.String(v)
+	if v == "" || strings.Contains(v, "集中") || v == "他" || v == "-" {
+		return []model.CatalogCourseSlot{}, nil
+	}
+	tokens := strings.FieldsFunc(v, func(r rune) bool {
+		return r == ',' || r == '、' || r == '/' || r == ';' || r == ' ' || r == '　'
+	})
+
+	slots := []model.CatalogCourseSlot{}
+	day := -1
+	for _, token := range tokens {
+		for _, part := range strings.Split(token, "・") {
+			if part == "" {
+				continue
+			}
+			runes := []rune(part)
+			if d, ok := weekdayByKanji[runes[0]]; ok {
+				day = d
+				runes = runes[1:]
+			}
+			if day < 0 || len(runes) == 0 {
+				return nil, fmt.Errorf("slot %q is not valid", v)
+			}
+			from, to, found := strings.Cut(string(runes), "-")
+			start, err := strconv.Atoi(from)
+			if err != nil {
+				return nil, fmt.Errorf("slot %q is not valid", v)
+			}
+			end := start
+			if found {
+				if end, err = strconv.Atoi(to); err != nil || end < start {
+					return nil, fmt.Errorf("slot %q is not valid", v)
+				}
+			}
+			for period := start; period <= end; period++ {
+				slots = append(slots, model.CatalogCourseSlot{DayOfWeek: day, Period: period})
+			}
+		}
+	}
+	return slots, nil
+}
+
+func formatSlots(slots []model.CatalogCourseSlot) string {
+	labels := make([]string, 0, len(slots))
+	sorted := append([]model.CatalogCourseSlot{}, slots...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].DayOfWeek != sorted[j].DayOfWeek {
+			return sorted[i].DayOfWeek < sorted[j].DayOfWeek
+		}
+		return sorted[i].Period < sorted[j].Period
+	})
+	for _, slot := range sorted {
+		if slot.DayOfWeek < 0 || slot.DayOfWeek >= 7 {
+			continue
+		}
+		labels = append(labels, fmt.Sprintf("%c%d", []rune(weekdayKanji)[slot.DayOfWeek], slot.Period))
+	}
+	return strings.Join(labels, ",")
+}
+
+func diffCatalogCourse(current model.CatalogCourse, next model.CatalogCourse) map[string]model.CatalogImportChange {
+	changes := map[string]model.CatalogImportChange{}
+	if current.Name != next.Name {
+		changes["name"] = model.CatalogImportChange{Old: current.Name, New: next.Name}
+	}
+	if stringValue(current.Instructor) != stringValue(next.Instructor) {
+		changes["instructor"] = model.CatalogImportChange{Old: current.Instructor, New: next.Instructor}
+	}
+	if current.Credits != next.Credits {
+		changes["credits"] = model.CatalogImportChange{Old: current.Credits, New: next.Credits}
+	}
+	if stringValue(current.Term) != stringValue(next.Term) {
+		changes["term"] = model.CatalogImportChange{Old: current.Term, New: next.Term}
+	}
+	if oldSlots, newSlots := formatSlots(current.Slots), formatSlots(next.Slots); oldSlots != newSlots {
+		changes["slots"] = model.CatalogImportChange{Old: oldSlots, New: newSlots}
+	}
+	return changes
+}
+
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+func flattenValidationError(err error) []string {
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		return []string{err.Error()}
+	}
+	keys := make([]string, 0, len(errs))
+	for key := range errs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	messages := make([]string, 0, len(errs))
+	for _, key := range keys {
+		messages = append(messages, fmt.Sprintf("%s: %s", key, errs[key].Error()))
+	}
+	return messages
+}
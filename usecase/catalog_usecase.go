@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"backend/validator"
+	"errors"
+)
+
+type ICatalogUsecase interface {
+	SearchCatalogCourses(params model.CatalogCourseSearchParams, offset int, limit int) ([]model.CatalogCourseResponse, error)
+	GetCatalogCourseByID(catalogCourseId uint) (model.CatalogCourseResponse, error)
+	CreateCatalogCourse(course *model.CatalogCourse) (model.CatalogCourseResponse, error)
+	UpdateCatalogCourse(course *model.CatalogCourse, catalogCourseId uint) (model.CatalogCourseResponse, error)
+	DeleteCatalogCourseByID(catalogCourseId uint) error
+}
+
+type catalogUsecase struct {
+	cr repository.ICatalogRepository
+	cv validator.ICatalogValidator
+}
+
+func NewCatalogUsecase(cr repository.ICatalogRepository, cv validator.ICatalogValidator) ICatalogUsecase {
+	return &catalogUsecase{cr: cr, cv: cv}
+}
+
+func (cu *catalogUsecase) SearchCatalogCourses(params model.CatalogCourseSearchParams, offset int, limit int) ([]model.CatalogCourseResponse, error) {
+	var courses []model.CatalogCourse
+	if err := cu.cr.SearchCatalogCourses(&courses, params, offset, limit); err != nil {
+		return nil, err
+	}
+	resCourses := make([]model.CatalogCourseResponse, 0, len(courses))
+	for _, course := range courses {
+		resCourses = append(resCourses, toCatalogCourseResponse(course))
+	}
+	return resCourses, nil
+}
+
+func (cu *catalogUsecase) GetCatalogCourseByID(catalogCourseId uint) (model.CatalogCourseResponse, error) {
+	var course model.CatalogCourse
+	if err := cu.cr.GetCatalogCourseByID(&course, catalogCourseId); err != nil {
+		return model.CatalogCourseResponse{}, err
+	}
+	return toCatalogCourseResponse(course), nil
+}
+
+func (cu *catalogUsecase) CreateCatalogCourse(course *model.CatalogCourse) (model.CatalogCourseResponse, error) {
+	if course == nil {
+		return model.CatalogCourseResponse{}, errors.New("catalog course is nil")
+	}
+	if err := cu.cv.CatalogCourseValidate(*course); err != nil {
+		return model.CatalogCourseResponse{}, err
+	}
+	if err := cu.cr.CreateCatalogCourse(course); err != nil {
+		return model.CatalogCourseResponse{}, err
+	}
+	return toCatalogCourseResponse(*course), nil
+}
+
+func (cu *catalogUsecase) UpdateCatalogCourse(course *model.CatalogCourse, catalogCourseId uint) (model.CatalogCourseResponse, error) {
+	if course == nil {
+		return model.CatalogCourseResponse{}, errors.New("catalog course is nil")
+	}
+	if err := cu.cv.CatalogCourseValidate(*course); err != nil {
+		return model.CatalogCourseResponse{}, err
+	}
+	if err := cu.cr.UpdateCatalogCourse(course, catalogCourseId); err != nil {
+		return model.CatalogCourseResponse{}, err
+	}
+	return toCatalogCourseResponse(*course), nil
+}
+
+func (cu *catalogUsecase) DeleteCatalogCourseByID(catalogCourseId uint) error {
+	return cu.cr.DeleteCatalogCourseByID(catalogCourseId)
+}
+
+func toCatalogCourseResponse(course model.CatalogCourse) model.CatalogCourseResponse {
+	slots := make([]model.SlotResponse, 0, len(course.Slots))
+	for _, slot := range course.Slots {
+		slots = append(slots, model.SlotResponse{DayOfWeek: slot.DayOfWeek, Period: slot.Period})
+	}
+	return model.CatalogCourseResponse{
+		ID:           course.ID,
+		UniversityID: course.UniversityID,
+		Code:         course.Code,
+		Name:         course.Name,
+		Instructor:   course.Instructor,
+		Credits:      course.Credits,
+		Term:         course.Term,
+		Slots:        slots,
+	}
+}
@@ -3,56 +3,259 @@ package usecase
 import (
 	"backend/model"
 	"backend/repository"
+	"backend/validator"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
 )
 
 type ICommentUsecase interface {
 	CreateComment(comment *model.Comment) (model.CommentResponse, error)
-	GetCommentsByPlanID(planID uint) ([]model.CommentResponse, error)
+	GetCommentsByPlanID(planID uint, viewerID uint) ([]model.CommentResponse, error)
+	GetReplies(commentID uint, viewerID uint, offset int, limit int) ([]model.CommentResponse, error)
 	GetCommentsByUserID(userID uint) ([]model.CommentResponse, error)
+	UpdateComment(commentID uint, userID uint, content string) (model.CommentResponse, error)
+	GetCommentHistory(commentID uint) (model.CommentHistoryResponse, error)
 	DeleteComment(commentID uint, userID *uint) error
+	DeleteCommentWithToken(commentID uint, token string) error
 }
 
 type commentUsecase struct {
-	cr repository.ICommentRepository
+	cr     repository.ICommentRepository
+	pr     repository.IPlanRepository
+	ur     repository.IUserRepository
+	mr     repository.IModerationRepository
+	cv     validator.ICommentValidator
+	m      IModerator
+	n      INotifier
+	ep     IPlanEventPublisher
+	wp     IWebhookPublisher
+	policy CommentPolicy
 }
 
-func NewCommentUsecase(cr repository.ICommentRepository) ICommentUsecase {
-	return &commentUsecase{cr: cr}
+func NewCommentUsecase(
+	cr repository.ICommentRepository,
+	pr repository.IPlanRepository,
+	ur repository.IUserRepository,
+	mr repository.IModerationRepository,
+	cv validator.ICommentValidator,
+	m IModerator,
+	n INotifier,
+	ep IPlanEventPublisher,
+	wp IWebhookPublisher,
+	policy CommentPolicy,
+) ICommentUsecase {
+	return &commentUsecase{cr: cr, pr: pr, ur: ur, mr: mr, cv: cv, m: m, n: n, ep: ep, wp: wp, policy: policy}
 }
 
+// コメントの運用ルール
+type CommentPolicy struct {
+	EditWindow time.Duration // 投稿後に編集できる期間。0の場合は無期限
+}
+
+const defaultCommentEditWindow = 30 * time.Minute
+
+// 環境変数 COMMENT_EDIT_WINDOW (例: 15m, 24h, 0) から読み込む
+func NewCommentPolicy() CommentPolicy {
+	policy := CommentPolicy{EditWindow: defaultCommentEditWindow}
+	if v := os.Getenv("COMMENT_EDIT_WINDOW"); v != "" {
+		window, err := time.ParseDuration(v)
+		if err != nil || window < 0 {
+			log.Printf("invalid COMMENT_EDIT_WINDOW %q, using %s", v, defaultCommentEditWindow)
+			return policy
+		}
+		policy.EditWindow = window
+	}
+	return policy
+}
+
+// 編集できる期間を過ぎている場合に返す
+var ErrEditWindowClosed = errors.New("edit window has closed")
+
+// トップレベルのコメントを0として、返信できる深さの上限
+const maxCommentDepth = 3
+
+// コメント一覧で各コメントに含める返信の数。残りは返信の取得APIで読み込む
+const initialReplyCount = 3
+
+// 返信先が深すぎる場合に返す
+var ErrReplyTooDeep = errors.New("reply depth limit exceeded")
+
+// 返信先のコメントと異なる計画を指定した場合に返す
+var ErrReplyPlanMismatch = errors.New("parent comment belongs to another plan")
+
+// 匿名のコメントには削除用のトークンを発行し、レスポンスでのみ返す
 func (cu *commentUsecase) CreateComment(comment *model.Comment) (model.CommentResponse, error) {
+	comment.Content = strings.TrimSpace(comment.Content)
+	comment.Depth = 0
+	comment.EditedAt = nil
+	comment.DeleteTokenHash = nil
+	comment.Replies = nil
+	comment.Revisions = nil
+	comment.Mentions = nil
+	var parent model.Comment
+	if comment.ParentID != nil {
+		if err := cu.cr.GetCommentByID(&parent, *comment.ParentID); err != nil {
+			return model.CommentResponse{}, err
+		}
+		if comment.PlanID != 0 && comment.PlanID != parent.PlanID {
+			return model.CommentResponse{}, ErrReplyPlanMismatch
+		}
+		if parent.Depth >= maxCommentDepth {
+			return model.CommentResponse{}, ErrReplyTooDeep
+		}
+		comment.PlanID = parent.PlanID
+		comment.Depth = parent.Depth + 1
+	}
+	if err := cu.cv.CommentValidate(*comment); err != nil {
+		return model.CommentResponse{}, err
+	}
+	var viewerID uint
+	if comment.UserID != nil {
+		viewerID = *comment.UserID
+	}
+	var plan model.Plan
+	if err := getVisiblePlan(cu.pr, &plan, comment.PlanID, viewerID); err != nil {
+		return model.CommentResponse{}, err
+	}
+
+	var deleteToken string
+	if comment.UserID == nil {
+		token, err := generateToken()
+		if err != nil {
+			return model.CommentResponse{}, err
+		}
+		hash := hashToken(token)
+		deleteToken = token
+		comment.DeleteTokenHash = &hash
+	}
+	comment.Status = model.CommentStatusVisible
+	held, err := cu.moderate(comment.UserID, comment.Content)
+	if err != nil {
+		return model.CommentResponse{}, err
+	}
+	if held {
+		comment.Status = model.CommentStatusPending
+	}
+	mentions, err := resolveMentions(cu.ur, comment.Content, comment.UserID)
+	if err != nil {
+		return model.CommentResponse{}, err
+	}
+	comment.Mentions = mentions
 	if err := cu.cr.CreateComment(comment); err != nil {
 		return model.CommentResponse{}, err
 	}
+	if held {
+		if err := cu.recordHold(*comment); err != nil {
+			return model.CommentResponse{}, err
+		}
+	} else {
+		cu.notifyComment(*comment, plan, parent)
+		cu.ep.Publish(comment.PlanID, model.PlanEventCommentCreated, toCommentResponse(*comment, 0))
+		cu.wp.Publish(comment.PlanID, model.WebhookEventCommentCreated, toCommentResponse(*comment, 0))
+	}
+	res := toCommentResponse(*comment, viewerID)
+	res.DeleteToken = deleteToken
+	return res, nil
+}
 
-	response := model.CommentResponse{
-		ID:        comment.ID,
-		Content:   comment.Content,
-		PlanID:    comment.PlanID,
-		UserID:    comment.UserID,
-		CreatedAt: comment.CreatedAt,
+// NGワードに該当した場合は保存はするが確認待ちにする
+func (cu *commentUsecase) moderate(userID *uint, content string) (bool, error) {
+	err := cu.m.Moderate(ModerationTarget{Kind: ModerationKindComment, UserID: userID, Text: content})
+	if errors.Is(err, ErrContentHeld) {
+		return true, nil
 	}
+	return false, err
+}
 
-	return response, nil
+// 返信先の投稿者、言及されたユーザー、計画の作成者の順に優先して1件ずつ通知する
+func (cu *commentUsecase) notifyComment(comment model.Comment, plan model.Plan, parent model.Comment) {
+	base := model.Notification{ActorID: comment.UserID, PlanID: &comment.PlanID, CommentID: &comment.ID}
+	notifications := []model.Notification{}
+	if parent.UserID != nil {
+		reply := base
+		reply.UserID = *parent.UserID
+		reply.Type = model.NotificationTypeReply
+		notifications = append(notifications, reply)
+	}
+	for _, mention := range comment.Mentions {
+		notification := base
+		notification.UserID = mention.UserID
+		notification.Type = model.NotificationTypeMention
+		notifications = append(notifications, notification)
+	}
+	onPlan := base
+	onPlan.UserID = plan.UserID
+	onPlan.Type = model.NotificationTypeComment
+	notifications = append(notifications, onPlan)
+	cu.n.Notify(notifications...)
 }
 
-func (cu *commentUsecase) GetCommentsByPlanID(planID uint) ([]model.CommentResponse, error) {
+func (cu *commentUsecase) recordHold(comment model.Comment) error {
+	commentId := comment.ID
+	return cu.mr.CreateAction(&model.ModerationAction{
+		CommentID: &commentId,
+		Action:    model.ModerationActionHold,
+		Content:   comment.Content,
+	})
+}
+
+// トップレベルのコメントは新しい順、返信は古い順の木構造で返す
+// viewerIDは自分のリアクションを判定するために使う。匿名の場合は0
+func (cu *commentUsecase) GetCommentsByPlanID(planID uint, viewerID uint) ([]model.CommentResponse, error) {
+	var plan model.Plan
+	if err := getVisiblePlan(cu.pr, &plan, planID, viewerID); err != nil {
+		return nil, err
+	}
 	comments, err := cu.cr.GetCommentsByPlanID(planID)
 	if err != nil {
 		return nil, err
 	}
 
-	responses := make([]model.CommentResponse, len(comments))
-	for i, comment := range comments {
-		responses[i] = model.CommentResponse{
-			ID:        comment.ID,
-			Content:   comment.Content,
-			PlanID:    comment.PlanID,
-			UserID:    comment.UserID,
-			CreatedAt: comment.CreatedAt,
-		}
+	roots, _ := buildCommentTree(comments)
+	responses := make([]model.CommentResponse, 0, len(roots))
+	for _, root := range roots {
+		responses = append(responses, root.response(initialReplyCount, viewerID))
+	}
+	return responses, nil
+}
+
+// 長いスレッドの続きを読み込むため、コメント直下の返信をページ単位で返す
+func (cu *commentUsecase) GetReplies(commentID uint, viewerID uint, offset int, limit int) ([]model.CommentResponse, error) {
+	var parent model.Comment
+	if err := cu.cr.GetCommentByID(&parent, commentID); err != nil {
+		return nil, err
+	}
+	var plan model.Plan
+	if err := getVisiblePlan(cu.pr, &plan, parent.PlanID, viewerID); err != nil {
+		return nil, err
+	}
+	// 返信の返信は、直下の返信の階層より下にしかない
+	levels := maxCommentDepth - (parent.Depth + 1)
+	replies := []model.Comment{}
+	if err := cu.cr.GetReplies(&replies, commentID, levels, offset, limit); err != nil {
+		return nil, err
+	}
+	replyIDs := make([]uint, 0, len(replies))
+	for _, reply := range replies {
+		replyIDs = append(replyIDs, reply.ID)
+	}
+	descendants := []model.Comment{}
+	if err := cu.cr.GetDescendants(&descendants, replyIDs); err != nil {
+		return nil, err
 	}
 
+	_, nodes := buildCommentTree(append(replies, descendants...))
+	responses := make([]model.CommentResponse, 0, len(replies))
+	for _, reply := range replies {
+		node := nodes[reply.ID]
+		node.children = pruneHiddenComments(node.children)
+		responses = append(responses, node.response(initialReplyCount, viewerID))
+	}
 	return responses, nil
 }
 
@@ -64,18 +267,227 @@ func (cu *commentUsecase) GetCommentsByUserID(userID uint) ([]model.CommentRespo
 
 	responses := make([]model.CommentResponse, len(comments))
 	for i, comment := range comments {
-		responses[i] = model.CommentResponse{
-			ID:        comment.ID,
-			Content:   comment.Content,
-			PlanID:    comment.PlanID,
-			UserID:    comment.UserID,
-			CreatedAt: comment.CreatedAt,
-		}
+		responses[i] = toCommentResponse(comment, userID)
 	}
 
 	return responses, nil
 }
 
+// 投稿者本人が編集期間内に限り編集できる。匿名のコメントは編集できない
+func (cu *commentUsecase) UpdateComment(commentID uint, userID uint, content string) (model.CommentResponse, error) {
+	var comment model.Comment
+	if err := cu.cr.GetCommentByID(&comment, commentID); err != nil {
+		return model.CommentResponse{}, err
+	}
+	if comment.UserID == nil || *comment.UserID != userID {
+		return model.CommentResponse{}, ErrForbidden
+	}
+	if cu.policy.EditWindow > 0 && time.Since(comment.CreatedAt) > cu.policy.EditWindow {
+		return model.CommentResponse{}, ErrEditWindowClosed
+	}
+	content = strings.TrimSpace(content)
+	if content == comment.Content {
+		return toCommentResponse(comment, userID), nil
+	}
+	edited := comment
+	edited.Content = content
+	if err := cu.cv.CommentValidate(edited); err != nil {
+		return model.CommentResponse{}, err
+	}
+	held, err := cu.moderate(comment.UserID, content)
+	if err != nil {
+		return model.CommentResponse{}, err
+	}
+	// 非表示にされたコメントは編集しても非表示のまま
+	held = held && comment.Status == model.CommentStatusVisible
+	if held {
+		comment.Status = model.CommentStatusPending
+	}
+	mentions, err := resolveMentions(cu.ur, content, comment.UserID)
+	if err != nil {
+		return model.CommentResponse{}, err
+	}
+	if err := cu.cr.UpdateCommentContent(&comment, content, mentions); err != nil {
+		return model.CommentResponse{}, err
+	}
+	if held {
+		if err := cu.recordHold(comment); err != nil {
+			return model.CommentResponse{}, err
+		}
+	}
+	publishCommentChange(cu.ep, comment)
+	return toCommentResponse(comment, userID), nil
+}
+
+func (cu *commentUsecase) GetCommentHistory(commentID uint) (model.CommentHistoryResponse, error) {
+	var comment model.Comment
+	if err := cu.cr.GetCommentByID(&comment, commentID); err != nil {
+		return model.CommentHistoryResponse{}, err
+	}
+	var revisions []model.CommentRevision
+	if err := cu.cr.GetRevisions(&revisions, commentID); err != nil {
+		return model.CommentHistoryResponse{}, err
+	}
+	res := model.CommentHistoryResponse{
+		Comment:   toCommentResponse(comment, 0),
+		Revisions: make([]model.CommentRevisionResponse, 0, len(revisions)),
+	}
+	for i, revision := range revisions {
+		res.Revisions = append(res.Revisions, model.CommentRevisionResponse{
+			Version:    i + 1,
+			Content:    revision.Content,
+			WrittenAt:  revision.WrittenAt,
+			ReplacedAt: revision.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
 func (cu *commentUsecase) DeleteComment(commentID uint, userID *uint) error {
-	return cu.cr.DeleteComment(commentID, userID)
+	var comment model.Comment
+	if err := cu.cr.GetCommentByID(&comment, commentID); err != nil {
+		return err
+	}
+	ids, err := commentThreadIDs(cu.cr, commentID)
+	if err != nil {
+		return err
+	}
+	if err := cu.cr.DeleteComment(commentID, userID); err != nil {
+		return err
+	}
+	publishCommentsDeleted(cu.ep, comment.PlanID, ids)
+	return nil
+}
+
+// 匿名で投稿した人が投稿時に受け取ったトークンで削除する
+func (cu *commentUsecase) DeleteCommentWithToken(commentID uint, token string) error {
+	var comment model.Comment
+	if err := cu.cr.GetCommentByID(&comment, commentID); err != nil {
+		return err
+	}
+	if comment.DeleteTokenHash == nil || token == "" ||
+		subtle.ConstantTimeCompare([]byte(*comment.DeleteTokenHash), []byte(hashToken(token))) != 1 {
+		return ErrForbidden
+	}
+	ids, err := commentThreadIDs(cu.cr, commentID)
+	if err != nil {
+		return err
+	}
+	if err := cu.cr.DeleteComment(commentID, nil); err != nil {
+		return err
+	}
+	publishCommentsDeleted(cu.ep, comment.PlanID, ids)
+	return nil
+}
+
+// 削除するコメントと、一緒に削除される返信のID。削除後は返信を辿れないため削除前に集める
+func commentThreadIDs(cr repository.ICommentRepository, commentID uint) ([]uint, error) {
+	descendants := []model.Comment{}
+	if err := cr.GetDescendants(&descendants, []uint{commentID}); err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(descendants)+1)
+	ids = append(ids, commentID)
+	for _, descendant := range descendants {
+		ids = append(ids, descendant.ID)
+	}
+	return ids, nil
+}
+
+func publishCommentsDeleted(ep IPlanEventPublisher, planID uint, ids []uint) {
+	for _, id := range ids {
+		ep.Publish(planID, model.PlanEventCommentDeleted, model.CommentDeletedEvent{ID: id, PlanID: planID})
+	}
+}
+
+// 公開中のコメントは新しい内容を、それ以外は閲覧者から見えなくなったことを配信する
+func publishCommentChange(ep IPlanEventPublisher, comment model.Comment) {
+	if comment.Status == model.CommentStatusVisible {
+		ep.Publish(comment.PlanID, model.PlanEventCommentUpdated, toCommentResponse(comment, 0))
+		return
+	}
+	ep.Publish(comment.PlanID, model.PlanEventCommentDeleted, model.CommentDeletedEvent{ID: comment.ID, PlanID: comment.PlanID})
+}
+
+type commentNode struct {
+	comment  model.Comment
+	children []*commentNode
+}
+
+// 計画の全コメントから木を組み立てる。commentsの順序がトップレベルの順序になる
+func buildCommentTree(comments []model.Comment) ([]*commentNode, map[uint]*commentNode) {
+	nodes := make(map[uint]*commentNode, len(comments))
+	for _, comment := range comments {
+		nodes[comment.ID] = &commentNode{comment: comment}
+	}
+	roots := []*commentNode{}
+	for _, comment := range comments {
+		node := nodes[comment.ID]
+		if comment.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+		if parent, ok := nodes[*comment.ParentID]; ok {
+			parent.children = append(parent.children, node)
+		}
+	}
+	for _, node := range nodes {
+		sort.Slice(node.children, func(i, j int) bool {
+			a, b := node.children[i].comment, node.children[j].comment
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+			return a.ID < b.ID
+		})
+	}
+	return pruneHiddenComments(roots), nodes
+}
+
+// 公開されていないコメントは、公開されている返信がある場合だけ本文を伏せて残す
+func pruneHiddenComments(nodes []*commentNode) []*commentNode {
+	kept := make([]*commentNode, 0, len(nodes))
+	for _, node := range nodes {
+		node.children = pruneHiddenComments(node.children)
+		if node.comment.Status == model.CommentStatusVisible || len(node.children) > 0 {
+			kept = append(kept, node)
+		}
+	}
+	return kept
+}
+
+// 各階層で最初のreplyLimit件の返信だけを含める
+func (n *commentNode) response(replyLimit int, viewerID uint) model.CommentResponse {
+	res := toCommentResponse(n.comment, viewerID)
+	if n.comment.Status != model.CommentStatusVisible {
+		res.Content = ""
+		res.Mentions = []model.MentionSpan{}
+		res.Reactions = []model.ReactionSummary{}
+	}
+	res.ReplyCount = len(n.children)
+	res.HasMoreReplies = len(n.children) > replyLimit
+	for i, child := range n.children {
+		if i >= replyLimit {
+			break
+		}
+		res.Replies = append(res.Replies, child.response(replyLimit, viewerID))
+	}
+	return res
+}
+
+func toCommentResponse(comment model.Comment, viewerID uint) model.CommentResponse {
+	return model.CommentResponse{
+		ID:        comment.ID,
+		Content:   comment.Content,
+		PlanID:    comment.PlanID,
+		UserID:    comment.UserID,
+		ParentID:  comment.ParentID,
+		Depth:     comment.Depth,
+		Status:    comment.Status,
+		Edited:    comment.EditedAt != nil,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+		Replies:   []model.CommentResponse{},
+		Mentions:  toMentionSpans(comment.Mentions),
+		Reactions: toReactionSummaries(comment.Reactions, viewerID),
+	}
 }
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/validator"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// 1 ← 2 ← 3 の返信と、別のスレッドの4
+func newCommentThreadForTest() *fakeCommentRepository {
+	userId := uint(10)
+	tokenHash := hashToken("token")
+	parent := func(id uint) *uint { return &id }
+	return &fakeCommentRepository{comments: map[uint]model.Comment{
+		1: {ID: 1, PlanID: 5, UserID: &userId, DeleteTokenHash: &tokenHash},
+		2: {ID: 2, PlanID: 5, ParentID: parent(1)},
+		3: {ID: 3, PlanID: 5, ParentID: parent(2)},
+		4: {ID: 4, PlanID: 5},
+	}}
+}
+
+func TestDeleteCommentPublishesReplies(t *testing.T) {
+	userId := uint(10)
+	tests := []struct {
+		name      string
+		commentID uint
+		delete    func(cu ICommentUsecase, mu IModerationUsecase, commentID uint) error
+		wantIDs   []uint
+	}{
+		{"投稿者による削除", 1, func(cu ICommentUsecase, mu IModerationUsecase, id uint) error {
+			return cu.DeleteComment(id, &userId)
+		}, []uint{1, 2, 3}},
+		{"トークンによる削除", 1, func(cu ICommentUsecase, mu IModerationUsecase, id uint) error {
+			return cu.DeleteCommentWithToken(id, "token")
+		}, []uint{1, 2, 3}},
+		{"モデレーターによる削除", 1, func(cu ICommentUsecase, mu IModerationUsecase, id uint) error {
+			_, err := mu.DeleteComment(99, id, nil)
+			return err
+		}, []uint{1, 2, 3}},
+		{"返信の削除は親を含めない", 2, func(cu ICommentUsecase, mu IModerationUsecase, id uint) error {
+			_, err := mu.DeleteComment(99, id, nil)
+			return err
+		}, []uint{2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := newCommentThreadForTest()
+			ep := &fakePublisher{}
+			cu := NewCommentUsecase(cr, nil, nil, nil, nil, nil, nil, ep, &fakePublisher{}, CommentPolicy{})
+			mu := NewModerationUsecase(&fakeModerationRepository{cr: cr}, cr, nil, nil, nil, ep, &fakePublisher{})
+			if err := tt.delete(cu, mu, tt.commentID); err != nil {
+				t.Fatalf("delete: %v", err)
+			}
+			gotIDs := []uint{}
+			for _, event := range ep.events {
+				if event.eventType != model.PlanEventCommentDeleted || event.planId != 5 {
+					t.Fatalf("unexpected event %+v", event)
+				}
+				gotIDs = append(gotIDs, event.data.(model.CommentDeletedEvent).ID)
+			}
+			slices.Sort(gotIDs)
+			if !slices.Equal(gotIDs, tt.wantIDs) {
+				t.Fatalf("deleted events = %v, want %v", gotIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func newCommentUsecaseForTest(cr *fakeCommentRepository, policy CommentPolicy) ICommentUsecase {
+	pr := &fakePlanRepository{plans: map[uint]model.Plan{
+		5: {ID: 5, UserID: 20, Visibility: model.PlanVisibilityPublic},
+		6: {ID: 6, UserID: 20, Visibility: model.PlanVisibilityPublic},
+	}}
+	ur := &fakeUserRepository{users: map[uint]model.User{}}
+	return NewCommentUsecase(cr, pr, ur, nil, validator.NewCommentValidator(), &fakeModerator{},
+		&fakeNotifier{}, &fakePublisher{}, &fakePublisher{}, policy)
+}
+
+func TestCreateCommentDepth(t *testing.T) {
+	parent := func(id uint) *uint { return &id }
+	tests := []struct {
+		name      string
+		planID    uint
+		parentID  *uint
+		wantDepth int
+		wantErr   error
+	}{
+		{"トップレベルのコメント", 5, nil, 0, nil},
+		{"トップレベルへの返信", 5, parent(1), 1, nil},
+		{"上限の深さまで返信できる", 5, parent(12), maxCommentDepth, nil},
+		{"上限の深さのコメントには返信できない", 5, parent(13), 0, ErrReplyTooDeep},
+		{"計画を省略した返信は返信先の計画になる", 0, parent(1), 1, nil},
+		{"返信先と異なる計画", 6, parent(1), 0, ErrReplyPlanMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &fakeCommentRepository{comments: map[uint]model.Comment{
+				1:  {ID: 1, PlanID: 5, Depth: 0},
+				12: {ID: 12, PlanID: 5, Depth: maxCommentDepth - 1},
+				13: {ID: 13, PlanID: 5, Depth: maxCommentDepth},
+			}}
+			cu := newCommentUsecaseForTest(cr, CommentPolicy{})
+			userID := uint(10)
+			comment := &model.Comment{Content: "よろしくお願いします", PlanID: tt.planID, ParentID: tt.parentID, UserID: &userID, Depth: 99}
+			res, err := cu.CreateComment(comment)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(cr.comments) != 3 {
+					t.Fatalf("comment was created")
+				}
+				return
+			}
+			if res.Depth != tt.wantDepth || comment.Depth != tt.wantDepth {
+				t.Fatalf("depth = %d, want %d", res.Depth, tt.wantDepth)
+			}
+			if comment.PlanID != 5 {
+				t.Fatalf("plan_id = %d, want 5", comment.PlanID)
+			}
+		})
+	}
+}
+
+func TestUpdateCommentEditWindow(t *testing.T) {
+	author := uint(10)
+	tests := []struct {
+		name    string
+		window  time.Duration
+		userID  uint
+		age     time.Duration
+		anon    bool
+		wantErr error
+	}{
+		{"編集期間内", 30 * time.Minute, author, 10 * time.Minute, false, nil},
+		{"編集期間を過ぎている", 30 * time.Minute, author, 31 * time.Minute, false, ErrEditWindowClosed},
+		{"期間が0の場合は無期限", 0, author, 365 * 24 * time.Hour, false, nil},
+		{"投稿者以外は編集できない", 30 * time.Minute, 11, time.Minute, false, ErrForbidden},
+		{"匿名のコメントは編集できない", 30 * time.Minute, author, time.Minute, true, ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment := model.Comment{ID: 1, PlanID: 5, Content: "編集前", UserID: &author,
+				Status: model.CommentStatusVisible, CreatedAt: time.Now().Add(-tt.age)}
+			if tt.anon {
+				comment.UserID = nil
+			}
+			cr := &fakeCommentRepository{comments: map[uint]model.Comment{1: comment}}
+			cu := newCommentUsecaseForTest(cr, CommentPolicy{EditWindow: tt.window})
+			_, err := cu.UpdateComment(1, tt.userID, "編集後")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			wantContent := "編集後"
+			if tt.wantErr != nil {
+				wantContent = "編集前"
+			}
+			if got := cr.comments[1].Content; got != wantContent {
+				t.Fatalf("content = %q, want %q", got, wantContent)
+			}
+		})
+	}
+}
+
+func TestNewCommentPolicy(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"未設定は既定の期間", "", defaultCommentEditWindow},
+		{"指定した期間", "15m", 15 * time.Minute},
+		{"0は無期限", "0", 0},
+		{"不正な値は既定の期間", "abc", defaultCommentEditWindow},
+		{"負の値は既定の期間", "-5m", defaultCommentEditWindow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("COMMENT_EDIT_WINDOW", tt.value)
+			if got := NewCommentPolicy().EditWindow; got != tt.want {
+				t.Fatalf("EditWindow = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,70 +3,232 @@ package usecase
 import (
 	"backend/model"
 	"backend/repository"
+	"backend/validator"
+	"errors"
+	"fmt"
 )
 
 type ICourseUsecase interface {
-	GetAllCourses(planId uint) ([]model.CourseResponse, error)
-	CreateCourses(courses []model.Course) ([]model.CourseResponse, error)
-	UpdateCourse(course *model.Course, courseId int) (model.CourseResponse, error)
-	DeleteCourseByID(courseId uint) error
+	GetAllCourses(userId uint, planId uint) ([]model.CourseResponse, error)
+	CreateCourses(userId uint, courses []model.Course) ([]model.CourseResponse, error)
+	UpdateCourse(userId uint, course *model.Course, courseId int) (model.CourseResponse, error)
+	DeleteCourseByID(userId uint, courseId uint) error
+	MoveCourseToTerm(userId uint, courseId uint, planTermId *uint) (model.CourseResponse, error)
 }
 
 type courseUsecase struct {
-	cr repository.ICourseRepository
+	cr  repository.ICourseRepository
+	ctr repository.ICatalogRepository
+	tr  repository.ITermRepository
+	pr  repository.IPlanRepository
+	cv  validator.ICourseValidator
 }
 
-func NewCourseUsecase(cr repository.ICourseRepository) ICourseUsecase {
-	return &courseUsecase{cr}
+func NewCourseUsecase(cr repository.ICourseRepository, ctr repository.ICatalogRepository, tr repository.ITermRepository, pr repository.IPlanRepository, cv validator.ICourseValidator) ICourseUsecase {
+	return &courseUsecase{cr, ctr, tr, pr, cv}
 }
 
-func (cu *courseUsecase) GetAllCourses(planId uint) ([]model.CourseResponse, error) {
+// 科目の入力内容が不正な場合に返す
+var ErrInvalidCourse = errors.New("invalid course")
+
+// 非公開の計画の科目は作成者のみ取得できる。userIdは未ログインの場合0
+func (cu *courseUsecase) GetAllCourses(userId uint, planId uint) ([]model.CourseResponse, error) {
+	var plan model.Plan
+	if err := getVisiblePlan(cu.pr, &plan, planId, userId); err != nil {
+		return nil, err
+	}
 	courses := []model.Course{}
 	if err := cu.cr.GetAllCourses(&courses, planId); err != nil {
 		return nil, err
 	}
 	resCourses := []model.CourseResponse{}
 	for _, v := range courses {
-		c := model.CourseResponse{
-			ID:      v.ID,
-			Name:    v.Name,
-			Content: v.Content,
-		}
-		resCourses = append(resCourses, c)
+		resCourses = append(resCourses, toCourseResponse(v))
 	}
 	return resCourses, nil
 }
 
-func (cu *courseUsecase) CreateCourses(courses []model.Course) ([]model.CourseResponse, error) {
+// 計画の作成者のみ追加できる。名前などはカタログの内容で補完してから検証する
+func (cu *courseUsecase) CreateCourses(userId uint, courses []model.Course) ([]model.CourseResponse, error) {
+	checked := map[uint]bool{}
+	for _, course := range courses {
+		if !checked[course.PlanID] {
+			if err := cu.checkPlanOwner(userId, course.PlanID); err != nil {
+				return nil, err
+			}
+			checked[course.PlanID] = true
+		}
+		if course.PlanTermID != nil {
+			if err := cu.checkPlanTerm(*course.PlanTermID, course.PlanID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := cu.applyCatalogCourses(courses); err != nil {
+		return nil, err
+	}
+	for _, course := range courses {
+		if err := cu.cv.CourseValidate(course); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCourse, err)
+		}
+	}
 	if err := cu.cr.CreateCourses(&courses); err != nil {
 		return nil, err
 	}
 	resCourses := []model.CourseResponse{}
 	for _, v := range courses {
-		c := model.CourseResponse{
-			ID:      v.ID,
-			Name:    v.Name,
-			Content: v.Content,
-		}
-		resCourses = append(resCourses, c)
+		resCourses = append(resCourses, toCourseResponse(v))
 	}
 	return resCourses, nil
 }
 
-func (cu *courseUsecase) UpdateCourse(course *model.Course, courseId int) (model.CourseResponse, error) {
+// 計画の作成者のみ更新できる。所属する計画・学期は変更しない(学期の移動はMoveCourseToTermで行う)
+func (cu *courseUsecase) UpdateCourse(userId uint, course *model.Course, courseId int) (model.CourseResponse, error) {
+	stored := model.Course{}
+	if err := cu.cr.GetCourseByID(&stored, uint(courseId)); err != nil {
+		return model.CourseResponse{}, err
+	}
+	if err := cu.checkPlanOwner(userId, stored.PlanID); err != nil {
+		return model.CourseResponse{}, err
+	}
+	course.PlanID = stored.PlanID
+	course.PlanTermID = stored.PlanTermID
+	if course.CatalogCourseID != nil {
+		courses := []model.Course{*course}
+		if err := cu.applyCatalogCourses(courses); err != nil {
+			return model.CourseResponse{}, err
+		}
+		*course = courses[0]
+	}
+	if err := cu.cv.CourseUpdateValidate(*course); err != nil {
+		return model.CourseResponse{}, fmt.Errorf("%w: %v", ErrInvalidCourse, err)
+	}
 	if err := cu.cr.UpdateCourse(course, courseId); err != nil {
 		return model.CourseResponse{}, err
 	}
-	return model.CourseResponse{
-		ID:      uint(courseId),
-		Name:    course.Name,
-		Content: course.Content,
-	}, nil
+	return toCourseResponse(*course), nil
 }
 
-func (cu *courseUsecase) DeleteCourseByID(courseId uint) error {
+// 計画の作成者のみ削除できる
+func (cu *courseUsecase) DeleteCourseByID(userId uint, courseId uint) error {
+	course := model.Course{}
+	if err := cu.cr.GetCourseByID(&course, courseId); err != nil {
+		return err
+	}
+	if err := cu.checkPlanOwner(userId, course.PlanID); err != nil {
+		return err
+	}
 	if err := cu.cr.DeleteCourseByID(courseId); err != nil {
 		return err
 	}
 	return nil
 }
+
+// 計画の作成者のみ移動できる。移動先の学期は同じ計画のものに限る
+func (cu *courseUsecase) MoveCourseToTerm(userId uint, courseId uint, planTermId *uint) (model.CourseResponse, error) {
+	course := model.Course{}
+	if err := cu.cr.GetCourseByID(&course, courseId); err != nil {
+		return model.CourseResponse{}, err
+	}
+	if err := cu.checkPlanOwner(userId, course.PlanID); err != nil {
+		return model.CourseResponse{}, err
+	}
+	if planTermId != nil {
+		if err := cu.checkPlanTerm(*planTermId, course.PlanID); err != nil {
+			return model.CourseResponse{}, err
+		}
+	}
+	if err := cu.cr.UpdateCourseTerm(courseId, planTermId); err != nil {
+		return model.CourseResponse{}, err
+	}
+	course.PlanTermID = planTermId
+	return toCourseResponse(course), nil
+}
+
+func (cu *courseUsecase) checkPlanOwner(userId uint, planId uint) error {
+	var plan model.Plan
+	if err := cu.pr.GetPlanByID(&plan, planId); err != nil {
+		return err
+	}
+	if plan.UserID != userId {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// 学期が指定の計画のものでなければErrInvalidCourseを返す
+func (cu *courseUsecase) checkPlanTerm(planTermId uint, planId uint) error {
+	term := model.PlanTerm{}
+	if err := cu.tr.GetPlanTermByID(&term, planTermId); err != nil {
+		return err
+	}
+	if term.PlanID != planId {
+		return fmt.Errorf("%w: plan term belongs to another plan", ErrInvalidCourse)
+	}
+	return nil
+}
+
+// カタログを参照する科目は未入力の項目をカタログの内容で補完する
+func (cu *courseUsecase) applyCatalogCourses(courses []model.Course) error {
+	ids := []uint{}
+	for _, course := range courses {
+		if course.CatalogCourseID != nil {
+			ids = append(ids, *course.CatalogCourseID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	catalogCourses := []model.CatalogCourse{}
+	if err := cu.ctr.GetCatalogCoursesByIDs(&catalogCourses, ids); err != nil {
+		return err
+	}
+	catalogById := make(map[uint]model.CatalogCourse, len(catalogCourses))
+	for _, v := range catalogCourses {
+		catalogById[v.ID] = v
+	}
+
+	for i := range courses {
+		if courses[i].CatalogCourseID == nil {
+			continue
+		}
+		catalogCourse, ok := catalogById[*courses[i].CatalogCourseID]
+		if !ok {
+			return fmt.Errorf("catalog course %d does not exist", *courses[i].CatalogCourseID)
+		}
+		if courses[i].Name == "" {
+			courses[i].Name = catalogCourse.Name
+		}
+		if courses[i].Credits == 0 {
+			courses[i].Credits = catalogCourse.Credits
+		}
+		if len(courses[i].Slots) == 0 {
+			slots := make([]model.CourseSlot, 0, len(catalogCourse.Slots))
+			for _, slot := range catalogCourse.Slots {
+				slots = append(slots, model.CourseSlot{DayOfWeek: slot.DayOfWeek, Period: slot.Period})
+			}
+			courses[i].Slots = slots
+		}
+	}
+	return nil
+}
+
+func toCourseResponse(course model.Course) model.CourseResponse {
+	slots := make([]model.SlotResponse, 0, len(course.Slots))
+	for _, slot := range course.Slots {
+		slots = append(slots, model.SlotResponse{DayOfWeek: slot.DayOfWeek, Period: slot.Period})
+	}
+	return model.CourseResponse{
+		ID:              course.ID,
+		Name:            course.Name,
+		Content:         course.Content,
+		Credits:         course.Credits,
+		Category:        course.Category,
+		Room:            course.Room,
+		Color:           course.Color,
+		CatalogCourseID: course.CatalogCourseID,
+		PlanTermID:      course.PlanTermID,
+		Slots:           slots,
+	}
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/validator"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func newCourseUsecaseForTest() (*courseUsecase, *fakeCourseRepository) {
+	pr := &fakePlanRepository{plans: map[uint]model.Plan{
+		1: {ID: 1, UserID: 10},
+		2: {ID: 2, UserID: 20},
+	}}
+	termId := uint(100)
+	cr := &fakeCourseRepository{courses: map[uint]model.Course{
+		5: {ID: 5, Name: "線形代数", PlanID: 1, PlanTermID: &termId},
+	}}
+	tr := &fakeTermRepository{planTerms: map[uint]model.PlanTerm{
+		100: {ID: 100, PlanID: 1},
+		200: {ID: 200, PlanID: 2},
+	}}
+	cu := NewCourseUsecase(cr, nil, tr, pr, validator.NewCourseValidator()).(*courseUsecase)
+	return cu, cr
+}
+
+func TestCreateCoursesChecksPlanOwnerAndTerm(t *testing.T) {
+	uintPtr := func(v uint) *uint { return &v }
+	tests := []struct {
+		name       string
+		userId     uint
+		planId     uint
+		planTermId *uint
+		wantErr    error
+	}{
+		{"作成者は追加できる", 10, 1, uintPtr(100), nil},
+		{"学期未割り当てでも追加できる", 10, 1, nil, nil},
+		{"他のユーザーの計画には追加できない", 20, 1, nil, ErrForbidden},
+		{"他の計画の学期は指定できない", 10, 1, uintPtr(200), ErrInvalidCourse},
+		{"存在しない学期", 10, 1, uintPtr(300), gorm.ErrRecordNotFound},
+		{"存在しない計画", 10, 3, nil, gorm.ErrRecordNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cu, cr := newCourseUsecaseForTest()
+			courses := []model.Course{{Name: "解析学", PlanID: tt.planId, PlanTermID: tt.planTermId}}
+			_, err := cu.CreateCourses(tt.userId, courses)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && len(cr.created) > 0 {
+				t.Fatalf("courses were created: %v", cr.created)
+			}
+		})
+	}
+}
+
+func TestUpdateCourseKeepsPlanAndTerm(t *testing.T) {
+	otherTerm := uint(200)
+	tests := []struct {
+		name    string
+		userId  uint
+		wantErr error
+	}{
+		{"作成者は更新できる", 10, nil},
+		{"他のユーザーは更新できない", 20, ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cu, cr := newCourseUsecaseForTest()
+			course := &model.Course{Name: "線形代数II", PlanID: 2, PlanTermID: &otherTerm}
+			_, err := cu.UpdateCourse(tt.userId, course, 5)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(cr.updated) > 0 {
+					t.Fatalf("course was updated: %v", cr.updated)
+				}
+				return
+			}
+			got := cr.updated[0]
+			if got.PlanID != 1 || got.PlanTermID == nil || *got.PlanTermID != 100 {
+				t.Fatalf("plan/term changed: plan_id=%d plan_term_id=%v", got.PlanID, got.PlanTermID)
+			}
+		})
+	}
+}
+
+func TestDeleteCourseByIDRequiresOwner(t *testing.T) {
+	tests := []struct {
+		name     string
+		userId   uint
+		courseId uint
+		wantErr  error
+	}{
+		{"作成者は削除できる", 10, 5, nil},
+		{"他のユーザーは削除できない", 20, 5, ErrForbidden},
+		{"存在しない科目", 10, 6, gorm.ErrRecordNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cu, cr := newCourseUsecaseForTest()
+			err := cu.DeleteCourseByID(tt.userId, tt.courseId)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && len(cr.deleted) > 0 {
+				t.Fatalf("course was deleted: %v", cr.deleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"backend/mail"
+	"backend/model"
+	"backend/repository"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IDigestUsecase interface {
+	GetSetting(userId uint) (model.DigestSettingResponse, error)
+	UpdateSetting(userId uint, req model.DigestSettingRequest) (model.DigestSettingResponse, error)
+	GetUnsubscribePage(token string) ([]byte, error)
+	Unsubscribe(token string) error
+	SendDueDigests() error
+}
+
+type digestUsecase struct {
+	dr     repository.IDigestRepository
+	mailer mail.Mailer
+}
+
+func NewDigestUsecase(dr repository.IDigestRepository, mailer mail.Mailer) IDigestUsecase {
+	return &digestUsecase{dr, mailer}
+}
+
+// 配信停止用のトークンが不正な場合に返す
+var ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+
+// 1通に載せる同じ学科の新しい計画の上限
+const digestNewPlanLimit = 10
+
+// テンプレートに渡す値
+type digestMail struct {
+	Name           string
+	Daily          bool
+	Activities     []digestActivity
+	NewPlans       []digestPlan
+	UnsubscribeURL string
+}
+
+type digestActivity struct {
+	model.DigestPlanActivity
+	URL string
+}
+
+type digestPlan struct {
+	Title string
+	URL   string
+}
+
+// 設定がなければ配信しない状態として返す
+func (du *digestUsecase) GetSetting(userId uint) (model.DigestSettingResponse, error) {
+	setting := model.DigestSetting{}
+	if err := du.dr.GetSetting(&setting, userId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.DigestSettingResponse{Frequency: model.DigestFrequencyOff, Locale: model.DigestLocaleJa}, nil
+		}
+		return model.DigestSettingResponse{}, err
+	}
+	return toDigestSettingResponse(setting), nil
+}
+
+func (du *digestUsecase) UpdateSetting(userId uint, req model.DigestSettingRequest) (model.DigestSettingResponse, error) {
+	setting := model.DigestSetting{UserID: userId, Frequency: req.Frequency, Locale: req.Locale}
+	if err := du.dr.SaveSetting(&setting); err != nil {
+		return model.DigestSettingResponse{}, err
+	}
+	return du.GetSetting(userId)
+}
+
+// メール内のリンクを開いたときの確認ページ。リンクを開いただけ(メールソフトやセキュリティ製品の
+// 先読みを含む)では配信を止めず、ページのボタンからPOSTしたときに止める
+func (du *digestUsecase) GetUnsubscribePage(token string) ([]byte, error) {
+	if _, err := parseUnsubscribeToken(token); err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err := unsubscribeHTML.Execute(buf, token); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// メール内のリンクからログインせずに配信を止める
+func (du *digestUsecase) Unsubscribe(token string) error {
+	userId, err := parseUnsubscribeToken(token)
+	if err != nil {
+		return err
+	}
+	return du.dr.DisableDigest(userId)
+}
+
+// 毎日0時・毎週月曜0時(日本時間)以降にまだ送っていないユーザーに送る。
+// 1人の送信に失敗しても他のユーザーには送り、失敗したユーザーには次回の実行で再送する
+func (du *digestUsecase) SendDueDigests() error {
+	now := time.Now().In(jst)
+	schedules := []struct {
+		frequency  string
+		sentBefore time.Time
+		period     time.Duration
+	}{
+		{model.DigestFrequencyDaily, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, jst), 24 * time.Hour},
+		{model.DigestFrequencyWeekly, weekStart(now), 7 * 24 * time.Hour},
+	}
+	for _, schedule := range schedules {
+		settings := []model.DigestSetting{}
+		if err := du.dr.GetDueSettings(&settings, schedule.frequency, schedule.sentBefore); err != nil {
+			return err
+		}
+		for _, setting := range settings {
+			claimed, err := du.dr.ClaimDue(setting.ID, schedule.sentBefore, now)
+			if err != nil {
+				return err
+			}
+			if !claimed {
+				continue
+			}
+			since := now.Add(-schedule.period)
+			if setting.LastSentAt != nil {
+				since = *setting.LastSentAt
+			}
+			if err := du.sendDigest(setting, since); err != nil {
+				log.Printf("failed to send digest to user %d: %v", setting.UserID, err)
+				if err := du.dr.RestoreLastSent(setting.ID, setting.LastSentAt); err != nil {
+					log.Printf("failed to restore digest setting %d: %v", setting.ID, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// 報告することがなければ送らない
+func (du *digestUsecase) sendDigest(setting model.DigestSetting, since time.Time) error {
+	activities := []model.DigestPlanActivity{}
+	if err := du.dr.GetPlanActivities(&activities, setting.UserID, since); err != nil {
+		return err
+	}
+	plans := []model.Plan{}
+	if setting.User.DepartmentID != nil {
+		if err := du.dr.GetDepartmentPlans(&plans, *setting.User.DepartmentID, setting.UserID, since, digestNewPlanLimit); err != nil {
+			return err
+		}
+	}
+
+	if len(activities) == 0 && len(plans) == 0 {
+		return nil
+	}
+	msg, err := newDigestMessage(setting, activities, plans)
+	if err != nil {
+		return err
+	}
+	return du.mailer.Send(msg)
+}
+
+func newDigestMessage(setting model.DigestSetting, activities []model.DigestPlanActivity, plans []model.Plan) (mail.Message, error) {
+	unsubscribeURL := strings.TrimRight(os.Getenv("API_URL"), "/") + "/digest/unsubscribe?token=" + url.QueryEscape(unsubscribeToken(setting.UserID))
+	data := digestMail{
+		Name:           setting.User.Name,
+		Daily:          setting.Frequency == model.DigestFrequencyDaily,
+		Activities:     make([]digestActivity, 0, len(activities)),
+		NewPlans:       make([]digestPlan, 0, len(plans)),
+		UnsubscribeURL: unsubscribeURL,
+	}
+	if data.Name == "" {
+		data.Name = setting.User.Email
+	}
+	for _, activity := range activities {
+		data.Activities = append(data.Activities, digestActivity{DigestPlanActivity: activity, URL: planURL(activity.PlanID)})
+	}
+	for _, plan := range plans {
+		data.NewPlans = append(data.NewPlans, digestPlan{Title: plan.Title, URL: planURL(plan.ID)})
+	}
+
+	subject, body, err := mail.Render("digest", setting.Locale, data)
+	if err != nil {
+		return mail.Message{}, err
+	}
+	return mail.Message{
+		To:      setting.User.Email,
+		Subject: subject,
+		Body:    body,
+		Headers: map[string]string{
+			// 対応しているメールソフトではワンクリックで配信を停止できる(RFC 8058)
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+func planURL(planId uint) string {
+	return fmt.Sprintf("%s/plans/%d", strings.TrimRight(os.Getenv("FE_URL"), "/"), planId)
+}
+
+// "ユーザーID.署名" の形式。DBに保存せず、SECRETで検証する
+func unsubscribeToken(userId uint) string {
+	id := strconv.FormatUint(uint64(userId), 10)
+	return id + "." + base64.RawURLEncoding.EncodeToString(unsubscribeSignature(id))
+}
+
+func parseUnsubscribeToken(token string) (uint, error) {
+	id, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, ErrInvalidUnsubscribeToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, unsubscribeSignature(id)) {
+		return 0, ErrInvalidUnsubscribeToken
+	}
+	userId, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidUnsubscribeToken
+	}
+	return uint(userId), nil
+}
+
+// JWTと同じ鍵を使うため、用途を含めて署名する
+func unsubscribeSignature(id string) []byte {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("SECRET")))
+	mac.Write([]byte("digest-unsubscribe:" + id))
+	return mac.Sum(nil)
+}
+
+func toDigestSettingResponse(setting model.DigestSetting) model.DigestSettingResponse {
+	return model.DigestSettingResponse{
+		Frequency:  setting.Frequency,
+		Locale:     setting.Locale,
+		LastSentAt: setting.LastSentAt,
+	}
+}
+
+var unsubscribeHTML = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>ダイジェストメールの配信停止</title>
+<style>
+body { font-family: "Hiragino Sans", "Noto Sans JP", "Yu Gothic", sans-serif; margin: 32px 16px; color: #222; text-align: center; }
+button { font-size: 15px; padding: 8px 24px; }
+</style>
+</head>
+<body>
+<p>ダイジェストメールの配信を停止しますか?<br>Unsubscribe from digest emails?</p>
+<form method="post" action="?token={{.}}">
+<button type="submit">配信を停止する / Unsubscribe</button>
+</form>
+</body>
+</html>
+`))
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestParseUnsubscribeToken(t *testing.T) {
+	t.Setenv("SECRET", "secret")
+	valid := unsubscribeToken(42)
+	otherUser := unsubscribeToken(43)
+	signed := func(id string) string {
+		return id + "." + base64.RawURLEncoding.EncodeToString(unsubscribeSignature(id))
+	}
+	t.Setenv("SECRET", "old-secret")
+	otherSecret := unsubscribeToken(42)
+	t.Setenv("SECRET", "secret")
+
+	tests := []struct {
+		name    string
+		token   string
+		want    uint
+		wantErr error
+	}{
+		{"正しいトークン", valid, 42, nil},
+		{"ユーザーIDを書き換えたトークン", "43" + valid[len("42"):], 0, ErrInvalidUnsubscribeToken},
+		{"他のユーザーの署名", "42" + otherUser[len("43"):], 0, ErrInvalidUnsubscribeToken},
+		{"別のSECRETで署名したトークン", otherSecret, 0, ErrInvalidUnsubscribeToken},
+		{"署名がない", "42", 0, ErrInvalidUnsubscribeToken},
+		{"署名がbase64でない", "42.!!!", 0, ErrInvalidUnsubscribeToken},
+		{"空", "", 0, ErrInvalidUnsubscribeToken},
+		{"署名は正しいがIDが数値でない", signed("abc"), 0, ErrInvalidUnsubscribeToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseUnsubscribeToken(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("userId = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
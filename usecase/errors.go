@@ -0,0 +1,12 @@
+package usecase
+
+import "errors"
+
+// 操作対象が自分のものでない場合に返す
+var ErrForbidden = errors.New("forbidden")
+
+// 一意であるべきものが既に登録されている場合に返す
+var ErrAlreadyExists = errors.New("already exists")
+
+// 審査で掲載できないと判断された場合に返す
+var ErrContentRejected = errors.New("content rejected")
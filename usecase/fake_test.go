@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// テスト用の計画リポジトリ。テストで使うメソッドだけを実装し、それ以外を呼ぶとpanicする
+type fakePlanRepository struct {
+	repository.IPlanRepository
+	plans   map[uint]model.Plan
+	updated []uint
+	deleted []uint
+}
+
+func (r *fakePlanRepository) GetPlanByID(plan *model.Plan, planId uint) error {
+	stored, ok := r.plans[planId]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	*plan = stored
+	return nil
+}
+
+func (r *fakePlanRepository) UpdatePlan(plan *model.Plan, planId uint) error {
+	r.updated = append(r.updated, planId)
+	stored := r.plans[planId]
+	stored.Title = plan.Title
+	stored.Content = plan.Content
+	r.plans[planId] = stored
+	*plan = stored
+	return nil
+}
+
+func (r *fakePlanRepository) DeletePlanByID(planId uint) error {
+	r.deleted = append(r.deleted, planId)
+	delete(r.plans, planId)
+	return nil
+}
+
+// 配信されたイベント・Webhookを記録する
+type fakePublisher struct {
+	events []publishedEvent
+}
+
+type publishedEvent struct {
+	planId    uint
+	eventType string
+	data      interface{}
+}
+
+func (p *fakePublisher) Publish(planId uint, eventType string, data interface{}) {
+	p.events = append(p.events, publishedEvent{planId, eventType, data})
+}
+
+type fakePostRepository struct {
+	repository.IPostRepository
+	created []model.Post
+}
+
+func (r *fakePostRepository) CreatePost(post *model.Post) error {
+	post.ID = uint(len(r.created) + 1)
+	r.created = append(r.created, *post)
+	return nil
+}
+
+// 作成された通知を記録する
+type fakeNotifier struct {
+	notifications []model.Notification
+}
+
+func (n *fakeNotifier) Notify(notifications ...model.Notification) {
+	n.notifications = append(n.notifications, notifications...)
+}
+
+type fakeCourseRepository struct {
+	repository.ICourseRepository
+	courses map[uint]model.Course
+	created []model.Course
+	updated []model.Course
+	deleted []uint
+}
+
+func (r *fakeCourseRepository) GetCourseByID(course *model.Course, courseId uint) error {
+	stored, ok := r.courses[courseId]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	*course = stored
+	return nil
+}
+
+func (r *fakeCourseRepository) CreateCourses(courses *[]model.Course) error {
+	r.created = append(r.created, *courses...)
+	return nil
+}
+
+func (r *fakeCourseRepository) UpdateCourse(course *model.Course, courseId int) error {
+	r.updated = append(r.updated, *course)
+	return nil
+}
+
+func (r *fakeCourseRepository) DeleteCourseByID(courseId uint) error {
+	r.deleted = append(r.deleted, courseId)
+	return nil
+}
+
+type fakeTermRepository struct {
+	repository.ITermRepository
+	planTerms map[uint]model.PlanTerm
+}
+
+func (r *fakeTermRepository) GetPlanTermByID(term *model.PlanTerm, planTermId uint) error {
+	stored, ok := r.planTerms[planTermId]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	*term = stored
+	return nil
+}
+
+// 送信待ちの送信を保持し、保存された結果を記録する
+type fakeWebhookRepository struct {
+	repository.IWebhookRepository
+	due   []model.WebhookDelivery
+	saved []model.WebhookDelivery
+}
+
+func (r *fakeWebhookRepository) GetDueDeliveries(deliveries *[]model.WebhookDelivery, now time.Time, limit int) error {
+	for _, delivery := range r.due {
+		if delivery.Status == model.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			*deliveries = append(*deliveries, delivery)
+		}
+	}
+	return nil
+}
+
+func (r *fakeWebhookRepository) ClaimDelivery(delivery model.WebhookDelivery, leaseUntil time.Time) (bool, error) {
+	return true, nil
+}
+
+func (r *fakeWebhookRepository) SaveDeliveryResult(delivery *model.WebhookDelivery) error {
+	r.saved = append(r.saved, *delivery)
+	for i := range r.due {
+		if r.due[i].ID == delivery.ID {
+			r.due[i] = *delivery
+		}
+	}
+	return nil
+}
+
+// ハンドルの保存時に返すエラーを指定できる
+type fakeUserRepository struct {
+	repository.IUserRepository
+	users     map[uint]model.User
+	taken     bool  // ExistsHandleの結果
+	updateErr error // UpdateHandleが返すエラー
+}
+
+func (r *fakeUserRepository) ExistsHandle(handle string, excludeUserId uint) (bool, error) {
+	return r.taken, nil
+}
+
+func (r *fakeUserRepository) UpdateHandle(userId uint, handle string) error {
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	user := r.users[userId]
+	user.Handle = &handle
+	r.users[userId] = user
+	return nil
+}
+
+func (r *fakeUserRepository) GetUserByID(user *model.User, userId uint) error {
+	stored, ok := r.users[userId]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	*user = stored
+	return nil
+}
+
+// 返信はParentIDで辿る。削除すると返信もまとめて消える
+type fakeCommentRepository struct {
+	repository.ICommentRepository
+	comments map[uint]model.Comment
+}
+
+func (r *fakeCommentRepository) GetCommentByID(comment *model.Comment, commentID uint) error {
+	stored, ok := r.comments[commentID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	*comment = stored
+	return nil
+}
+
+func (r *fakeCommentRepository) GetDescendants(comments *[]model.Comment, commentIDs []uint) error {
+	for _, id := range commentIDs {
+		for _, comment := range r.comments {
+			if comment.ParentID != nil && *comment.ParentID == id {
+				*comments = append(*comments, comment)
+				if err := r.GetDescendants(comments, []uint{comment.ID}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (r *fakeCommentRepository) DeleteComment(commentID uint, userID *uint) error {
+	comment, ok := r.comments[commentID]
+	if !ok || (userID != nil && (comment.UserID == nil || *comment.UserID != *userID)) {
+		return gorm.ErrRecordNotFound
+	}
+	descendants := []model.Comment{}
+	r.GetDescendants(&descendants, []uint{commentID})
+	for _, descendant := range descendants {
+		delete(r.comments, descendant.ID)
+	}
+	delete(r.comments, commentID)
+	return nil
+}
+
+type fakeModerationRepository struct {
+	repository.IModerationRepository
+	cr *fakeCommentRepository
+}
+
+func (r *fakeModerationRepository) ApplyAction(action *model.ModerationAction, status string) error {
+	if action.Action == model.ModerationActionDelete {
+		return r.cr.DeleteComment(*action.CommentID, nil)
+	}
+	return nil
+}
+
+func (r *fakeUserRepository) GetUsersByHandles(users *[]model.User, handles []string) error {
+	for _, user := range r.users {
+		if user.Handle != nil && slices.Contains(handles, *user.Handle) {
+			*users = append(*users, user)
+		}
+	}
+	return nil
+}
+
+func (r *fakeCommentRepository) CreateComment(comment *model.Comment) error {
+	comment.ID = uint(len(r.comments) + 100)
+	r.comments[comment.ID] = *comment
+	return nil
+}
+
+func (r *fakeCommentRepository) UpdateCommentContent(comment *model.Comment, content string, mentions []model.Mention) error {
+	comment.Content = content
+	comment.Mentions = mentions
+	r.comments[comment.ID] = *comment
+	return nil
+}
+
+// 指定したエラーを返す。nilの場合はすべて公開する
+type fakeModerator struct {
+	err error
+}
+
+func (m *fakeModerator) Moderate(target ModerationTarget) error {
+	return m.err
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"backend/validator"
+	"strings"
+)
+
+type IFavoriteUsecase interface {
+	SaveFavorite(userId uint, planId uint, note *string) (model.MyFavoritePlanResponse, error)
+	DeleteFavorite(userId uint, planId uint) error
+	GetCollections(userId uint) ([]model.FavoriteCollectionResponse, error)
+	CreateCollection(userId uint, collection *model.FavoriteCollection) (model.FavoriteCollectionResponse, error)
+	UpdateCollection(userId uint, collectionId uint, collection *model.FavoriteCollection) (model.FavoriteCollectionResponse, error)
+	DeleteCollectionByID(userId uint, collectionId uint) error
+	GetCollectionPlans(userId uint, collectionId uint, offset int, limit int) ([]model.MyFavoritePlanResponse, error)
+	AddPlanToCollection(userId uint, collectionId uint, planId uint) (model.MyFavoritePlanResponse, error)
+	RemovePlanFromCollection(userId uint, collectionId uint, planId uint) error
+}
+
+type favoriteUsecase struct {
+	fr repository.IFavoriteRepository
+	pr repository.IPlanRepository
+	fv validator.IFavoriteValidator
+	n  INotifier
+	ep IPlanEventPublisher
+	wp IWebhookPublisher
+}
+
+func NewFavoriteUsecase(
+	fr repository.IFavoriteRepository,
+	pr repository.IPlanRepository,
+	fv validator.IFavoriteValidator,
+	n INotifier,
+	ep IPlanEventPublisher,
+	wp IWebhookPublisher,
+) IFavoriteUsecase {
+	return &favoriteUsecase{fr: fr, pr: pr, fv: fv, n: n, ep: ep, wp: wp}
+}
+
+// 何度呼んでも結果は同じ。noteを指定した場合はメモも更新する
+func (fu *favoriteUsecase) SaveFavorite(userId uint, planId uint, note *string) (model.MyFavoritePlanResponse, error) {
+	if err := fu.fv.NoteValidate(note); err != nil {
+		return model.MyFavoritePlanResponse{}, err
+	}
+	var plan model.Plan
+	if err := getVisiblePlan(fu.pr, &plan, planId, userId); err != nil {
+		return model.MyFavoritePlanResponse{}, err
+	}
+	favorite := model.FavoritePlan{UserID: userId, PlanID: planId, Note: note}
+	created, err := fu.fr.SaveFavorite(&favorite)
+	if err != nil {
+		return model.MyFavoritePlanResponse{}, err
+	}
+	if created {
+		fu.n.Notify(favoriteNotification(plan, userId))
+		publishFavoriteChange(fu.pr, fu.ep, fu.wp, userId, planId, true)
+	}
+	return fu.toResponse(favorite, plan, userId)
+}
+
+// お気に入りでなくてもエラーにしない
+func (fu *favoriteUsecase) DeleteFavorite(userId uint, planId uint) error {
+	deleted, err := fu.fr.DeleteFavorite(userId, planId)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		publishFavoriteChange(fu.pr, fu.ep, fu.wp, userId, planId, false)
+	}
+	return nil
+}
+
+func (fu *favoriteUsecase) GetCollections(userId uint) ([]model.FavoriteCollectionResponse, error) {
+	var collections []model.FavoriteCollection
+	if err := fu.fr.GetCollectionsByUserID(&collections, userId); err != nil {
+		return nil, err
+	}
+	collectionIds := make([]uint, 0, len(collections))
+	for _, collection := range collections {
+		collectionIds = append(collectionIds, collection.ID)
+	}
+	counts, err := fu.fr.GetCollectionPlanCounts(collectionIds)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]model.FavoriteCollectionResponse, 0, len(collections))
+	for _, collection := range collections {
+		res = append(res, toFavoriteCollectionResponse(collection, counts[collection.ID]))
+	}
+	return res, nil
+}
+
+func (fu *favoriteUsecase) CreateCollection(userId uint, collection *model.FavoriteCollection) (model.FavoriteCollectionResponse, error) {
+	collection.ID = 0
+	collection.UserID = userId
+	collection.Name = strings.TrimSpace(collection.Name)
+	if err := fu.checkCollection(*collection); err != nil {
+		return model.FavoriteCollectionResponse{}, err
+	}
+	if err := fu.fr.CreateCollection(collection); err != nil {
+		return model.FavoriteCollectionResponse{}, err
+	}
+	return toFavoriteCollectionResponse(*collection, 0), nil
+}
+
+func (fu *favoriteUsecase) UpdateCollection(userId uint, collectionId uint, collection *model.FavoriteCollection) (model.FavoriteCollectionResponse, error) {
+	var current model.FavoriteCollection
+	if err := fu.getOwnCollection(&current, userId, collectionId); err != nil {
+		return model.FavoriteCollectionResponse{}, err
+	}
+	current.Name = strings.TrimSpace(collection.Name)
+	if err := fu.checkCollection(current); err != nil {
+		return model.FavoriteCollectionResponse{}, err
+	}
+	if err := fu.fr.UpdateCollection(&current); err != nil {
+		return model.FavoriteCollectionResponse{}, err
+	}
+	counts, err := fu.fr.GetCollectionPlanCounts([]uint{current.ID})
+	if err != nil {
+		return model.FavoriteCollectionResponse{}, err
+	}
+	return toFavoriteCollectionResponse(current, counts[current.ID]), nil
+}
+
+func (fu *favoriteUsecase) DeleteCollectionByID(userId uint, collectionId uint) error {
+	var collection model.FavoriteCollection
+	if err := fu.getOwnCollection(&collection, userId, collectionId); err != nil {
+		return err
+	}
+	return fu.fr.DeleteCollectionByID(collectionId)
+}
+
+func (fu *favoriteUsecase) GetCollectionPlans(userId uint, collectionId uint, offset int, limit int) ([]model.MyFavoritePlanResponse, error) {
+	var collection model.FavoriteCollection
+	if err := fu.getOwnCollection(&collection, userId, collectionId); err != nil {
+		return nil, err
+	}
+	var favorites []model.FavoritePlan
+	if err := fu.fr.GetCollectionPlans(&favorites, collectionId, userId, offset, limit); err != nil {
+		return nil, err
+	}
+	return toMyFavoritePlanResponses(fu.pr, favorites, userId)
+}
+
+// 既にコレクションに入っている場合は何もしない
+func (fu *favoriteUsecase) AddPlanToCollection(userId uint, collectionId uint, planId uint) (model.MyFavoritePlanResponse, error) {
+	var collection model.FavoriteCollection
+	if err := fu.getOwnCollection(&collection, userId, collectionId); err != nil {
+		return model.MyFavoritePlanResponse{}, err
+	}
+	var plan model.Plan
+	if err := getVisiblePlan(fu.pr, &plan, planId, userId); err != nil {
+		return model.MyFavoritePlanResponse{}, err
+	}
+	favorite := model.FavoritePlan{UserID: userId, PlanID: planId}
+	created, err := fu.fr.AddToCollection(collectionId, &favorite)
+	if err != nil {
+		return model.MyFavoritePlanResponse{}, err
+	}
+	if created {
+		fu.n.Notify(favoriteNotification(plan, userId))
+		publishFavoriteChange(fu.pr, fu.ep, fu.wp, userId, planId, true)
+	}
+	return fu.toResponse(favorite, plan, userId)
+}
+
+// コレクションから外してもお気に入りは残す
+func (fu *favoriteUsecase) RemovePlanFromCollection(userId uint, collectionId uint, planId uint) error {
+	var collection model.FavoriteCollection
+	if err := fu.getOwnCollection(&collection, userId, collectionId); err != nil {
+		return err
+	}
+	return fu.fr.RemoveFromCollection(collectionId, userId, planId)
+}
+
+func (fu *favoriteUsecase) getOwnCollection(collection *model.FavoriteCollection, userId uint, collectionId uint) error {
+	if err := fu.fr.GetCollectionByID(collection, collectionId); err != nil {
+		return err
+	}
+	if collection.UserID != userId {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (fu *favoriteUsecase) checkCollection(collection model.FavoriteCollection) error {
+	if err := fu.fv.CollectionValidate(collection); err != nil {
+		return err
+	}
+	exists, err := fu.fr.ExistsCollectionName(collection.UserID, collection.Name, collection.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrAlreadyExists
+	}
+	return nil
+}
+
+func (fu *favoriteUsecase) toResponse(favorite model.FavoritePlan, plan model.Plan, userId uint) (model.MyFavoritePlanResponse, error) {
+	favorite.Plan = plan
+	res, err := toMyFavoritePlanResponses(fu.pr, []model.FavoritePlan{favorite}, userId)
+	if err != nil {
+		return model.MyFavoritePlanResponse{}, err
+	}
+	return res[0], nil
+}
+
+func toFavoriteCollectionResponse(collection model.FavoriteCollection, planCount int64) model.FavoriteCollectionResponse {
+	return model.FavoriteCollectionResponse{
+		ID:        collection.ID,
+		Name:      collection.Name,
+		PlanCount: planCount,
+		CreatedAt: collection.CreatedAt,
+		UpdatedAt: collection.UpdatedAt,
+	}
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RFC 5545 形式のカレンダーを組み立てる
+type icsWriter struct {
+	b strings.Builder
+}
+
+// 1行は75オクテット以内に折り返し、改行はCRLFとする。
+// 続きの行は先頭の空白を含めて75オクテットなので、本文は74オクテットまで
+func (w *icsWriter) line(name string, value string) {
+	content := name + ":" + value
+	limit := 75
+	for len(content) > limit {
+		cut := limit
+		// マルチバイト文字の途中で切らない
+		for cut > 0 && !isUTF8Start(content[cut]) {
+			cut--
+		}
+		w.b.WriteString(content[:cut])
+		w.b.WriteString("\r\n ")
+		content = content[cut:]
+		limit = 74
+	}
+	w.b.WriteString(content)
+	w.b.WriteString("\r\n")
+}
+
+func (w *icsWriter) text(name string, value string) {
+	w.line(name, escapeICSText(value))
+}
+
+func (w *icsWriter) bytes() []byte {
+	return []byte(w.b.String())
+}
+
+func isUTF8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+func escapeICSText(v string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(v)
+}
+
+func icsLocalTime(t time.Time) string {
+	return t.Format("20060102T150405")
+}
+
+func icsUTCTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// 日本は夏時間がないため固定のオフセットで表す
+func writeJSTTimezone(w *icsWriter) {
+	w.line("BEGIN", "VTIMEZONE")
+	w.line("TZID", icsTimezone)
+	w.line("BEGIN", "STANDARD")
+	w.line("DTSTART", "19700101T000000")
+	w.line("TZOFFSETFROM", "+0900")
+	w.line("TZOFFSETTO", "+0900")
+	w.line("TZNAME", "JST")
+	w.line("END", "STANDARD")
+	w.line("END", "VTIMEZONE")
+}
+
+const icsTimezone = "Asia/Tokyo"
+
+var jst = time.FixedZone("JST", 9*60*60)
+
+// "09:00" をその日の日時にする
+func atClock(day time.Time, clock string) (time.Time, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(clock, "%d:%d", &hour, &minute); err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", clock)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, jst), nil
+}
+
+// start以降で最初に指定の曜日になる日
+func firstWeekday(start time.Time, weekday time.Weekday) time.Time {
+	offset := (int(weekday) - int(start.Weekday()) + 7) % 7
+	return start.AddDate(0, 0, offset)
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestICSWriterLineFolding(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		wantLines int
+	}{
+		{"短い行は折り返さない", "線形代数", 1},
+		{"75オクテットちょうどは折り返さない", strings.Repeat("a", 75-len("SUMMARY:")), 1},
+		{"76オクテットは折り返す", strings.Repeat("a", 76-len("SUMMARY:")), 2},
+		{"続きの行は空白を含めて75オクテット", strings.Repeat("a", 75-len("SUMMARY:")+74), 2},
+		{"続きの行が1オクテット超える", strings.Repeat("a", 75-len("SUMMARY:")+75), 3},
+		{"マルチバイト文字の途中で切らない", strings.Repeat("講義", 40), 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w icsWriter
+			w.line("SUMMARY", tt.value)
+			out := string(w.bytes())
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("line does not end with CRLF: %q", out)
+			}
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			if len(lines) != tt.wantLines {
+				t.Fatalf("lines = %d, want %d: %q", len(lines), tt.wantLines, lines)
+			}
+			for i, l := range lines {
+				if len(l) > 75 {
+					t.Errorf("line %d is %d octets", i, len(l))
+				}
+				if i > 0 && !strings.HasPrefix(l, " ") {
+					t.Errorf("continuation line %d does not start with a space: %q", i, l)
+				}
+				if !utf8.ValidString(l) {
+					t.Errorf("line %d splits a character: %q", i, l)
+				}
+			}
+			// 折り返しを戻すと元の内容になる
+			if got := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""); got != "SUMMARY:"+tt.value {
+				t.Fatalf("unfolded = %q", got)
+			}
+		})
+	}
+}
+
+func TestEscapeICSText(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"そのまま", "線形代数 I", "線形代数 I"},
+		{"バックスラッシュ", `a\b`, `a\\b`},
+		{"セミコロンとカンマ", "月;火,水", `月\;火\,水`},
+		{"改行", "1行目\n2行目", `1行目\n2行目`},
+		{"CRLF", "1行目\r\n2行目", `1行目\n2行目`},
+		{"エスケープ済みに見える文字列", `\n`, `\\n`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeICSText(tt.value); got != tt.want {
+				t.Fatalf("escapeICSText(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"sort"
+	"unicode"
+)
+
+// ハンドルとして使える最大の文字数。validatorのHandleValidateと揃える
+const maxHandleLength = 20
+
+// 本文中の@から始まる言及の候補
+type mentionCandidate struct {
+	start int    // @の位置(rune)
+	name  []rune // @の後に続くハンドルに使える文字の並び
+}
+
+func isHandleRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_'
+}
+
+// @または全角の＠の直後に続く文字列を候補として取り出す。
+// メールアドレスのように直前がハンドルに使える文字の場合は言及とみなさない
+func parseMentionCandidates(content string) []mentionCandidate {
+	runes := []rune(content)
+	candidates := []mentionCandidate{}
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '@' && runes[i] != '＠' {
+			continue
+		}
+		if i > 0 && isHandleRune(runes[i-1]) {
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && isHandleRune(runes[end]) {
+			end++
+		}
+		if end-(i+1) >= 2 {
+			candidates = append(candidates, mentionCandidate{start: i, name: runes[i+1 : end]})
+		}
+		i = end - 1
+	}
+	return candidates
+}
+
+// 日本語の本文では「@山田さん」のようにハンドルの後に空白を置かないことが多いため、
+// 候補の先頭から取った文字列のうち、存在するハンドルに一致する最も長いものを言及とみなす
+func resolveMentions(ur repository.IUserRepository, content string, authorID *uint) ([]model.Mention, error) {
+	candidates := parseMentionCandidates(content)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	handles := []string{}
+	seen := map[string]bool{}
+	for _, candidate := range candidates {
+		for n := 2; n <= len(candidate.name) && n <= maxHandleLength; n++ {
+			handle := normalizeText(string(candidate.name[:n]))
+			if !seen[handle] {
+				seen[handle] = true
+				handles = append(handles, handle)
+			}
+		}
+	}
+	users := []model.User{}
+	if err := ur.GetUsersByHandles(&users, handles); err != nil {
+		return nil, err
+	}
+	userIDs := map[string]uint{}
+	for _, user := range users {
+		userIDs[*user.Handle] = user.ID
+	}
+
+	mentions := []model.Mention{}
+	byUser := map[uint]int{}
+	for _, candidate := range candidates {
+		for n := min(len(candidate.name), maxHandleLength); n >= 2; n-- {
+			handle := normalizeText(string(candidate.name[:n]))
+			userID, ok := userIDs[handle]
+			if !ok {
+				continue
+			}
+			r := model.MentionRange{Start: candidate.start, End: candidate.start + 1 + n}
+			if i, ok := byUser[userID]; ok {
+				mentions[i].Ranges = append(mentions[i].Ranges, r)
+			} else {
+				byUser[userID] = len(mentions)
+				mentions = append(mentions, model.Mention{
+					UserID:   userID,
+					Handle:   handle,
+					AuthorID: authorID,
+					Ranges:   []model.MentionRange{r},
+				})
+			}
+			break
+		}
+	}
+	return mentions, nil
+}
+
+// 本文中の位置の順に並べて返す
+func toMentionSpans(mentions []model.Mention) []model.MentionSpan {
+	spans := []model.MentionSpan{}
+	for _, mention := range mentions {
+		for _, r := range mention.Ranges {
+			spans = append(spans, model.MentionSpan{
+				UserID: mention.UserID,
+				Handle: mention.Handle,
+				Start:  r.Start,
+				End:    r.End,
+			})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+	return spans
+}
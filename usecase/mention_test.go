@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"backend/model"
+	"reflect"
+	"testing"
+)
+
+func TestResolveMentions(t *testing.T) {
+	handle := func(h string) *string { return &h }
+	ur := &fakeUserRepository{users: map[uint]model.User{
+		1: {ID: 1, Handle: handle("taro")},
+		2: {ID: 2, Handle: handle("taro_2")},
+		3: {ID: 3, Handle: handle("山田")},
+	}}
+	type span struct {
+		userID     uint
+		start, end int
+	}
+	tests := []struct {
+		name    string
+		content string
+		want    []span
+	}{
+		{"ハンドルの後に続く日本語は含めない", "@山田さん、よろしく", []span{{3, 0, 3}}},
+		{"最も長く一致するハンドルを選ぶ", "@taro_2です", []span{{2, 0, 7}}},
+		{"長いハンドルがなければ短いハンドル", "@taro_3です", []span{{1, 0, 5}}},
+		{"全角の＠と全角英字", "こんにちは ＠ＴＡＲＯさん", []span{{1, 6, 11}}},
+		{"メールアドレスは言及ではない", "mail@taro.example", nil},
+		{"存在しないハンドル", "@hanako", nil},
+		{"1文字は候補にしない", "@t", nil},
+		{"同じユーザーへの複数の言及", "@taro と @taro", []span{{1, 0, 5}, {1, 8, 13}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mentions, err := resolveMentions(ur, tt.content, nil)
+			if err != nil {
+				t.Fatalf("resolveMentions: %v", err)
+			}
+			var got []span
+			for _, s := range toMentionSpans(mentions) {
+				got = append(got, span{s.UserID, s.Start, s.End})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("spans = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+)
+
+type IMentionUsecase interface {
+	GetMyMentions(userId uint, offset int, limit int) ([]model.MentionResponse, error)
+}
+
+type mentionUsecase struct {
+	mr repository.IMentionRepository
+}
+
+func NewMentionUsecase(mr repository.IMentionRepository) IMentionUsecase {
+	return &mentionUsecase{mr}
+}
+
+// 自分が言及されたコメントと投稿を新しい順に返す
+func (mu *mentionUsecase) GetMyMentions(userId uint, offset int, limit int) ([]model.MentionResponse, error) {
+	mentions := []model.Mention{}
+	if err := mu.mr.GetMentionsByUserID(&mentions, userId, offset, limit); err != nil {
+		return nil, err
+	}
+
+	commentIds := []uint{}
+	postIds := []uint{}
+	for _, mention := range mentions {
+		if mention.CommentID != nil {
+			commentIds = append(commentIds, *mention.CommentID)
+		}
+		if mention.PostID != nil {
+			postIds = append(postIds, *mention.PostID)
+		}
+	}
+	comments := []model.Comment{}
+	if err := mu.mr.GetCommentsByIDs(&comments, commentIds); err != nil {
+		return nil, err
+	}
+	posts := []model.Post{}
+	if err := mu.mr.GetPostsByIDs(&posts, postIds); err != nil {
+		return nil, err
+	}
+	commentsByID := make(map[uint]model.Comment, len(comments))
+	for _, comment := range comments {
+		commentsByID[comment.ID] = comment
+	}
+	postsByID := make(map[uint]model.Post, len(posts))
+	for _, post := range posts {
+		postsByID[post.ID] = post
+	}
+
+	responses := make([]model.MentionResponse, 0, len(mentions))
+	for _, mention := range mentions {
+		res := model.MentionResponse{
+			ID:        mention.ID,
+			AuthorID:  mention.AuthorID,
+			CreatedAt: mention.CreatedAt,
+		}
+		if mention.CommentID != nil {
+			if comment, ok := commentsByID[*mention.CommentID]; ok {
+				commentRes := toCommentResponse(comment, userId)
+				res.Comment = &commentRes
+			}
+		}
+		if mention.PostID != nil {
+			if post, ok := postsByID[*mention.PostID]; ok {
+				postRes := toPostResponse(post, userId)
+				res.Post = &postRes
+			}
+		}
+		responses = append(responses, res)
+	}
+	return responses, nil
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 審査対象の種別
+const (
+	ModerationKindComment = "comment"
+	ModerationKindReview  = "review"
+	ModerationKindAnswer  = "answer"
+)
+
+type ModerationTarget struct {
+	Kind   string
+	UserID *uint // 未ログインのコメントはnil
+	Text   string
+}
+
+// コメント・レビューを保存する前に内容を審査する差し込み口。
+// 掲載できない場合は ErrContentRejected を包んだエラーを返す。
+// 人の確認が必要な場合は ErrContentHeld を返し、保留できない投稿では掲載を拒否したものとして扱う
+type IModerator interface {
+	Moderate(target ModerationTarget) error
+}
+
+// 確認待ちとして保留すべき場合に返す
+var ErrContentHeld = fmt.Errorf("%w: held for review", ErrContentRejected)
+
+type nopModerator struct{}
+
+// 何も審査しない既定の実装
+func NewNopModerator() IModerator {
+	return nopModerator{}
+}
+
+func (nopModerator) Moderate(target ModerationTarget) error {
+	return nil
+}
+
+// NGワードの変更を反映できる審査
+type INGWordModerator interface {
+	IModerator
+	Reload()
+}
+
+// 他のインスタンスでの変更もこの間隔で反映する
+const ngWordCacheTTL = time.Minute
+
+type ngWordModerator struct {
+	mr       repository.IModerationRepository
+	mu       sync.RWMutex
+	words    []string
+	loadedAt time.Time
+}
+
+// NGワードを含む投稿を保留する。NGワードは全角・半角と大文字・小文字を区別しない
+func NewNGWordModerator(mr repository.IModerationRepository) INGWordModerator {
+	return &ngWordModerator{mr: mr}
+}
+
+func (m *ngWordModerator) Moderate(target ModerationTarget) error {
+	text := normalizeText(target.Text)
+	for _, word := range m.load() {
+		if strings.Contains(text, word) {
+			return ErrContentHeld
+		}
+	}
+	return nil
+}
+
+// 次の審査でNGワードを読み込み直す
+func (m *ngWordModerator) Reload() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loadedAt = time.Time{}
+}
+
+// 読み込みに失敗した場合は前回のNGワードで審査を続ける
+func (m *ngWordModerator) load() []string {
+	m.mu.RLock()
+	if time.Since(m.loadedAt) < ngWordCacheTTL {
+		defer m.mu.RUnlock()
+		return m.words
+	}
+	m.mu.RUnlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if time.Since(m.loadedAt) < ngWordCacheTTL {
+		return m.words
+	}
+	var ngWords []model.NGWord
+	if err := m.mr.GetNGWords(&ngWords); err != nil {
+		log.Printf("failed to load NG words: %v", err)
+		return m.words
+	}
+	words := make([]string, 0, len(ngWords))
+	for _, ngWord := range ngWords {
+		if word := normalizeText(ngWord.Word); word != "" {
+			words = append(words, word)
+		}
+	}
+	m.words = words
+	m.loadedAt = time.Now()
+	return m.words
+}
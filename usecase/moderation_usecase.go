@@ -0,0 +1,369 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"backend/validator"
+	"strings"
+)
+
+type IModerationUsecase interface {
+	ReportComment(commentId uint, userId *uint, req model.ReportRequest) (model.ReportResponse, error)
+	ReportReview(reviewId uint, userId uint, req model.ReportRequest) (model.ReportResponse, error)
+	GetQueue(offset int, limit int) ([]model.ModerationQueueItemResponse, error)
+	HideComment(moderatorId uint, commentId uint, reason *string) (model.ModerationActionResponse, error)
+	RestoreComment(moderatorId uint, commentId uint, reason *string) (model.ModerationActionResponse, error)
+	DeleteComment(moderatorId uint, commentId uint, reason *string) (model.ModerationActionResponse, error)
+	HideReview(moderatorId uint, reviewId uint, reason *string) (model.ModerationActionResponse, error)
+	RestoreReview(moderatorId uint, reviewId uint, reason *string) (model.ModerationActionResponse, error)
+	DeleteReview(moderatorId uint, reviewId uint, reason *string) (model.ModerationActionResponse, error)
+	GetActions(commentId *uint, reviewId *uint, offset int, limit int) ([]model.ModerationActionResponse, error)
+	GetNGWords() ([]model.NGWordResponse, error)
+	CreateNGWord(userId uint, word string) (model.NGWordResponse, error)
+	DeleteNGWordByID(ngWordId uint) error
+}
+
+type moderationUsecase struct {
+	mr  repository.IModerationRepository
+	cr  repository.ICommentRepository
+	rr  repository.IReviewRepository
+	mv  validator.IModerationValidator
+	ngm INGWordModerator
+	ep  IPlanEventPublisher
+	wp  IWebhookPublisher
+}
+
+func NewModerationUsecase(
+	mr repository.IModerationRepository,
+	cr repository.ICommentRepository,
+	rr repository.IReviewRepository,
+	mv validator.IModerationValidator,
+	ngm INGWordModerator,
+	ep IPlanEventPublisher,
+	wp IWebhookPublisher,
+) IModerationUsecase {
+	return &moderationUsecase{mr: mr, cr: cr, rr: rr, mv: mv, ngm: ngm, ep: ep, wp: wp}
+}
+
+// ログインユーザーは同じコメントを未対応のまま重ねて通報できない
+func (mu *moderationUsecase) ReportComment(commentId uint, userId *uint, req model.ReportRequest) (model.ReportResponse, error) {
+	if err := mu.mv.ReportValidate(req); err != nil {
+		return model.ReportResponse{}, err
+	}
+	report := model.CommentReport{
+		CommentID: commentId,
+		UserID:    userId,
+		Reason:    req.Reason,
+		Detail:    req.Detail,
+	}
+	var comment model.Comment
+	if err := mu.cr.GetCommentByID(&comment, commentId); err != nil {
+		return model.ReportResponse{}, err
+	}
+	if userId != nil {
+		exists, err := mu.mr.ExistsReport(commentId, *userId)
+		if err != nil {
+			return model.ReportResponse{}, err
+		}
+		if exists {
+			return model.ReportResponse{}, ErrAlreadyExists
+		}
+	}
+	if err := mu.mr.CreateReport(&report); err != nil {
+		return model.ReportResponse{}, err
+	}
+	return toCommentReportResponse(report), nil
+}
+
+// 同じレビューを未対応のまま重ねて通報できない
+func (mu *moderationUsecase) ReportReview(reviewId uint, userId uint, req model.ReportRequest) (model.ReportResponse, error) {
+	if err := mu.mv.ReportValidate(req); err != nil {
+		return model.ReportResponse{}, err
+	}
+	var review model.CourseReview
+	if err := mu.rr.GetReviewByID(&review, reviewId); err != nil {
+		return model.ReportResponse{}, err
+	}
+	exists, err := mu.mr.ExistsReviewReport(reviewId, userId)
+	if err != nil {
+		return model.ReportResponse{}, err
+	}
+	if exists {
+		return model.ReportResponse{}, ErrAlreadyExists
+	}
+	report := model.ReviewReport{
+		ReviewID: reviewId,
+		UserID:   userId,
+		Reason:   req.Reason,
+		Detail:   req.Detail,
+	}
+	if err := mu.mr.CreateReviewReport(&report); err != nil {
+		return model.ReportResponse{}, err
+	}
+	return toReviewReportResponse(report), nil
+}
+
+// コメントとレビューを通報の多い順にまとめて返す
+func (mu *moderationUsecase) GetQueue(offset int, limit int) ([]model.ModerationQueueItemResponse, error) {
+	var entries []model.ModerationQueueEntry
+	if err := mu.mr.GetQueue(&entries, offset, limit); err != nil {
+		return nil, err
+	}
+	commentIds := []uint{}
+	reviewIds := []uint{}
+	for _, entry := range entries {
+		switch entry.Kind {
+		case model.ModerationTargetComment:
+			commentIds = append(commentIds, entry.TargetID)
+		case model.ModerationTargetReview:
+			reviewIds = append(reviewIds, entry.TargetID)
+		}
+	}
+	var comments []model.Comment
+	if err := mu.mr.GetCommentsByIDs(&comments, commentIds); err != nil {
+		return nil, err
+	}
+	var commentReports []model.CommentReport
+	if err := mu.mr.GetOpenReports(&commentReports, commentIds); err != nil {
+		return nil, err
+	}
+	var reviews []model.CourseReview
+	if err := mu.mr.GetReviewsByIDs(&reviews, reviewIds); err != nil {
+		return nil, err
+	}
+	var reviewReports []model.ReviewReport
+	if err := mu.mr.GetOpenReviewReports(&reviewReports, reviewIds); err != nil {
+		return nil, err
+	}
+
+	commentsById := make(map[uint]model.Comment, len(comments))
+	for _, comment := range comments {
+		commentsById[comment.ID] = comment
+	}
+	reviewsById := make(map[uint]model.CourseReview, len(reviews))
+	for _, review := range reviews {
+		reviewsById[review.ID] = review
+	}
+	reportsByComment := map[uint][]model.ReportResponse{}
+	for _, report := range commentReports {
+		reportsByComment[report.CommentID] = append(reportsByComment[report.CommentID], toCommentReportResponse(report))
+	}
+	reportsByReview := map[uint][]model.ReportResponse{}
+	for _, report := range reviewReports {
+		reportsByReview[report.ReviewID] = append(reportsByReview[report.ReviewID], toReviewReportResponse(report))
+	}
+
+	res := make([]model.ModerationQueueItemResponse, 0, len(entries))
+	for _, entry := range entries {
+		item := model.ModerationQueueItemResponse{
+			Kind:           entry.Kind,
+			ReportCount:    entry.ReportCount,
+			ReasonCounts:   map[string]int{},
+			LastReportedAt: entry.LastReportedAt,
+		}
+		var reports []model.ReportResponse
+		switch entry.Kind {
+		case model.ModerationTargetComment:
+			comment, ok := commentsById[entry.TargetID]
+			if !ok {
+				continue
+			}
+			commentRes := toCommentResponse(comment, 0)
+			item.Comment = &commentRes
+			item.Content = comment.Content
+			reports = reportsByComment[comment.ID]
+		case model.ModerationTargetReview:
+			review, ok := reviewsById[entry.TargetID]
+			if !ok {
+				continue
+			}
+			reviewRes := toCourseReviewResponse(review)
+			item.Review = &reviewRes
+			item.Content = review.Content
+			reports = reportsByReview[review.ID]
+		default:
+			continue
+		}
+		item.Reports = make([]model.ReportResponse, 0, len(reports))
+		for _, report := range reports {
+			item.ReasonCounts[report.Reason]++
+			item.Reports = append(item.Reports, report)
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+func (mu *moderationUsecase) HideComment(moderatorId uint, commentId uint, reason *string) (model.ModerationActionResponse, error) {
+	return mu.apply(moderatorId, commentId, model.ModerationActionHide, model.CommentStatusHidden, reason)
+}
+
+func (mu *moderationUsecase) RestoreComment(moderatorId uint, commentId uint, reason *string) (model.ModerationActionResponse, error) {
+	return mu.apply(moderatorId, commentId, model.ModerationActionRestore, model.CommentStatusVisible, reason)
+}
+
+// 返信もまとめて削除される
+func (mu *moderationUsecase) DeleteComment(moderatorId uint, commentId uint, reason *string) (model.ModerationActionResponse, error) {
+	return mu.apply(moderatorId, commentId, model.ModerationActionDelete, "", reason)
+}
+
+func (mu *moderationUsecase) HideReview(moderatorId uint, reviewId uint, reason *string) (model.ModerationActionResponse, error) {
+	return mu.applyReview(moderatorId, reviewId, model.ModerationActionHide, model.ReviewStatusHidden, reason)
+}
+
+func (mu *moderationUsecase) RestoreReview(moderatorId uint, reviewId uint, reason *string) (model.ModerationActionResponse, error) {
+	return mu.applyReview(moderatorId, reviewId, model.ModerationActionRestore, model.ReviewStatusVisible, reason)
+}
+
+func (mu *moderationUsecase) DeleteReview(moderatorId uint, reviewId uint, reason *string) (model.ModerationActionResponse, error) {
+	return mu.applyReview(moderatorId, reviewId, model.ModerationActionDelete, "", reason)
+}
+
+func (mu *moderationUsecase) GetActions(commentId *uint, reviewId *uint, offset int, limit int) ([]model.ModerationActionResponse, error) {
+	var actions []model.ModerationAction
+	if err := mu.mr.GetActions(&actions, commentId, reviewId, offset, limit); err != nil {
+		return nil, err
+	}
+	res := make([]model.ModerationActionResponse, 0, len(actions))
+	for _, action := range actions {
+		res = append(res, toModerationActionResponse(action))
+	}
+	return res, nil
+}
+
+func (mu *moderationUsecase) GetNGWords() ([]model.NGWordResponse, error) {
+	var words []model.NGWord
+	if err := mu.mr.GetNGWords(&words); err != nil {
+		return nil, err
+	}
+	res := make([]model.NGWordResponse, 0, len(words))
+	for _, word := range words {
+		res = append(res, toNGWordResponse(word))
+	}
+	return res, nil
+}
+
+// 追加・削除したNGワードは次の投稿から反映される
+func (mu *moderationUsecase) CreateNGWord(userId uint, word string) (model.NGWordResponse, error) {
+	ngWord := model.NGWord{Word: strings.TrimSpace(word), CreatedBy: &userId}
+	if err := mu.mv.NGWordValidate(ngWord); err != nil {
+		return model.NGWordResponse{}, err
+	}
+	exists, err := mu.mr.ExistsNGWord(ngWord.Word)
+	if err != nil {
+		return model.NGWordResponse{}, err
+	}
+	if exists {
+		return model.NGWordResponse{}, ErrAlreadyExists
+	}
+	if err := mu.mr.CreateNGWord(&ngWord); err != nil {
+		return model.NGWordResponse{}, err
+	}
+	mu.ngm.Reload()
+	return toNGWordResponse(ngWord), nil
+}
+
+func (mu *moderationUsecase) DeleteNGWordByID(ngWordId uint) error {
+	if err := mu.mr.DeleteNGWordByID(ngWordId); err != nil {
+		return err
+	}
+	mu.ngm.Reload()
+	return nil
+}
+
+func (mu *moderationUsecase) apply(moderatorId uint, commentId uint, action string, status string, reason *string) (model.ModerationActionResponse, error) {
+	var comment model.Comment
+	if err := mu.cr.GetCommentByID(&comment, commentId); err != nil {
+		return model.ModerationActionResponse{}, err
+	}
+	moderationAction := model.ModerationAction{
+		CommentID:   &commentId,
+		ModeratorID: &moderatorId,
+		Action:      action,
+		Reason:      reason,
+		Content:     comment.Content,
+	}
+	var threadIDs []uint
+	if action == model.ModerationActionDelete {
+		ids, err := commentThreadIDs(mu.cr, commentId)
+		if err != nil {
+			return model.ModerationActionResponse{}, err
+		}
+		threadIDs = ids
+	}
+	if err := mu.mr.ApplyAction(&moderationAction, status); err != nil {
+		return model.ModerationActionResponse{}, err
+	}
+	if action == model.ModerationActionDelete {
+		publishCommentsDeleted(mu.ep, comment.PlanID, threadIDs)
+		return toModerationActionResponse(moderationAction), nil
+	}
+	previous := comment.Status
+	comment.Status = status
+	publishCommentChange(mu.ep, comment)
+	// 確認待ちだったコメントはWebhookでは公開された時点で作成として送る
+	if previous == model.CommentStatusPending && status == model.CommentStatusVisible {
+		mu.wp.Publish(comment.PlanID, model.WebhookEventCommentCreated, toCommentResponse(comment, 0))
+	}
+	return toModerationActionResponse(moderationAction), nil
+}
+
+func (mu *moderationUsecase) applyReview(moderatorId uint, reviewId uint, action string, status string, reason *string) (model.ModerationActionResponse, error) {
+	var review model.CourseReview
+	if err := mu.rr.GetReviewByID(&review, reviewId); err != nil {
+		return model.ModerationActionResponse{}, err
+	}
+	moderationAction := model.ModerationAction{
+		ReviewID:    &reviewId,
+		ModeratorID: &moderatorId,
+		Action:      action,
+		Reason:      reason,
+		Content:     review.Content,
+	}
+	if err := mu.mr.ApplyAction(&moderationAction, status); err != nil {
+		return model.ModerationActionResponse{}, err
+	}
+	return toModerationActionResponse(moderationAction), nil
+}
+
+func toCommentReportResponse(report model.CommentReport) model.ReportResponse {
+	return model.ReportResponse{
+		ID:        report.ID,
+		UserID:    report.UserID,
+		Reason:    report.Reason,
+		Detail:    report.Detail,
+		CreatedAt: report.CreatedAt,
+	}
+}
+
+func toReviewReportResponse(report model.ReviewReport) model.ReportResponse {
+	userId := report.UserID
+	return model.ReportResponse{
+		ID:        report.ID,
+		UserID:    &userId,
+		Reason:    report.Reason,
+		Detail:    report.Detail,
+		CreatedAt: report.CreatedAt,
+	}
+}
+
+func toModerationActionResponse(action model.ModerationAction) model.ModerationActionResponse {
+	return model.ModerationActionResponse{
+		ID:          action.ID,
+		CommentID:   action.CommentID,
+		ReviewID:    action.ReviewID,
+		ModeratorID: action.ModeratorID,
+		Action:      action.Action,
+		Reason:      action.Reason,
+		Content:     action.Content,
+		CreatedAt:   action.CreatedAt,
+	}
+}
+
+func toNGWordResponse(word model.NGWord) model.NGWordResponse {
+	return model.NGWordResponse{
+		ID:        word.ID,
+		Word:      word.Word,
+		CreatedAt: word.CreatedAt,
+	}
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"errors"
+)
+
+type INotificationUsecase interface {
+	GetNotifications(userId uint, unreadOnly bool, offset int, limit int) (model.NotificationsResponse, error)
+	MarkRead(userId uint, notificationId uint) error
+	MarkAllRead(userId uint) error
+	GetPreferences(userId uint) ([]model.NotificationPreference, error)
+	UpdatePreferences(userId uint, preferences []model.NotificationPreference) ([]model.NotificationPreference, error)
+}
+
+type notificationUsecase struct {
+	nr repository.INotificationRepository
+}
+
+func NewNotificationUsecase(nr repository.INotificationRepository) INotificationUsecase {
+	return &notificationUsecase{nr}
+}
+
+// 存在しない通知の種類を指定した場合に返す
+var ErrUnknownNotificationType = errors.New("unknown notification type")
+
+// 新しい順に返す。未読件数は絞り込みやページに関係なく全体の件数
+func (nu *notificationUsecase) GetNotifications(userId uint, unreadOnly bool, offset int, limit int) (model.NotificationsResponse, error) {
+	notifications := []model.Notification{}
+	if err := nu.nr.GetNotifications(&notifications, userId, unreadOnly, offset, limit); err != nil {
+		return model.NotificationsResponse{}, err
+	}
+	unread, err := nu.nr.CountUnread(userId)
+	if err != nil {
+		return model.NotificationsResponse{}, err
+	}
+	res := model.NotificationsResponse{
+		UnreadCount:   unread,
+		Notifications: make([]model.NotificationResponse, 0, len(notifications)),
+	}
+	for _, notification := range notifications {
+		res.Notifications = append(res.Notifications, toNotificationResponse(notification))
+	}
+	return res, nil
+}
+
+func (nu *notificationUsecase) MarkRead(userId uint, notificationId uint) error {
+	return nu.nr.MarkRead(userId, notificationId)
+}
+
+func (nu *notificationUsecase) MarkAllRead(userId uint) error {
+	return nu.nr.MarkAllRead(userId)
+}
+
+// すべての種類について受け取るかどうかを返す
+func (nu *notificationUsecase) GetPreferences(userId uint) ([]model.NotificationPreference, error) {
+	types, err := nu.nr.GetOptOutTypes(userId)
+	if err != nil {
+		return nil, err
+	}
+	optOuts := make(map[string]bool, len(types))
+	for _, t := range types {
+		optOuts[t] = true
+	}
+	preferences := make([]model.NotificationPreference, 0, len(model.NotificationTypes))
+	for _, t := range model.NotificationTypes {
+		preferences = append(preferences, model.NotificationPreference{Type: t, Enabled: !optOuts[t]})
+	}
+	return preferences, nil
+}
+
+// 指定した種類のみ更新し、更新後のすべての設定を返す
+func (nu *notificationUsecase) UpdatePreferences(userId uint, preferences []model.NotificationPreference) ([]model.NotificationPreference, error) {
+	enabled := make(map[string]bool, len(preferences))
+	for _, preference := range preferences {
+		if !isNotificationType(preference.Type) {
+			return nil, ErrUnknownNotificationType
+		}
+		enabled[preference.Type] = preference.Enabled
+	}
+	if err := nu.nr.UpdateOptOuts(userId, enabled); err != nil {
+		return nil, err
+	}
+	return nu.GetPreferences(userId)
+}
+
+func isNotificationType(t string) bool {
+	for _, known := range model.NotificationTypes {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
+func toNotificationResponse(notification model.Notification) model.NotificationResponse {
+	return model.NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		ActorID:   notification.ActorID,
+		PlanID:    notification.PlanID,
+		CommentID: notification.CommentID,
+		PostID:    notification.PostID,
+		AnswerID:  notification.AnswerID,
+		Read:      notification.ReadAt != nil,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+}
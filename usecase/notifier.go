@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"log"
+)
+
+// コメントや計画の操作から通知を作成する。
+// 通知の失敗で元の操作を失敗させないよう、エラーはログに出力するだけにする
+type INotifier interface {
+	Notify(notifications ...model.Notification)
+}
+
+type notifier struct {
+	nr repository.INotificationRepository
+}
+
+func NewNotifier(nr repository.INotificationRepository) INotifier {
+	return &notifier{nr}
+}
+
+// 自分の操作による通知と、受け取らない設定にしている種類の通知は作成しない。
+// 同じユーザーへの通知が複数ある場合は、受け取る設定の種類のうち最初のものだけを作成する
+func (n *notifier) Notify(notifications ...model.Notification) {
+	recipients := []uint{}
+	seen := map[uint]bool{}
+	pending := []model.Notification{}
+	for _, notification := range notifications {
+		if notification.UserID == 0 {
+			continue
+		}
+		if notification.ActorID != nil && *notification.ActorID == notification.UserID {
+			continue
+		}
+		if !seen[notification.UserID] {
+			seen[notification.UserID] = true
+			recipients = append(recipients, notification.UserID)
+		}
+		pending = append(pending, notification)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	optOuts, err := n.nr.GetOptOuts(recipients)
+	if err != nil {
+		log.Printf("notify: %v", err)
+		return
+	}
+	notified := map[uint]bool{}
+	enabled := make([]model.Notification, 0, len(recipients))
+	for _, notification := range pending {
+		if optOuts[notification.UserID][notification.Type] || notified[notification.UserID] {
+			continue
+		}
+		notified[notification.UserID] = true
+		enabled = append(enabled, notification)
+	}
+	if len(enabled) == 0 {
+		return
+	}
+	if err := n.nr.CreateNotifications(&enabled); err != nil {
+		log.Printf("notify: %v", err)
+	}
+}
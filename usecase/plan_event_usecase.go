@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"backend/event"
+	"backend/model"
+	"backend/repository"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// 計画のページに配信するイベントを作成する。
+// 配信の失敗で元の操作を失敗させないよう、エラーはログに出力するだけにする
+type IPlanEventPublisher interface {
+	Publish(planId uint, eventType string, data interface{})
+}
+
+type IPlanEventUsecase interface {
+	IPlanEventPublisher
+	Subscribe(userId uint, planId uint, lastEventId uint) (*PlanEventSubscription, error)
+	HandleNotification(payload string)
+	Reconnect()
+	PruneEvents() error
+}
+
+type planEventUsecase struct {
+	per    repository.IPlanEventRepository
+	pr     repository.IPlanRepository
+	broker *event.Broker
+}
+
+func NewPlanEventUsecase(per repository.IPlanEventRepository, pr repository.IPlanRepository, broker *event.Broker) IPlanEventUsecase {
+	return &planEventUsecase{per: per, pr: pr, broker: broker}
+}
+
+// 再接続時に送り直すイベントの上限。超える場合は送り直さずにresyncを送る
+const planEventReplayLimit = 500
+
+// イベントを保存しておく期間
+const planEventRetention = 24 * time.Hour
+
+// Replayを送った後にEventsを送る。Eventsが閉じられたらクライアントに再接続させる。
+// 送り直すイベントが多すぎる場合、Replayは最新のイベントIDを持つresyncだけになる
+type PlanEventSubscription struct {
+	Replay []model.PlanEvent
+	Events <-chan model.PlanEvent
+	Close  func()
+}
+
+func (peu *planEventUsecase) Publish(planId uint, eventType string, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("publish %s on plan %d: %v", eventType, planId, err)
+		return
+	}
+	planEvent := model.PlanEvent{PlanID: planId, Type: eventType, Data: string(body)}
+	if err := peu.per.CreateEvent(&planEvent); err != nil {
+		log.Printf("publish %s on plan %d: %v", eventType, planId, err)
+	}
+}
+
+// 取りこぼしがないよう、先に購読してからlastEventId以降の保存済みのイベントを読み込む
+func (peu *planEventUsecase) Subscribe(userId uint, planId uint, lastEventId uint) (*PlanEventSubscription, error) {
+	var plan model.Plan
+	if err := getVisiblePlan(peu.pr, &plan, planId, userId); err != nil {
+		return nil, err
+	}
+	events, unsubscribe := peu.broker.Subscribe(planId)
+	sub := &PlanEventSubscription{Replay: []model.PlanEvent{}, Events: events, Close: unsubscribe}
+	if lastEventId == 0 {
+		return sub, nil
+	}
+	if err := peu.per.GetEventsAfter(&sub.Replay, planId, lastEventId, planEventReplayLimit+1); err != nil {
+		unsubscribe()
+		return nil, err
+	}
+	if len(sub.Replay) <= planEventReplayLimit {
+		return sub, nil
+	}
+	var latest model.PlanEvent
+	if err := peu.per.GetLatestEvent(&latest, planId); err != nil {
+		unsubscribe()
+		return nil, err
+	}
+	body, err := json.Marshal(model.ResyncEvent{PlanID: planId})
+	if err != nil {
+		unsubscribe()
+		return nil, err
+	}
+	sub.Replay = []model.PlanEvent{{ID: latest.ID, PlanID: planId, Type: model.PlanEventResync, Data: string(body)}}
+	return sub, nil
+}
+
+// 他のインスタンスを含めて保存されたイベントの通知を受け取り、
+// このインスタンスで購読しているクライアントがいれば配る
+func (peu *planEventUsecase) HandleNotification(payload string) {
+	var planId, eventId uint
+	if _, err := fmt.Sscanf(payload, "%d:%d", &planId, &eventId); err != nil {
+		log.Printf("invalid plan event notification %q: %v", payload, err)
+		return
+	}
+	if !peu.broker.HasSubscribers(planId) {
+		return
+	}
+	var planEvent model.PlanEvent
+	if err := peu.per.GetEventByID(&planEvent, eventId); err != nil {
+		log.Printf("load plan event %d: %v", eventId, err)
+		return
+	}
+	peu.broker.Publish(planEvent)
+}
+
+// 通知を取りこぼした可能性があるため、クライアントにLast-Event-IDで再接続させる
+func (peu *planEventUsecase) Reconnect() {
+	peu.broker.CloseAll()
+}
+
+func (peu *planEventUsecase) PruneEvents() error {
+	return peu.per.DeleteEventsBefore(time.Now().Add(-planEventRetention))
+}
+
+// お気に入りの追加・削除の後に、現在の件数を計画のページとWebhookに配信する
+func publishFavoriteChange(pr repository.IPlanRepository, ep IPlanEventPublisher, wp IWebhookPublisher, userId uint, planId uint, favorited bool) {
+	count, err := pr.GetFavoriteCount(planId)
+	if err != nil {
+		log.Printf("publish favorite count on plan %d: %v", planId, err)
+		return
+	}
+	ep.Publish(planId, model.PlanEventFavoriteCount, model.FavoriteCountEvent{PlanID: planId, FavoriteCount: count})
+	wp.Publish(planId, model.WebhookEventFavoriteToggled, model.FavoriteToggledEvent{
+		PlanID:        planId,
+		UserID:        userId,
+		Favorited:     favorited,
+		FavoriteCount: count,
+	})
+}
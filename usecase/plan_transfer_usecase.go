@@ -0,0 +1,420 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"backend/validator"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type IPlanTransferUsecase interface {
+	ExportPlan(userId uint, planId uint) (model.PlanExport, error)
+	ExportPlanCSV(userId uint, planId uint) ([]byte, error)
+	ExportPlanXLSX(userId uint, planId uint) ([]byte, error)
+	ImportPlan(userId uint, r io.Reader, options model.PlanImportOptions) (model.PlanImportReport, error)
+}
+
+type planTransferUsecase struct {
+	pr  repository.IPlanRepository
+	ctr repository.ICatalogRepository
+	plv validator.IPlanValidator
+	cv  validator.ICourseValidator
+	tv  validator.ITermValidator
+	wp  IWebhookPublisher
+}
+
+func NewPlanTransferUsecase(
+	pr repository.IPlanRepository,
+	ctr repository.ICatalogRepository,
+	plv validator.IPlanValidator,
+	cv validator.ICourseValidator,
+	tv validator.ITermValidator,
+	wp IWebhookPublisher) IPlanTransferUsecase {
+	return &planTransferUsecase{pr: pr, ctr: ctr, plv: plv, cv: cv, tv: tv, wp: wp}
+}
+
+// CSVの見出し。取り込みもこの見出し名で列を探す
+var planCSVHeader = []string{"name", "credits", "category", "room", "color", "grade", "term_kind", "slots", "catalog_course_id", "content"}
+
+func (pu *planTransferUsecase) ExportPlan(userId uint, planId uint) (model.PlanExport, error) {
+	var plan model.Plan
+	if err := getVisiblePlan(pu.pr, &plan, planId, userId); err != nil {
+		return model.PlanExport{}, err
+	}
+
+	export := model.PlanExport{
+		Version:    model.PlanExportVersion,
+		ExportedAt: time.Now(),
+		Title:      plan.Title,
+		Content:    plan.Content,
+		Terms:      make([]model.PlanExportTerm, 0, len(plan.Terms)),
+		Courses:    make([]model.PlanExportCourse, 0, len(plan.Courses)),
+	}
+	terms := make(map[uint]model.PlanTerm, len(plan.Terms))
+	for _, term := range plan.Terms {
+		terms[term.ID] = term
+		export.Terms = append(export.Terms, model.PlanExportTerm{Grade: term.Grade, Kind: term.Kind})
+	}
+	for _, course := range plan.Courses {
+		v := model.PlanExportCourse{
+			Name:            course.Name,
+			Content:         course.Content,
+			Credits:         course.Credits,
+			Category:        course.Category,
+			Room:            course.Room,
+			Color:           course.Color,
+			CatalogCourseID: course.CatalogCourseID,
+			Slots:           make([]model.SlotResponse, 0, len(course.Slots)),
+		}
+		if course.PlanTermID != nil {
+			if term, ok := terms[*course.PlanTermID]; ok {
+				grade, kind := term.Grade, term.Kind
+				v.Grade, v.TermKind = &grade, &kind
+			}
+		}
+		for _, slot := range course.Slots {
+			v.Slots = append(v.Slots, model.SlotResponse{DayOfWeek: slot.DayOfWeek, Period: slot.Period})
+		}
+		export.Courses = append(export.Courses, v)
+	}
+	return export, nil
+}
+
+func (pu *planTransferUsecase) ExportPlanCSV(userId uint, planId uint) ([]byte, error) {
+	export, err := pu.ExportPlan(userId, planId)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	// Excelで開いたときに文字化けしないようBOMを付ける
+	buf.WriteString("\xef\xbb\xbf")
+	w := csv.NewWriter(buf)
+	w.Write(planCSVHeader)
+	for _, course := range export.Courses {
+		w.Write(planCSVRecord(course))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// 先頭に計画の情報、その下に科目の表を置く
+func (pu *planTransferUsecase) ExportPlanXLSX(userId uint, planId uint) ([]byte, error) {
+	export, err := pu.ExportPlan(userId, planId)
+	if err != nil {
+		return nil, err
+	}
+	sheet := &xlsxSheet{name: "履修計画"}
+	sheet.addRow("タイトル", export.Title)
+	sheet.addRow("説明", stringValue(export.Content))
+	sheet.addRow("形式", fmt.Sprintf("v%d", export.Version))
+	sheet.addRow()
+	sheet.addRow("科目名", "単位数", "区分", "教室", "学期", "曜日時限", "カタログID", "メモ")
+	for _, course := range export.Courses {
+		term := ""
+		if course.Grade != nil && course.TermKind != nil {
+			term = planTermName(*course.Grade, *course.TermKind)
+		}
+		var catalogCourseId interface{}
+		if course.CatalogCourseID != nil {
+			catalogCourseId = *course.CatalogCourseID
+		}
+		sheet.addRow(
+			course.Name,
+			course.Credits,
+			stringValue(course.Category),
+			stringValue(course.Room),
+			term,
+			formatSlotResponses(course.Slots),
+			catalogCourseId,
+			stringValue(course.Content),
+		)
+	}
+	return sheet.bytes()
+}
+
+// 全行を検証し、エラーが1件もない場合のみ新しい計画として登録する
+func (pu *planTransferUsecase) ImportPlan(userId uint, r io.Reader, options model.PlanImportOptions) (model.PlanImportReport, error) {
+	var export model.PlanExport
+	var rows []planImportRow
+	switch strings.ToLower(options.Format) {
+	case "", "json":
+		if err := json.NewDecoder(r).Decode(&export); err != nil {
+			return model.PlanImportReport{}, fmt.Errorf("failed to parse json: %w", err)
+		}
+		if export.Version != model.PlanExportVersion {
+			return model.PlanImportReport{}, fmt.Errorf("unsupported version: %d", export.Version)
+		}
+		rows = make([]planImportRow, 0, len(export.Courses))
+		for i, course := range export.Courses {
+			rows = append(rows, planImportRow{row: i + 1, course: course})
+		}
+	case "csv":
+		var err error
+		if rows, err = parsePlanCSV(r); err != nil {
+			return model.PlanImportReport{}, err
+		}
+	default:
+		return model.PlanImportReport{}, fmt.Errorf("unsupported format: %s", options.Format)
+	}
+	if options.Title != "" {
+		export.Title = options.Title
+	}
+	if options.Content != nil {
+		export.Content = options.Content
+	}
+
+	plan := model.Plan{Title: export.Title, Content: export.Content, UserID: userId}
+	if err := pu.plv.PlanValidate(plan); err != nil {
+		return model.PlanImportReport{}, err
+	}
+
+	catalogIds := []uint{}
+	for _, row := range rows {
+		if row.course.CatalogCourseID != nil {
+			catalogIds = append(catalogIds, *row.course.CatalogCourseID)
+		}
+	}
+	catalogExists := map[uint]bool{}
+	if len(catalogIds) > 0 {
+		catalogCourses := []model.CatalogCourse{}
+		if err := pu.ctr.GetCatalogCoursesByIDs(&catalogCourses, catalogIds); err != nil {
+			return model.PlanImportReport{}, err
+		}
+		for _, v := range catalogCourses {
+			catalogExists[v.ID] = true
+		}
+	}
+
+	// 学期は明示されたものと科目から参照されるものを合わせて作る
+	termIndex := map[model.PlanExportTerm]int{}
+	addTerm := func(term model.PlanExportTerm) (int, error) {
+		if i, ok := termIndex[term]; ok {
+			return i, nil
+		}
+		planTerm := model.PlanTerm{Grade: term.Grade, Kind: term.Kind}
+		if err := pu.tv.PlanTermValidate(planTerm); err != nil {
+			return 0, err
+		}
+		planTerm.Sequence = planTermSequence(term.Grade, term.Kind)
+		plan.Terms = append(plan.Terms, planTerm)
+		termIndex[term] = len(plan.Terms) - 1
+		return termIndex[term], nil
+	}
+	for _, term := range export.Terms {
+		if _, err := addTerm(term); err != nil {
+			return model.PlanImportReport{}, fmt.Errorf("term %d %s: %w", term.Grade, term.Kind, err)
+		}
+	}
+
+	report := model.PlanImportReport{Errors: []model.PlanImportRowError{}}
+	for _, row := range rows {
+		v := row.course
+		course := model.Course{
+			Name:            strings.TrimSpace(v.Name),
+			Content:         v.Content,
+			Credits:         v.Credits,
+			Category:        v.Category,
+			Room:            v.Room,
+			Color:           v.Color,
+			CatalogCourseID: v.CatalogCourseID,
+			Slots:           make([]model.CourseSlot, 0, len(v.Slots)),
+		}
+		for _, slot := range v.Slots {
+			course.Slots = append(course.Slots, model.CourseSlot{DayOfWeek: slot.DayOfWeek, Period: slot.Period})
+		}
+
+		// 値の形式が不正な行は、補った空の値による検証エラーを重ねて出さない
+		messages := row.errors
+		if len(messages) == 0 {
+			if err := pu.cv.CourseValidate(course); err != nil {
+				messages = append(messages, flattenValidationError(err)...)
+			}
+		}
+		if v.CatalogCourseID != nil && !catalogExists[*v.CatalogCourseID] {
+			messages = append(messages, fmt.Sprintf("catalog course %d does not exist", *v.CatalogCourseID))
+		}
+		termAt := -1
+		if v.Grade != nil || v.TermKind != nil {
+			if v.Grade == nil || v.TermKind == nil {
+				messages = append(messages, "grade and term_kind must be specified together")
+			} else if at, err := addTerm(model.PlanExportTerm{Grade: *v.Grade, Kind: *v.TermKind}); err != nil {
+				messages = append(messages, flattenValidationError(err)...)
+			} else {
+				termAt = at
+			}
+		}
+		if len(messages) > 0 {
+			report.Errors = append(report.Errors, model.PlanImportRowError{Row: row.row, Name: v.Name, Errors: messages})
+			continue
+		}
+
+		if termAt >= 0 {
+			plan.Terms[termAt].Courses = append(plan.Terms[termAt].Courses, course)
+		} else {
+			plan.Courses = append(plan.Courses, course)
+		}
+	}
+	if len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	if err := pu.pr.ImportPlan(&plan); err != nil {
+		return report, err
+	}
+	report.Valid = true
+	report.Plan = &model.PlanBaseResponse{
+		ID:         plan.ID,
+		Title:      plan.Title,
+		Content:    plan.Content,
+		UserID:     plan.UserID,
+		Visibility: plan.Visibility,
+		CreatedAt:  plan.CreatedAt,
+		UpdatedAt:  plan.UpdatedAt,
+	}
+	pu.wp.Publish(plan.ID, model.WebhookEventPlanCreated, *report.Plan)
+	return report, nil
+}
+
+func planCSVRecord(course model.PlanExportCourse) []string {
+	grade, termKind, catalogCourseId := "", "", ""
+	if course.Grade != nil {
+		grade = strconv.FormatUint(uint64(*course.Grade), 10)
+	}
+	if course.TermKind != nil {
+		termKind = *course.TermKind
+	}
+	if course.CatalogCourseID != nil {
+		catalogCourseId = strconv.FormatUint(uint64(*course.CatalogCourseID), 10)
+	}
+	return []string{
+		course.Name,
+		strconv.FormatUint(uint64(course.Credits), 10),
+		stringValue(course.Category),
+		stringValue(course.Room),
+		stringValue(course.Color),
+		grade,
+		termKind,
+		formatSlotResponses(course.Slots),
+		catalogCourseId,
+		stringValue(course.Content),
+	}
+}
+
+type planImportRow struct {
+	row    int
+	course model.PlanExportCourse
+	errors []string
+}
+
+// CSVは科目のみを持つため、タイトルと説明は取り込み時に指定する
+func parsePlanCSV(r io.Reader) ([]planImportRow, error) {
+	reader := csv.NewReader(skipBOM(r))
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("column name is not found")
+	}
+
+	rows := []planImportRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, planImportRow{row: parseErr.StartLine, errors: []string{parseErr.Err.Error()}})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlankRecord(record) {
+			continue
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		optional := func(name string) *string {
+			if v := field(name); v != "" {
+				return &v
+			}
+			return nil
+		}
+		row := planImportRow{row: line}
+		row.course = model.PlanExportCourse{
+			Name:     field("name"),
+			Content:  optional("content"),
+			Category: optional("category"),
+			Room:     optional("room"),
+			Color:    optional("color"),
+			TermKind: optional("term_kind"),
+			Slots:    []model.SlotResponse{},
+		}
+		if v := field("credits"); v != "" {
+			credits, err := parseCredits(v)
+			if err != nil {
+				row.errors = append(row.errors, err.Error())
+			}
+			row.course.Credits = credits
+		}
+		if v := field("grade"); v != "" {
+			grade, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				row.errors = append(row.errors, fmt.Sprintf("grade %q is not a valid number", v))
+			} else {
+				g := uint(grade)
+				row.course.Grade = &g
+			}
+		}
+		if v := field("catalog_course_id"); v != "" {
+			id, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				row.errors = append(row.errors, fmt.Sprintf("catalog_course_id %q is not a valid number", v))
+			} else {
+				catalogCourseId := uint(id)
+				row.course.CatalogCourseID = &catalogCourseId
+			}
+		}
+		slots, err := parseSlots(field("slots"))
+		if err != nil {
+			row.errors = append(row.errors, err.Error())
+		}
+		for _, slot := range slots {
+			row.course.Slots = append(row.course.Slots, model.SlotResponse{DayOfWeek: slot.DayOfWeek, Period: slot.Period})
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func formatSlotResponses(slots []model.SlotResponse) string {
+	catalogSlots := make([]model.CatalogCourseSlot, 0, len(slots))
+	for _, slot := range slots {
+		catalogSlots = append(catalogSlots, model.CatalogCourseSlot{DayOfWeek: slot.DayOfWeek, Period: slot.Period})
+	}
+	return formatSlots(catalogSlots)
+}
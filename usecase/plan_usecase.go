@@ -5,81 +5,68 @@ import (
 	"backend/repository"
 	"backend/validator"
 	"errors"
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
 )
 
 type IPlanUsecase interface {
-	GetAllPlans(offset int, limit int) ([]model.PlanResponse, error)
-	GetPlanByID(planId uint) (model.PlanDetailResponse, error)
+	GetAllPlans(userId uint, offset int, limit int) ([]model.PlanResponse, error)
+	GetPlanByID(userId uint, planId uint) (model.PlanDetailResponse, error)
 	CreatePlan(plan *model.Plan) (model.PlanBaseResponse, error)
-	UpdatePlan(plan *model.Plan, planId uint) (model.PlanUpdateResponse, error) // planId の型を uint に変更
-	DeletePlanByID(planId uint) error
+	UpdatePlan(userId uint, plan *model.Plan, planId uint) (model.PlanUpdateResponse, error) // planId の型を uint に変更
+	DeletePlanByID(userId uint, planId uint) error
 	ToggleFavoritePlan(userId, planId uint) error
-	GetFavoriteCount(planId uint) (int64, error)
+	GetFavoriteCount(userId uint, planId uint) (int64, error)
+	GetMyFavoritePlans(userId uint, offset int, limit int) ([]model.MyFavoritePlanResponse, error)
+	ComparePlans(userId uint, planIds []uint) (model.PlanCompareResponse, error)
+	ForkPlan(userId uint, planId uint) (model.PlanBaseResponse, error)
 }
 
 type planUsecase struct {
 	pr  repository.IPlanRepository
 	plv validator.IPlanValidator
+	n   INotifier
+	ep  IPlanEventPublisher
+	wp  IWebhookPublisher
 }
 
-func NewPlanUsecase(pr repository.IPlanRepository, plv validator.IPlanValidator) IPlanUsecase {
-	return &planUsecase{pr: pr, plv: plv}
+func NewPlanUsecase(pr repository.IPlanRepository, plv validator.IPlanValidator, n INotifier, ep IPlanEventPublisher, wp IWebhookPublisher) IPlanUsecase {
+	return &planUsecase{pr: pr, plv: plv, n: n, ep: ep, wp: wp}
 }
 
-func (pu *planUsecase) GetAllPlans(offset int, limit int) ([]model.PlanResponse, error) {
+func (pu *planUsecase) GetAllPlans(userId uint, offset int, limit int) ([]model.PlanResponse, error) {
 	var plans []model.Plan
-	if err := pu.pr.GetAllPlans(&plans, offset, limit); err != nil {
+	if err := pu.pr.GetAllPlans(&plans, userId, offset, limit); err != nil {
 		return nil, err
 	}
 
-	// スライスの容量を事前に確保
-	resPlans := make([]model.PlanResponse, 0, len(plans))
-	for _, plan := range plans {
-		resPlans = append(resPlans, model.PlanResponse{
-			ID:        plan.ID,
-			Title:     plan.Title,
-			Content:   plan.Content,
-			UserID:    plan.UserID,
-			CreatedAt: plan.CreatedAt,
-			UpdatedAt: plan.UpdatedAt,
-			UserResponse: model.UserResponse{
-				ID:         plan.User.ID,
-				Email:      plan.User.Email,
-				University: plan.User.University,
-				Faculty:    plan.User.Faculty,
-				Department: plan.User.Department,
-			},
-		})
-	}
-	return resPlans, nil
+	return toPlanResponses(pu.pr, plans, userId)
 }
 
-func (pu *planUsecase) GetPlanByID(planId uint) (model.PlanDetailResponse, error) {
+func (pu *planUsecase) GetPlanByID(userId uint, planId uint) (model.PlanDetailResponse, error) {
 	var plan model.Plan
-	if err := pu.pr.GetPlanByID(&plan, planId); err != nil {
+	if err := getVisiblePlan(pu.pr, &plan, planId, userId); err != nil {
 		return model.PlanDetailResponse{}, err
 	}
 
 	courses := make([]model.CourseResponse, 0, len(plan.Courses))
 	for _, course := range plan.Courses {
-		courses = append(courses, model.CourseResponse{
-			ID:      course.ID,
-			Name:    course.Name,
-			Content: course.Content,
-		})
+		courses = append(courses, toCourseResponse(course))
 	}
 
 	posts := make([]model.PostResponse, 0, len(plan.Posts))
 	for _, post := range plan.Posts {
-		posts = append(posts, model.PostResponse{
-			ID:        post.ID,
-			Content:   post.Content,
-			CreatedAt: post.CreatedAt,
-		})
+		posts = append(posts, toPostResponse(post, userId))
 	}
 
 	favorites := make([]model.FavoritePlanResponse, 0, len(plan.Favorites))
+	favoritedByMe := false
 	for _, favorite := range plan.Favorites {
+		if favorite.UserID == userId {
+			favoritedByMe = true
+		}
 		favorites = append(favorites, model.FavoritePlanResponse{
 			ID:     favorite.ID,
 			UserID: favorite.UserID,
@@ -88,15 +75,20 @@ func (pu *planUsecase) GetPlanByID(planId uint) (model.PlanDetailResponse, error
 	}
 
 	resPlan := model.PlanDetailResponse{
-		ID:        plan.ID,
-		Title:     plan.Title,
-		Content:   plan.Content,
-		UserID:    plan.UserID,
-		CreatedAt: plan.CreatedAt,
-		UpdatedAt: plan.UpdatedAt,
+		ID:            plan.ID,
+		Title:         plan.Title,
+		Content:       plan.Content,
+		UserID:        plan.UserID,
+		Visibility:    plan.Visibility,
+		ForkedFromID:  plan.ForkedFromID,
+		FavoriteCount: int64(len(plan.Favorites)),
+		FavoritedByMe: favoritedByMe,
+		CreatedAt:     plan.CreatedAt,
+		UpdatedAt:     plan.UpdatedAt,
 		User: model.UserResponse{
 			ID:         plan.User.ID,
 			Email:      plan.User.Email,
+			Handle:     plan.User.Handle,
 			University: plan.User.University,
 			Faculty:    plan.User.Faculty,
 			Department: plan.User.Department,
@@ -104,6 +96,7 @@ func (pu *planUsecase) GetPlanByID(planId uint) (model.PlanDetailResponse, error
 		Courses:   courses,
 		Posts:     posts,
 		Favorites: favorites,
+		Terms:     toPlanTermsResponse(plan),
 	}
 	return resPlan, nil
 }
@@ -120,21 +113,27 @@ func (pu *planUsecase) CreatePlan(plan *model.Plan) (model.PlanBaseResponse, err
 		return model.PlanBaseResponse{}, err
 	}
 	resPlan := model.PlanBaseResponse{
-		ID:        plan.ID,
-		Title:     plan.Title,
-		Content:   plan.Content,
-		UserID:    plan.UserID,
-		CreatedAt: plan.CreatedAt,
-		UpdatedAt: plan.UpdatedAt,
+		ID:         plan.ID,
+		Title:      plan.Title,
+		Content:    plan.Content,
+		UserID:     plan.UserID,
+		Visibility: plan.Visibility,
+		CreatedAt:  plan.CreatedAt,
+		UpdatedAt:  plan.UpdatedAt,
 	}
+	pu.wp.Publish(plan.ID, model.WebhookEventPlanCreated, resPlan)
 	return resPlan, nil
 }
 
-func (pu *planUsecase) UpdatePlan(plan *model.Plan, planId uint) (model.PlanUpdateResponse, error) {
+// 作成者のみ更新できる
+func (pu *planUsecase) UpdatePlan(userId uint, plan *model.Plan, planId uint) (model.PlanUpdateResponse, error) {
 	// nilチェック
 	if plan == nil {
 		return model.PlanUpdateResponse{}, errors.New("plan is nil")
 	}
+	if err := pu.checkPlanOwner(userId, planId); err != nil {
+		return model.PlanUpdateResponse{}, err
+	}
 	if err := pu.plv.PlanValidate(*plan); err != nil {
 		return model.PlanUpdateResponse{}, err
 	}
@@ -142,23 +141,336 @@ func (pu *planUsecase) UpdatePlan(plan *model.Plan, planId uint) (model.PlanUpda
 		return model.PlanUpdateResponse{}, err
 	}
 	resPlan := model.PlanUpdateResponse{
-		ID:        plan.ID,
-		Title:     plan.Title,
-		Content:   plan.Content,
-		CreatedAt: plan.CreatedAt,
-		UpdatedAt: plan.UpdatedAt,
+		ID:         plan.ID,
+		Title:      plan.Title,
+		Content:    plan.Content,
+		Visibility: plan.Visibility,
+		CreatedAt:  plan.CreatedAt,
+		UpdatedAt:  plan.UpdatedAt,
 	}
+	pu.wp.Publish(plan.ID, model.WebhookEventPlanUpdated, resPlan)
 	return resPlan, nil
 }
 
-func (pu *planUsecase) DeletePlanByID(planId uint) error {
+// 作成者のみ削除できる
+func (pu *planUsecase) DeletePlanByID(userId uint, planId uint) error {
+	if err := pu.checkPlanOwner(userId, planId); err != nil {
+		return err
+	}
 	return pu.pr.DeletePlanByID(planId)
 }
 
+func (pu *planUsecase) checkPlanOwner(userId uint, planId uint) error {
+	var plan model.Plan
+	if err := pu.pr.GetPlanByID(&plan, planId); err != nil {
+		return err
+	}
+	if plan.UserID != userId {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// お気に入りに追加した場合のみ計画の作成者に通知する
 func (pu *planUsecase) ToggleFavoritePlan(userId, planId uint) error {
-	return pu.pr.ToggleFavoritePlan(userId, planId)
+	var plan model.Plan
+	if err := getVisiblePlan(pu.pr, &plan, planId, userId); err != nil {
+		return err
+	}
+	favorited, err := pu.pr.ToggleFavoritePlan(userId, planId)
+	if err != nil {
+		return err
+	}
+	publishFavoriteChange(pu.pr, pu.ep, pu.wp, userId, planId, favorited)
+	if !favorited {
+		return nil
+	}
+	pu.n.Notify(favoriteNotification(plan, userId))
+	return nil
 }
 
-func (pu *planUsecase) GetFavoriteCount(planId uint) (int64, error) {
+func (pu *planUsecase) GetFavoriteCount(userId uint, planId uint) (int64, error) {
+	var plan model.Plan
+	if err := getVisiblePlan(pu.pr, &plan, planId, userId); err != nil {
+		return 0, err
+	}
 	return pu.pr.GetFavoriteCount(planId)
 }
+
+func (pu *planUsecase) GetMyFavoritePlans(userId uint, offset int, limit int) ([]model.MyFavoritePlanResponse, error) {
+	var favorites []model.FavoritePlan
+	if err := pu.pr.GetFavoritePlans(&favorites, userId, offset, limit); err != nil {
+		return nil, err
+	}
+	return toMyFavoritePlanResponses(pu.pr, favorites, userId)
+}
+
+// 2〜3件の計画の科目・単位数・時間割を比較する
+func (pu *planUsecase) ComparePlans(userId uint, planIds []uint) (model.PlanCompareResponse, error) {
+	if len(planIds) < 2 || len(planIds) > 3 {
+		return model.PlanCompareResponse{}, errors.New("specify 2 or 3 plans to compare")
+	}
+	plans := make([]model.Plan, 0, len(planIds))
+	seen := map[uint]bool{}
+	for _, planId := range planIds {
+		if seen[planId] {
+			return model.PlanCompareResponse{}, fmt.Errorf("plan %d is duplicated", planId)
+		}
+		seen[planId] = true
+		var plan model.Plan
+		if err := getVisiblePlan(pu.pr, &plan, planId, userId); err != nil {
+			return model.PlanCompareResponse{}, err
+		}
+		plans = append(plans, plan)
+	}
+
+	res := model.PlanCompareResponse{
+		Plans:         make([]model.PlanCompareSummary, 0, len(plans)),
+		SharedCourses: []model.PlanCompareCourse{},
+		UniqueCourses: map[uint][]model.PlanCompareCourse{},
+		Timetable:     []model.PlanCompareSlot{},
+	}
+
+	// 同じ科目をまとめる。カタログIDで見つからない場合は科目名で探す
+	groups := []model.PlanCompareCourse{}
+	byCatalogId := map[uint]int{}
+	byName := map[string]int{}
+	type slotKey struct{ day, period int }
+	slots := map[slotKey][]model.PlanCompareSlotEntry{}
+
+	for _, plan := range plans {
+		summary := model.PlanCompareSummary{
+			ID:                plan.ID,
+			Title:             plan.Title,
+			UserID:            plan.UserID,
+			CourseCount:       len(plan.Courses),
+			CreditsByCategory: map[string]uint{},
+		}
+		for _, course := range plan.Courses {
+			summary.TotalCredits += course.Credits
+			summary.CreditsByCategory[stringValue(course.Category)] += course.Credits
+
+			name := normalizeText(course.Name)
+			i, ok := -1, false
+			if course.CatalogCourseID != nil {
+				i, ok = byCatalogId[*course.CatalogCourseID]
+			}
+			// 別のカタログ科目と同名の場合は同一とみなさない
+			if !ok {
+				if j, found := byName[name]; found && (course.CatalogCourseID == nil || groups[j].CatalogCourseID == nil) {
+					i, ok = j, true
+				}
+			}
+			if !ok {
+				groups = append(groups, model.PlanCompareCourse{
+					Name:            course.Name,
+					CatalogCourseID: course.CatalogCourseID,
+					Credits:         course.Credits,
+				})
+				i = len(groups) - 1
+			}
+			if course.CatalogCourseID != nil {
+				byCatalogId[*course.CatalogCourseID] = i
+			}
+			byName[name] = i
+			if n := len(groups[i].PlanIDs); n == 0 || groups[i].PlanIDs[n-1] != plan.ID {
+				groups[i].PlanIDs = append(groups[i].PlanIDs, plan.ID)
+			}
+
+			for _, slot := range course.Slots {
+				key := slotKey{slot.DayOfWeek, slot.Period}
+				slots[key] = append(slots[key], model.PlanCompareSlotEntry{PlanID: plan.ID, CourseName: course.Name})
+			}
+		}
+		if len(res.Plans) > 0 {
+			summary.CreditDifference = int(summary.TotalCredits) - int(res.Plans[0].TotalCredits)
+		}
+		res.Plans = append(res.Plans, summary)
+		res.UniqueCourses[plan.ID] = []model.PlanCompareCourse{}
+	}
+
+	for _, group := range groups {
+		if len(group.PlanIDs) > 1 {
+			res.SharedCourses = append(res.SharedCourses, group)
+		} else {
+			res.UniqueCourses[group.PlanIDs[0]] = append(res.UniqueCourses[group.PlanIDs[0]], group)
+		}
+	}
+
+	for key, entries := range slots {
+		res.Timetable = append(res.Timetable, model.PlanCompareSlot{DayOfWeek: key.day, Period: key.period, Entries: entries})
+	}
+	sort.Slice(res.Timetable, func(i, j int) bool {
+		if res.Timetable[i].DayOfWeek != res.Timetable[j].DayOfWeek {
+			return res.Timetable[i].DayOfWeek < res.Timetable[j].DayOfWeek
+		}
+		return res.Timetable[i].Period < res.Timetable[j].Period
+	})
+	return res, nil
+}
+
+// 閲覧できる計画を学期・科目・時間割ごと自分の計画としてコピーする
+func (pu *planUsecase) ForkPlan(userId uint, planId uint) (model.PlanBaseResponse, error) {
+	var source model.Plan
+	if err := getVisiblePlan(pu.pr, &source, planId, userId); err != nil {
+		return model.PlanBaseResponse{}, err
+	}
+
+	plan := model.Plan{
+		Title:        source.Title,
+		Content:      source.Content,
+		UserID:       userId,
+		Visibility:   source.Visibility,
+		ForkedFromID: &source.ID,
+	}
+	termIndex := map[uint]int{}
+	for _, term := range source.Terms {
+		termIndex[term.ID] = len(plan.Terms)
+		plan.Terms = append(plan.Terms, model.PlanTerm{
+			Grade:          term.Grade,
+			Kind:           term.Kind,
+			Sequence:       term.Sequence,
+			AcademicTermID: term.AcademicTermID,
+		})
+	}
+	for _, course := range source.Courses {
+		slots := make([]model.CourseSlot, 0, len(course.Slots))
+		for _, slot := range course.Slots {
+			slots = append(slots, model.CourseSlot{DayOfWeek: slot.DayOfWeek, Period: slot.Period})
+		}
+		copied := model.Course{
+			Name:            course.Name,
+			Content:         course.Content,
+			Credits:         course.Credits,
+			Category:        course.Category,
+			Room:            course.Room,
+			Color:           course.Color,
+			CatalogCourseID: course.CatalogCourseID,
+			Slots:           slots,
+		}
+		if course.PlanTermID != nil {
+			if i, ok := termIndex[*course.PlanTermID]; ok {
+				plan.Terms[i].Courses = append(plan.Terms[i].Courses, copied)
+				continue
+			}
+		}
+		plan.Courses = append(plan.Courses, copied)
+	}
+	if err := pu.pr.ImportPlan(&plan); err != nil {
+		return model.PlanBaseResponse{}, err
+	}
+	pu.n.Notify(model.Notification{
+		UserID:  source.UserID,
+		Type:    model.NotificationTypeFork,
+		ActorID: &userId,
+		PlanID:  &source.ID,
+	})
+	res := model.PlanBaseResponse{
+		ID:           plan.ID,
+		Title:        plan.Title,
+		Content:      plan.Content,
+		UserID:       plan.UserID,
+		Visibility:   plan.Visibility,
+		ForkedFromID: plan.ForkedFromID,
+		CreatedAt:    plan.CreatedAt,
+		UpdatedAt:    plan.UpdatedAt,
+	}
+	pu.wp.Publish(plan.ID, model.WebhookEventPlanCreated, res)
+	return res, nil
+}
+
+// お気に入り数と閲覧者のお気に入り状態は計画ごとではなくまとめて取得する
+func toPlanResponses(pr repository.IPlanRepository, plans []model.Plan, userId uint) ([]model.PlanResponse, error) {
+	planIds := make([]uint, 0, len(plans))
+	for _, plan := range plans {
+		planIds = append(planIds, plan.ID)
+	}
+	counts, err := pr.GetFavoriteCounts(planIds)
+	if err != nil {
+		return nil, err
+	}
+	favorited, err := pr.GetFavoritedPlanIDs(userId, planIds)
+	if err != nil {
+		return nil, err
+	}
+
+	// スライスの容量を事前に確保
+	resPlans := make([]model.PlanResponse, 0, len(plans))
+	for _, plan := range plans {
+		resPlan := toPlanResponse(plan)
+		resPlan.FavoriteCount = counts[plan.ID]
+		resPlan.FavoritedByMe = favorited[plan.ID]
+		resPlans = append(resPlans, resPlan)
+	}
+	return resPlans, nil
+}
+
+func toMyFavoritePlanResponses(pr repository.IPlanRepository, favorites []model.FavoritePlan, userId uint) ([]model.MyFavoritePlanResponse, error) {
+	plans := make([]model.Plan, 0, len(favorites))
+	for _, favorite := range favorites {
+		plans = append(plans, favorite.Plan)
+	}
+	resPlans, err := toPlanResponses(pr, plans, userId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]model.MyFavoritePlanResponse, 0, len(favorites))
+	for i, favorite := range favorites {
+		collectionIds := make([]uint, 0, len(favorite.CollectionItems))
+		for _, item := range favorite.CollectionItems {
+			collectionIds = append(collectionIds, item.CollectionID)
+		}
+		res = append(res, model.MyFavoritePlanResponse{
+			FavoritedAt:   favorite.CreatedAt,
+			Note:          favorite.Note,
+			CollectionIDs: collectionIds,
+			Plan:          resPlans[i],
+		})
+	}
+	return res, nil
+}
+
+func toPlanResponse(plan model.Plan) model.PlanResponse {
+	return model.PlanResponse{
+		ID:         plan.ID,
+		Title:      plan.Title,
+		Content:    plan.Content,
+		UserID:     plan.UserID,
+		Visibility: plan.Visibility,
+		CreatedAt:  plan.CreatedAt,
+		UpdatedAt:  plan.UpdatedAt,
+		UserResponse: model.UserResponse{
+			ID:         plan.User.ID,
+			Email:      plan.User.Email,
+			Handle:     plan.User.Handle,
+			University: plan.User.University,
+			Faculty:    plan.User.Faculty,
+			Department: plan.User.Department,
+		},
+	}
+}
+
+// 非公開の計画は作成者以外には存在しないものとして扱う
+func getVisiblePlan(pr repository.IPlanRepository, plan *model.Plan, planId uint, userId uint) error {
+	if err := pr.GetPlanByID(plan, planId); err != nil {
+		return err
+	}
+	if !canViewPlan(*plan, userId) {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func canViewPlan(plan model.Plan, userId uint) bool {
+	return plan.Visibility != model.PlanVisibilityPrivate || plan.UserID == userId
+}
+
+// 計画の作成者へのお気に入り登録の通知
+func favoriteNotification(plan model.Plan, userId uint) model.Notification {
+	return model.Notification{
+		UserID:  plan.UserID,
+		Type:    model.NotificationTypeFavorite,
+		ActorID: &userId,
+		PlanID:  &plan.ID,
+	}
+}
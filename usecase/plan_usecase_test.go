@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/validator"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func newPlanUsecaseForTest() (*planUsecase, *fakePlanRepository) {
+	pr := &fakePlanRepository{plans: map[uint]model.Plan{
+		1: {ID: 1, UserID: 10, Title: "計画", Visibility: model.PlanVisibilityPrivate},
+	}}
+	pu := NewPlanUsecase(pr, validator.NewPlanValidator(), nil, &fakePublisher{}, &fakePublisher{}).(*planUsecase)
+	return pu, pr
+}
+
+func TestUpdatePlanRequiresOwner(t *testing.T) {
+	tests := []struct {
+		name    string
+		userId  uint
+		planId  uint
+		wantErr error
+	}{
+		{"作成者は更新できる", 10, 1, nil},
+		{"他のユーザーは更新できない", 20, 1, ErrForbidden},
+		{"存在しない計画", 10, 2, gorm.ErrRecordNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pu, pr := newPlanUsecaseForTest()
+			content := "内容"
+			plan := &model.Plan{Title: "変更後", Content: &content, Visibility: model.PlanVisibilityPublic}
+			_, err := pu.UpdatePlan(tt.userId, plan, tt.planId)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && len(pr.updated) > 0 {
+				t.Fatalf("plan was updated: %v", pr.updated)
+			}
+			if tt.wantErr == nil && pr.plans[1].UserID != 10 {
+				t.Fatalf("owner changed to %d", pr.plans[1].UserID)
+			}
+		})
+	}
+}
+
+func TestDeletePlanByIDRequiresOwner(t *testing.T) {
+	tests := []struct {
+		name    string
+		userId  uint
+		planId  uint
+		wantErr error
+	}{
+		{"作成者は削除できる", 10, 1, nil},
+		{"他のユーザーは削除できない", 20, 1, ErrForbidden},
+		{"存在しない計画", 10, 2, gorm.ErrRecordNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pu, pr := newPlanUsecaseForTest()
+			err := pu.DeletePlanByID(tt.userId, tt.planId)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && len(pr.deleted) > 0 {
+				t.Fatalf("plan was deleted: %v", pr.deleted)
+			}
+		})
+	}
+}
@@ -4,22 +4,32 @@ import (
 	"backend/model"
 	"backend/repository"
 	"backend/validator"
+	"log"
 )
 
 type IPostUsecase interface {
 	GetAllPosts(author_id uint) ([]model.PostResponse, error)
-	GetPostByID(planId uint) ([]model.PostResponse, error)
+	GetPostByID(planId uint, userId uint) ([]model.PostResponse, error)
 	CreatePost(post *model.Post) (model.PostResponse, error)
 	DeletePostByID(postId uint) error
 }
 
 type postUsecase struct {
-	pr repository.IPostRepository
-	pv validator.IPostValidator
+	pr  repository.IPostRepository
+	ur  repository.IUserRepository
+	plr repository.IPlanRepository
+	pv  validator.IPostValidator
+	n   INotifier
 }
 
-func NewPostUsecase(pr repository.IPostRepository, pv validator.IPostValidator) IPostUsecase {
-	return &postUsecase{pr, pv}
+func NewPostUsecase(
+	pr repository.IPostRepository,
+	ur repository.IUserRepository,
+	plr repository.IPlanRepository,
+	pv validator.IPostValidator,
+	n INotifier,
+) IPostUsecase {
+	return &postUsecase{pr: pr, ur: ur, plr: plr, pv: pv, n: n}
 }
 
 func (pu *postUsecase) GetAllPosts(author_id uint) ([]model.PostResponse, error) {
@@ -29,49 +39,82 @@ func (pu *postUsecase) GetAllPosts(author_id uint) ([]model.PostResponse, error)
 	}
 	resPosts := []model.PostResponse{}
 	for _, v := range posts {
-		p := model.PostResponse{
-			ID:        v.ID,
-			Content:   v.Content,
-			CreatedAt: v.CreatedAt,
-		}
-		resPosts = append(resPosts, p)
+		resPosts = append(resPosts, toPostResponse(v, author_id))
 	}
 	return resPosts, nil
 
 }
 
-func (pu *postUsecase) GetPostByID(planId uint) ([]model.PostResponse, error) {
+// 非公開の計画への質問は作成者のみ取得できる
+func (pu *postUsecase) GetPostByID(planId uint, userId uint) ([]model.PostResponse, error) {
+	var plan model.Plan
+	if err := getVisiblePlan(pu.plr, &plan, planId, userId); err != nil {
+		return nil, err
+	}
 	posts := []model.Post{}
 	if err := pu.pr.GetPostByID(&posts, planId); err != nil {
 		return nil, err
 	}
 	resPosts := []model.PostResponse{}
 	for _, v := range posts {
-		p := model.PostResponse{
-			ID:        v.ID,
-			Content:   v.Content,
-			CreatedAt: v.CreatedAt,
-		}
-		resPosts = append(resPosts, p)
+		resPosts = append(resPosts, toPostResponse(v, userId))
 	}
 
 	return resPosts, nil
 }
 
+// 閲覧できない計画には質問できない
 func (pu *postUsecase) CreatePost(post *model.Post) (model.PostResponse, error) {
 	if err := pu.pv.PostValidate(*post); err != nil {
 		return model.PostResponse{}, err
 	}
+	if post.PlanID != nil {
+		var plan model.Plan
+		if err := getVisiblePlan(pu.plr, &plan, *post.PlanID, post.AuthorID); err != nil {
+			return model.PostResponse{}, err
+		}
+	}
+	post.AcceptedAnswerID = nil
+	post.Answers = nil
+	post.Mentions = nil
+	post.Reactions = nil
+	if post.Content != nil {
+		mentions, err := resolveMentions(pu.ur, *post.Content, &post.AuthorID)
+		if err != nil {
+			return model.PostResponse{}, err
+		}
+		post.Mentions = mentions
+	}
 
 	if err := pu.pr.CreatePost(post); err != nil {
 		return model.PostResponse{}, err
 	}
-	resPost := model.PostResponse{
-		ID:        post.ID,
-		Content:   post.Content,
-		CreatedAt: post.CreatedAt,
+	pu.notifyPost(*post)
+	return toPostResponse(*post, post.AuthorID), nil
+}
+
+// 計画への質問は計画の作成者に、言及は言及されたユーザーに通知する
+func (pu *postUsecase) notifyPost(post model.Post) {
+	base := model.Notification{ActorID: &post.AuthorID, PlanID: post.PlanID, PostID: &post.ID}
+	notifications := []model.Notification{}
+	if post.PlanID != nil {
+		var plan model.Plan
+		if err := pu.plr.GetPlanByID(&plan, *post.PlanID); err != nil {
+			log.Printf("notify question on plan %d: %v", *post.PlanID, err)
+		} else {
+			question := base
+			question.UserID = plan.UserID
+			question.Type = model.NotificationTypeQuestion
+			notifications = append(notifications, question)
+		}
+	}
+	for _, mention := range post.Mentions {
+		notification := base
+		notification.UserID = mention.UserID
+		notification.Type = model.NotificationTypeMention
+		notifications = append(notifications, notification)
 	}
-	return resPost, nil
+	pu.n.Notify(notifications...)
 }
 
 func (pu *postUsecase) DeletePostByID(postId uint) error {
@@ -80,3 +123,17 @@ func (pu *postUsecase) DeletePostByID(postId uint) error {
 	}
 	return nil
 }
+
+func toPostResponse(post model.Post, viewerID uint) model.PostResponse {
+	return model.PostResponse{
+		ID:               post.ID,
+		Content:          post.Content,
+		PlanID:           post.PlanID,
+		AuthorID:         post.AuthorID,
+		AnswerCount:      len(post.Answers),
+		AcceptedAnswerID: post.AcceptedAnswerID,
+		CreatedAt:        post.CreatedAt,
+		Mentions:         toMentionSpans(post.Mentions),
+		Reactions:        toReactionSummaries(post.Reactions, viewerID),
+	}
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/validator"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestCreatePostChecksPlanVisibility(t *testing.T) {
+	planId := func(id uint) *uint { return &id }
+	tests := []struct {
+		name    string
+		author  uint
+		planId  *uint
+		wantErr error
+	}{
+		{"公開の計画には誰でも質問できる", 20, planId(1), nil},
+		{"自分の非公開の計画には質問できる", 10, planId(2), nil},
+		{"他人の非公開の計画には質問できない", 20, planId(2), gorm.ErrRecordNotFound},
+		{"存在しない計画には質問できない", 20, planId(3), gorm.ErrRecordNotFound},
+		{"計画に紐づかない質問", 20, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plr := &fakePlanRepository{plans: map[uint]model.Plan{
+				1: {ID: 1, UserID: 10, Visibility: model.PlanVisibilityPublic},
+				2: {ID: 2, UserID: 10, Visibility: model.PlanVisibilityPrivate},
+			}}
+			pr := &fakePostRepository{}
+			n := &fakeNotifier{}
+			pu := NewPostUsecase(pr, nil, plr, validator.NewPostValidator(), n)
+
+			content := "質問です"
+			_, err := pu.CreatePost(&model.Post{Content: &content, PlanID: tt.planId, AuthorID: tt.author})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && (len(pr.created) > 0 || len(n.notifications) > 0) {
+				t.Fatalf("post was created: %v, notifications: %v", pr.created, n.notifications)
+			}
+		})
+	}
+}
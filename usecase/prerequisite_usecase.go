@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"errors"
+	"sort"
+)
+
+type IPrerequisiteUsecase interface {
+	GetPrerequisiteGraph(universityId uint) (model.PrerequisiteGraphResponse, error)
+	GetPrerequisitesByCourseID(catalogCourseId uint) ([]model.CoursePrerequisiteResponse, error)
+	CreatePrerequisite(prerequisite *model.CoursePrerequisite) (model.CoursePrerequisiteResponse, error)
+	DeletePrerequisiteByID(prerequisiteId uint) error
+	CheckPlan(userId uint, planId uint) (model.PrerequisiteCheckResponse, error)
+}
+
+type prerequisiteUsecase struct {
+	prr repository.IPrerequisiteRepository
+	cr  repository.ICatalogRepository
+	pr  repository.IPlanRepository
+}
+
+func NewPrerequisiteUsecase(
+	prr repository.IPrerequisiteRepository, cr repository.ICatalogRepository, pr repository.IPlanRepository) IPrerequisiteUsecase {
+	return &prerequisiteUsecase{prr: prr, cr: cr, pr: pr}
+}
+
+func (pu *prerequisiteUsecase) GetPrerequisiteGraph(universityId uint) (model.PrerequisiteGraphResponse, error) {
+	var prerequisites []model.CoursePrerequisite
+	if err := pu.prr.GetPrerequisitesByUniversityID(&prerequisites, universityId); err != nil {
+		return model.PrerequisiteGraphResponse{}, err
+	}
+
+	nodes := map[uint]model.PrerequisiteGraphNode{}
+	edges := make([]model.CoursePrerequisiteResponse, 0, len(prerequisites))
+	for _, p := range prerequisites {
+		nodes[p.CatalogCourse.ID] = model.PrerequisiteGraphNode{ID: p.CatalogCourse.ID, Code: p.CatalogCourse.Code, Name: p.CatalogCourse.Name}
+		nodes[p.RequiredCourse.ID] = model.PrerequisiteGraphNode{ID: p.RequiredCourse.ID, Code: p.RequiredCourse.Code, Name: p.RequiredCourse.Name}
+		edges = append(edges, toCoursePrerequisiteResponse(p))
+	}
+
+	resNodes := make([]model.PrerequisiteGraphNode, 0, len(nodes))
+	for _, node := range nodes {
+		resNodes = append(resNodes, node)
+	}
+	sort.Slice(resNodes, func(i, j int) bool { return resNodes[i].Code < resNodes[j].Code })
+	return model.PrerequisiteGraphResponse{Nodes: resNodes, Edges: edges}, nil
+}
+
+func (pu *prerequisiteUsecase) GetPrerequisitesByCourseID(catalogCourseId uint) ([]model.CoursePrerequisiteResponse, error) {
+	var prerequisites []model.CoursePrerequisite
+	if err := pu.prr.GetPrerequisitesByCourseIDs(&prerequisites, []uint{catalogCourseId}); err != nil {
+		return nil, err
+	}
+	res := make([]model.CoursePrerequisiteResponse, 0, len(prerequisites))
+	for _, p := range prerequisites {
+		res = append(res, toCoursePrerequisiteResponse(p))
+	}
+	return res, nil
+}
+
+// 循環する依存関係は登録できない
+func (pu *prerequisiteUsecase) CreatePrerequisite(prerequisite *model.CoursePrerequisite) (model.CoursePrerequisiteResponse, error) {
+	if prerequisite == nil {
+		return model.CoursePrerequisiteResponse{}, errors.New("prerequisite is nil")
+	}
+	if prerequisite.Type == "" {
+		prerequisite.Type = model.PrerequisiteTypePrerequisite
+	}
+	if prerequisite.Type != model.PrerequisiteTypePrerequisite && prerequisite.Type != model.PrerequisiteTypeCorequisite {
+		return model.CoursePrerequisiteResponse{}, errors.New("type must be prerequisite or corequisite")
+	}
+	if prerequisite.CatalogCourseID == prerequisite.RequiredCourseID {
+		return model.CoursePrerequisiteResponse{}, errors.New("a course cannot require itself")
+	}
+
+	var courses []model.CatalogCourse
+	if err := pu.cr.GetCatalogCoursesByIDs(&courses, []uint{prerequisite.CatalogCourseID, prerequisite.RequiredCourseID}); err != nil {
+		return model.CoursePrerequisiteResponse{}, err
+	}
+	if len(courses) != 2 {
+		return model.CoursePrerequisiteResponse{}, errors.New("catalog course does not exist")
+	}
+	if courses[0].UniversityID != courses[1].UniversityID {
+		return model.CoursePrerequisiteResponse{}, errors.New("courses must belong to the same university")
+	}
+
+	var edges []model.CoursePrerequisite
+	if err := pu.prr.GetPrerequisitesByUniversityID(&edges, courses[0].UniversityID); err != nil {
+		return model.CoursePrerequisiteResponse{}, err
+	}
+	if createsPrerequisiteCycle(edges, *prerequisite) {
+		return model.CoursePrerequisiteResponse{}, errors.New("prerequisite creates a cycle")
+	}
+
+	if err := pu.prr.CreatePrerequisite(prerequisite); err != nil {
+		return model.CoursePrerequisiteResponse{}, err
+	}
+	return toCoursePrerequisiteResponse(*prerequisite), nil
+}
+
+func (pu *prerequisiteUsecase) DeletePrerequisiteByID(prerequisiteId uint) error {
+	return pu.prr.DeletePrerequisiteByID(prerequisiteId)
+}
+
+// 計画内の科目について、必要な科目が計画にないもの・後の学期に置かれているものを返す
+func (pu *prerequisiteUsecase) CheckPlan(userId uint, planId uint) (model.PrerequisiteCheckResponse, error) {
+	var plan model.Plan
+	if err := getVisiblePlan(pu.pr, &plan, planId, userId); err != nil {
+		return model.PrerequisiteCheckResponse{}, err
+	}
+
+	byCatalogId := map[uint]model.Course{}
+	ids := []uint{}
+	for _, course := range plan.Courses {
+		if course.CatalogCourseID == nil {
+			continue
+		}
+		if _, ok := byCatalogId[*course.CatalogCourseID]; !ok {
+			ids = append(ids, *course.CatalogCourseID)
+		}
+		byCatalogId[*course.CatalogCourseID] = course
+	}
+
+	var prerequisites []model.CoursePrerequisite
+	if err := pu.prr.GetPrerequisitesByCourseIDs(&prerequisites, ids); err != nil {
+		return model.PrerequisiteCheckResponse{}, err
+	}
+
+	res := model.PrerequisiteCheckResponse{
+		PlanID: plan.ID,
+		Valid:  true,
+		Issues: []model.PrerequisiteIssueResponse{},
+	}
+	for _, p := range prerequisites {
+		course := byCatalogId[p.CatalogCourseID]
+		issue := model.PrerequisiteIssueResponse{
+			CourseID:           course.ID,
+			CourseName:         course.Name,
+			RequiredCourseID:   p.RequiredCourseID,
+			RequiredCourseName: p.RequiredCourse.Name,
+			Type:               p.Type,
+		}
+
+		required, ok := byCatalogId[p.RequiredCourseID]
+		if !ok {
+			issue.Issue = model.PrerequisiteIssueMissing
+		} else if course.PlanTerm != nil && required.PlanTerm != nil {
+			if p.Type == model.PrerequisiteTypePrerequisite && required.PlanTerm.Sequence >= course.PlanTerm.Sequence ||
+				p.Type == model.PrerequisiteTypeCorequisite && required.PlanTerm.Sequence > course.PlanTerm.Sequence {
+				issue.Issue = model.PrerequisiteIssueOrder
+			}
+		}
+		if issue.Issue != "" {
+			res.Valid = false
+			res.Issues = append(res.Issues, issue)
+		}
+	}
+	return res, nil
+}
+
+// 新しい辺を加えたとき、先修条件を1本以上含む閉路ができるかを調べる。
+// 併修条件のみの閉路は同じ学期に履修すれば満たせるため許容する
+func createsPrerequisiteCycle(edges []model.CoursePrerequisite, next model.CoursePrerequisite) bool {
+	// 依存される側から依存する側への隣接リスト
+	type edge struct {
+		to     uint
+		strict bool
+	}
+	adjacent := map[uint][]edge{}
+	for _, e := range edges {
+		adjacent[e.RequiredCourseID] = append(adjacent[e.RequiredCourseID], edge{e.CatalogCourseID, e.Type == model.PrerequisiteTypePrerequisite})
+	}
+
+	// next.CatalogCourseID から next.RequiredCourseID へ到達できれば閉路になる
+	type state struct {
+		node   uint
+		strict bool
+	}
+	start := state{next.CatalogCourseID, next.Type == model.PrerequisiteTypePrerequisite}
+	visited := map[state]bool{start: true}
+	stack := []state{start}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current.node == next.RequiredCourseID && current.strict {
+			return true
+		}
+		for _, e := range adjacent[current.node] {
+			s := state{e.to, current.strict || e.strict}
+			if !visited[s] {
+				visited[s] = true
+				stack = append(stack, s)
+			}
+		}
+	}
+	return false
+}
+
+func toCoursePrerequisiteResponse(p model.CoursePrerequisite) model.CoursePrerequisiteResponse {
+	return model.CoursePrerequisiteResponse{
+		ID:               p.ID,
+		CatalogCourseID:  p.CatalogCourseID,
+		RequiredCourseID: p.RequiredCourseID,
+		Type:             p.Type,
+	}
+}
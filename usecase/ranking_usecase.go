@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"errors"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IRankingUsecase interface {
+	RecomputeScores() error
+	GetTrendingPlans(userId uint, universityId *uint, departmentId *uint, offset int, limit int) (model.TrendingPlansResponse, error)
+}
+
+type rankingUsecase struct {
+	rr repository.IRankingRepository
+	pr repository.IPlanRepository
+}
+
+func NewRankingUsecase(rr repository.IRankingRepository, pr repository.IPlanRepository) IRankingUsecase {
+	return &rankingUsecase{rr: rr, pr: pr}
+}
+
+// 反応の種類ごとの重み。コピーは計画を参考にした度合いが最も強いとみなす
+const (
+	favoriteWeight = 3.0
+	commentWeight  = 1.0
+	forkWeight     = 5.0
+)
+
+// 反応の重みはこの期間ごとに半分になる
+const scoreHalfLife = 7 * 24 * time.Hour
+
+// 全ての公開計画のスコアを再計算し、週が変わっていれば週始めのスナップショットを保存する
+func (ru *rankingUsecase) RecomputeScores() error {
+	now := time.Now()
+	scores := map[uint]*model.PlanScore{}
+	score := func(planId uint) *model.PlanScore {
+		s, ok := scores[planId]
+		if !ok {
+			s = &model.PlanScore{PlanID: planId, ComputedAt: now}
+			scores[planId] = s
+		}
+		return s
+	}
+	decay := func(createdAt time.Time) float64 {
+		age := now.Sub(createdAt)
+		if age < 0 {
+			age = 0
+		}
+		return math.Pow(0.5, float64(age)/float64(scoreHalfLife))
+	}
+
+	var favorites, comments, forks []model.PlanActivity
+	if err := ru.rr.GetFavoriteActivities(&favorites); err != nil {
+		return err
+	}
+	if err := ru.rr.GetCommentActivities(&comments); err != nil {
+		return err
+	}
+	if err := ru.rr.GetForkActivities(&forks); err != nil {
+		return err
+	}
+	for _, a := range favorites {
+		s := score(a.PlanID)
+		s.FavoriteCount++
+		s.Score += favoriteWeight * decay(a.CreatedAt)
+	}
+	for _, a := range comments {
+		s := score(a.PlanID)
+		s.CommentCount++
+		s.Score += commentWeight * decay(a.CreatedAt)
+	}
+	for _, a := range forks {
+		s := score(a.PlanID)
+		s.ForkCount++
+		s.Score += forkWeight * decay(a.CreatedAt)
+	}
+
+	planScores := make([]model.PlanScore, 0, len(scores))
+	for _, s := range scores {
+		planScores = append(planScores, *s)
+	}
+	if err := ru.rr.ReplacePlanScores(&planScores); err != nil {
+		return err
+	}
+
+	week := weekStart(now)
+	exists, err := ru.rr.ExistsSnapshot(week)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	return ru.rr.CreateSnapshot(week)
+}
+
+// 順位の変動は直近のスナップショットの同じ範囲内の順位と比べる
+func (ru *rankingUsecase) GetTrendingPlans(userId uint, universityId *uint, departmentId *uint, offset int, limit int) (model.TrendingPlansResponse, error) {
+	var scores []model.PlanScore
+	if err := ru.rr.GetTrendingScores(&scores, universityId, departmentId, offset, limit); err != nil {
+		return model.TrendingPlansResponse{}, err
+	}
+	planIds := make([]uint, 0, len(scores))
+	for _, s := range scores {
+		planIds = append(planIds, s.PlanID)
+	}
+	var plans []model.Plan
+	if len(planIds) > 0 {
+		if err := ru.rr.GetPlansByIDs(&plans, planIds); err != nil {
+			return model.TrendingPlansResponse{}, err
+		}
+	}
+	resPlans, err := toPlanResponses(ru.pr, plans, userId)
+	if err != nil {
+		return model.TrendingPlansResponse{}, err
+	}
+	plansById := make(map[uint]model.PlanResponse, len(resPlans))
+	for _, plan := range resPlans {
+		plansById[plan.ID] = plan
+	}
+
+	res := model.TrendingPlansResponse{Plans: make([]model.TrendingPlanResponse, 0, len(scores))}
+	previousRanks := map[uint]int{}
+	var week time.Time
+	err = ru.rr.GetLatestSnapshotWeek(&week)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.TrendingPlansResponse{}, err
+	}
+	if err == nil {
+		res.WeekStart = &week
+		if previousRanks, err = ru.rr.GetSnapshotRanks(week, universityId, departmentId, planIds); err != nil {
+			return model.TrendingPlansResponse{}, err
+		}
+	}
+
+	for i, s := range scores {
+		plan, ok := plansById[s.PlanID]
+		if !ok {
+			continue
+		}
+		trending := model.TrendingPlanResponse{
+			Rank:          offset + i + 1,
+			Score:         math.Round(s.Score*1000) / 1000,
+			FavoriteCount: s.FavoriteCount,
+			CommentCount:  s.CommentCount,
+			ForkCount:     s.ForkCount,
+			Plan:          plan,
+		}
+		if previous, ok := previousRanks[s.PlanID]; ok {
+			change := previous - trending.Rank
+			trending.PreviousRank = &previous
+			trending.RankChange = &change
+		}
+		res.Plans = append(res.Plans, trending)
+	}
+	return res, nil
+}
+
+// その週の月曜日0時
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"errors"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+type IReactionUsecase interface {
+	AddCommentReaction(userId uint, commentId uint, emoji string) ([]model.ReactionSummary, error)
+	RemoveCommentReaction(userId uint, commentId uint, emoji string) ([]model.ReactionSummary, error)
+	AddPostReaction(userId uint, postId uint, emoji string) ([]model.ReactionSummary, error)
+	RemovePostReaction(userId uint, postId uint, emoji string) ([]model.ReactionSummary, error)
+}
+
+type reactionUsecase struct {
+	rr     repository.IReactionRepository
+	cr     repository.ICommentRepository
+	por    repository.IPostRepository
+	pr     repository.IPlanRepository
+	policy ReactionPolicy
+}
+
+func NewReactionUsecase(
+	rr repository.IReactionRepository,
+	cr repository.ICommentRepository,
+	por repository.IPostRepository,
+	pr repository.IPlanRepository,
+	policy ReactionPolicy,
+) IReactionUsecase {
+	return &reactionUsecase{rr: rr, cr: cr, por: por, pr: pr, policy: policy}
+}
+
+// リアクションに使える絵文字
+type ReactionPolicy struct {
+	Emojis []string
+}
+
+var defaultReactionEmojis = []string{"👍", "🙏", "💡", "🎉", "👀"}
+
+// 環境変数 REACTION_EMOJIS (カンマ区切り) から読み込む
+func NewReactionPolicy() ReactionPolicy {
+	policy := ReactionPolicy{Emojis: defaultReactionEmojis}
+	if v := os.Getenv("REACTION_EMOJIS"); v != "" {
+		emojis := []string{}
+		for _, emoji := range strings.Split(v, ",") {
+			if emoji = strings.TrimSpace(emoji); emoji != "" {
+				emojis = append(emojis, emoji)
+			}
+		}
+		if len(emojis) == 0 {
+			log.Printf("invalid REACTION_EMOJIS %q, using defaults", v)
+			return policy
+		}
+		policy.Emojis = emojis
+	}
+	return policy
+}
+
+func (p ReactionPolicy) allows(emoji string) bool {
+	for _, e := range p.Emojis {
+		if e == emoji {
+			return true
+		}
+	}
+	return false
+}
+
+// 使えない絵文字を指定した場合に返す
+var ErrUnknownReaction = errors.New("unknown reaction")
+
+// 公開中のコメントで、計画を閲覧できる場合のみリアクションできる
+func (ru *reactionUsecase) AddCommentReaction(userId uint, commentId uint, emoji string) ([]model.ReactionSummary, error) {
+	if !ru.policy.allows(emoji) {
+		return nil, ErrUnknownReaction
+	}
+	var comment model.Comment
+	if err := ru.cr.GetCommentByID(&comment, commentId); err != nil {
+		return nil, err
+	}
+	var plan model.Plan
+	if err := getVisiblePlan(ru.pr, &plan, comment.PlanID, userId); err != nil {
+		return nil, err
+	}
+	if comment.Status != model.CommentStatusVisible {
+		return nil, ErrForbidden
+	}
+	if err := ru.rr.AddCommentReaction(userId, commentId, emoji); err != nil {
+		return nil, err
+	}
+	return ru.commentReactions(userId, commentId)
+}
+
+// 設定から外れた絵文字のリアクションも取り消せるよう、絵文字は確認しない
+func (ru *reactionUsecase) RemoveCommentReaction(userId uint, commentId uint, emoji string) ([]model.ReactionSummary, error) {
+	var comment model.Comment
+	if err := ru.cr.GetCommentByID(&comment, commentId); err != nil {
+		return nil, err
+	}
+	if err := ru.rr.RemoveCommentReaction(userId, commentId, emoji); err != nil {
+		return nil, err
+	}
+	return ru.commentReactions(userId, commentId)
+}
+
+func (ru *reactionUsecase) commentReactions(userId uint, commentId uint) ([]model.ReactionSummary, error) {
+	reactions := []model.Reaction{}
+	if err := ru.rr.GetCommentReactions(&reactions, commentId); err != nil {
+		return nil, err
+	}
+	return toReactionSummaries(reactions, userId), nil
+}
+
+func (ru *reactionUsecase) AddPostReaction(userId uint, postId uint, emoji string) ([]model.ReactionSummary, error) {
+	if !ru.policy.allows(emoji) {
+		return nil, ErrUnknownReaction
+	}
+	var post model.Post
+	if err := ru.por.GetPost(&post, postId); err != nil {
+		return nil, err
+	}
+	if post.PlanID != nil {
+		var plan model.Plan
+		if err := getVisiblePlan(ru.pr, &plan, *post.PlanID, userId); err != nil {
+			return nil, err
+		}
+	}
+	if err := ru.rr.AddPostReaction(userId, postId, emoji); err != nil {
+		return nil, err
+	}
+	return ru.postReactions(userId, postId)
+}
+
+func (ru *reactionUsecase) RemovePostReaction(userId uint, postId uint, emoji string) ([]model.ReactionSummary, error) {
+	var post model.Post
+	if err := ru.por.GetPost(&post, postId); err != nil {
+		return nil, err
+	}
+	if err := ru.rr.RemovePostReaction(userId, postId, emoji); err != nil {
+		return nil, err
+	}
+	return ru.postReactions(userId, postId)
+}
+
+func (ru *reactionUsecase) postReactions(userId uint, postId uint) ([]model.ReactionSummary, error) {
+	reactions := []model.Reaction{}
+	if err := ru.rr.GetPostReactions(&reactions, postId); err != nil {
+		return nil, err
+	}
+	return toReactionSummaries(reactions, userId), nil
+}
+
+// 絵文字ごとに集計し、最初にリアクションされた順に並べる
+func toReactionSummaries(reactions []model.Reaction, userId uint) []model.ReactionSummary {
+	sorted := make([]model.Reaction, len(reactions))
+	copy(sorted, reactions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	summaries := []model.ReactionSummary{}
+	index := map[string]int{}
+	for _, reaction := range sorted {
+		i, ok := index[reaction.Emoji]
+		if !ok {
+			i = len(summaries)
+			index[reaction.Emoji] = i
+			summaries = append(summaries, model.ReactionSummary{Emoji: reaction.Emoji})
+		}
+		summaries[i].Count++
+		if userId != 0 && reaction.UserID == userId {
+			summaries[i].ReactedByMe = true
+		}
+	}
+	return summaries
+}
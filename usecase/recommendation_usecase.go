@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+type IRecommendationUsecase interface {
+	RecomputeCooccurrences() error
+	GetPlanRecommendations(userId uint, planId uint, limit int) ([]model.RecommendedCourseResponse, error)
+	GetCourseRecommendations(userId uint, catalogCourseId uint, departmentId *uint, limit int) ([]model.RecommendedCourseResponse, error)
+}
+
+type recommendationUsecase struct {
+	rr repository.IRecommendationRepository
+	pr repository.IPlanRepository
+	ur repository.IUserRepository
+}
+
+func NewRecommendationUsecase(rr repository.IRecommendationRepository, pr repository.IPlanRepository, ur repository.IUserRepository) IRecommendationUsecase {
+	return &recommendationUsecase{rr: rr, pr: pr, ur: ur}
+}
+
+// この数より少ない計画でしか一緒に履修されていない組は偶然とみなして推薦しない
+const minCooccurrencePlans = 2
+
+// 学科ごとに、科目Aを含む計画のうち科目Bも含む割合を求める。
+// 計画はお気に入りの数だけ重みを増やし、多くの人が参考にしている計画の組み合わせを優先する
+func (ru *recommendationUsecase) RecomputeCooccurrences() error {
+	var plans []model.Plan
+	if err := ru.rr.GetPlansForCooccurrence(&plans); err != nil {
+		return err
+	}
+
+	type pairKey struct {
+		departmentId uint
+		course       string
+		paired       string
+	}
+	type courseInfo struct {
+		name            string
+		catalogCourseId *uint
+	}
+	courseWeights := map[pairKey]float64{} // pairedは空
+	pairWeights := map[pairKey]float64{}
+	pairCounts := map[pairKey]int{}
+	infos := map[string]courseInfo{}
+
+	for _, plan := range plans {
+		if plan.User.DepartmentID == nil {
+			continue
+		}
+		departmentId := *plan.User.DepartmentID
+		weight := 1 + float64(len(plan.Favorites))
+
+		keys := []string{}
+		seen := map[string]bool{}
+		for _, course := range plan.Courses {
+			key := courseKey(course)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			keys = append(keys, key)
+			infos[key] = courseInfo{name: course.Name, catalogCourseId: course.CatalogCourseID}
+		}
+		for _, a := range keys {
+			courseWeights[pairKey{departmentId, a, ""}] += weight
+			for _, b := range keys {
+				if a == b {
+					continue
+				}
+				pairWeights[pairKey{departmentId, a, b}] += weight
+				pairCounts[pairKey{departmentId, a, b}]++
+			}
+		}
+	}
+
+	now := time.Now()
+	cooccurrences := []model.CourseCooccurrence{}
+	for key, weight := range pairWeights {
+		if pairCounts[key] < minCooccurrencePlans {
+			continue
+		}
+		info := infos[key.paired]
+		cooccurrences = append(cooccurrences, model.CourseCooccurrence{
+			DepartmentID:          key.departmentId,
+			CourseKey:             key.course,
+			PairedKey:             key.paired,
+			PairedName:            info.name,
+			PairedCatalogCourseID: info.catalogCourseId,
+			PlanCount:             pairCounts[key],
+			Score:                 weight / courseWeights[pairKey{key.departmentId, key.course, ""}],
+			ComputedAt:            now,
+		})
+	}
+	return ru.rr.ReplaceCooccurrences(&cooccurrences)
+}
+
+// 計画の科目と一緒に履修されることが多い、計画にまだない科目を返す
+func (ru *recommendationUsecase) GetPlanRecommendations(userId uint, planId uint, limit int) ([]model.RecommendedCourseResponse, error) {
+	var plan model.Plan
+	if err := getVisiblePlan(ru.pr, &plan, planId, userId); err != nil {
+		return nil, err
+	}
+	if plan.User.DepartmentID == nil {
+		return []model.RecommendedCourseResponse{}, nil
+	}
+
+	keys := make([]string, 0, len(plan.Courses))
+	names := map[string]string{}
+	for _, course := range plan.Courses {
+		key := courseKey(course)
+		if _, ok := names[key]; !ok {
+			keys = append(keys, key)
+		}
+		names[key] = course.Name
+	}
+	var cooccurrences []model.CourseCooccurrence
+	if err := ru.rr.GetCooccurrences(&cooccurrences, *plan.User.DepartmentID, keys); err != nil {
+		return nil, err
+	}
+
+	// 複数の科目から推薦される科目は、それぞれのスコアを平均して順位を付ける
+	byKey := map[string]*model.RecommendedCourseResponse{}
+	order := []string{}
+	for _, c := range cooccurrences {
+		if _, ok := names[c.PairedKey]; ok {
+			continue
+		}
+		res, ok := byKey[c.PairedKey]
+		if !ok {
+			res = &model.RecommendedCourseResponse{Name: c.PairedName, CatalogCourseID: c.PairedCatalogCourseID}
+			byKey[c.PairedKey] = res
+			order = append(order, c.PairedKey)
+		}
+		res.Score += c.Score / float64(len(keys))
+		if c.PlanCount > res.PlanCount {
+			res.PlanCount = c.PlanCount
+		}
+		res.PairedWith = append(res.PairedWith, names[c.CourseKey])
+	}
+
+	recommendations := make([]model.RecommendedCourseResponse, 0, len(order))
+	for _, key := range order {
+		recommendations = append(recommendations, *byKey[key])
+	}
+	return topRecommendations(recommendations, limit), nil
+}
+
+// 学科を指定しない場合は閲覧しているユーザーの学科を使う
+func (ru *recommendationUsecase) GetCourseRecommendations(userId uint, catalogCourseId uint, departmentId *uint, limit int) ([]model.RecommendedCourseResponse, error) {
+	if departmentId == nil {
+		var user model.User
+		if err := ru.ur.GetUserByID(&user, userId); err != nil {
+			return nil, err
+		}
+		if user.DepartmentID == nil {
+			return []model.RecommendedCourseResponse{}, nil
+		}
+		departmentId = user.DepartmentID
+	}
+
+	key := courseKey(model.Course{CatalogCourseID: &catalogCourseId})
+	var cooccurrences []model.CourseCooccurrence
+	if err := ru.rr.GetCooccurrences(&cooccurrences, *departmentId, []string{key}); err != nil {
+		return nil, err
+	}
+	recommendations := make([]model.RecommendedCourseResponse, 0, len(cooccurrences))
+	for _, c := range cooccurrences {
+		recommendations = append(recommendations, model.RecommendedCourseResponse{
+			Name:            c.PairedName,
+			CatalogCourseID: c.PairedCatalogCourseID,
+			Score:           c.Score,
+			PlanCount:       c.PlanCount,
+			PairedWith:      []string{},
+		})
+	}
+	return topRecommendations(recommendations, limit), nil
+}
+
+// カタログの科目はIDで、自由入力の科目は正規化した名前で識別する
+func courseKey(course model.Course) string {
+	if course.CatalogCourseID != nil {
+		return fmt.Sprintf("catalog:%d", *course.CatalogCourseID)
+	}
+	return "name:" + normalizeText(course.Name)
+}
+
+func topRecommendations(recommendations []model.RecommendedCourseResponse, limit int) []model.RecommendedCourseResponse {
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score > recommendations[j].Score
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	for i := range recommendations {
+		recommendations[i].Score = math.Round(recommendations[i].Score*1000) / 1000
+	}
+	return recommendations
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"backend/validator"
+	"errors"
+)
+
+type IRequirementUsecase interface {
+	GetRequirementSets(departmentId uint) ([]model.RequirementSetResponse, error)
+	GetRequirementSetByID(requirementId uint) (model.RequirementSetResponse, error)
+	CreateRequirementSet(set *model.RequirementSet) (model.RequirementSetResponse, error)
+	UpdateRequirementSet(set *model.RequirementSet, requirementId uint) (model.RequirementSetResponse, error)
+	DeleteRequirementSetByID(requirementId uint) error
+	EvaluatePlan(userId uint, planId uint, departmentId *uint, entryYear *uint) (model.RequirementEvaluationResponse, error)
+}
+
+type requirementUsecase struct {
+	rr repository.IRequirementRepository
+	pr repository.IPlanRepository
+	rv validator.IRequirementValidator
+}
+
+func NewRequirementUsecase(
+	rr repository.IRequirementRepository, pr repository.IPlanRepository, rv validator.IRequirementValidator) IRequirementUsecase {
+	return &requirementUsecase{rr: rr, pr: pr, rv: rv}
+}
+
+func (ru *requirementUsecase) GetRequirementSets(departmentId uint) ([]model.RequirementSetResponse, error) {
+	var sets []model.RequirementSet
+	if err := ru.rr.GetRequirementSets(&sets, departmentId); err != nil {
+		return nil, err
+	}
+	resSets := make([]model.RequirementSetResponse, 0, len(sets))
+	for _, set := range sets {
+		resSets = append(resSets, toRequirementSetResponse(set))
+	}
+	return resSets, nil
+}
+
+func (ru *requirementUsecase) GetRequirementSetByID(requirementId uint) (model.RequirementSetResponse, error) {
+	var set model.RequirementSet
+	if err := ru.rr.GetRequirementSetByID(&set, requirementId); err != nil {
+		return model.RequirementSetResponse{}, err
+	}
+	return toRequirementSetResponse(set), nil
+}
+
+func (ru *requirementUsecase) CreateRequirementSet(set *model.RequirementSet) (model.RequirementSetResponse, error) {
+	if set == nil {
+		return model.RequirementSetResponse{}, errors.New("requirement set is nil")
+	}
+	if err := ru.rv.RequirementSetValidate(*set); err != nil {
+		return model.RequirementSetResponse{}, err
+	}
+	if err := ru.rr.CreateRequirementSet(set); err != nil {
+		return model.RequirementSetResponse{}, err
+	}
+	return toRequirementSetResponse(*set), nil
+}
+
+func (ru *requirementUsecase) UpdateRequirementSet(set *model.RequirementSet, requirementId uint) (model.RequirementSetResponse, error) {
+	if set == nil {
+		return model.RequirementSetResponse{}, errors.New("requirement set is nil")
+	}
+	if err := ru.rv.RequirementSetValidate(*set); err != nil {
+		return model.RequirementSetResponse{}, err
+	}
+	if err := ru.rr.UpdateRequirementSet(set, requirementId); err != nil {
+		return model.RequirementSetResponse{}, err
+	}
+	return toRequirementSetResponse(*set), nil
+}
+
+func (ru *requirementUsecase) DeleteRequirementSetByID(requirementId uint) error {
+	return ru.rr.DeleteRequirementSetByID(requirementId)
+}
+
+// 学科・入学年度が指定されない場合は計画作成者のプロフィールを使う
+func (ru *requirementUsecase) EvaluatePlan(userId uint, planId uint, departmentId *uint, entryYear *uint) (model.RequirementEvaluationResponse, error) {
+	var plan model.Plan
+	if err := getVisiblePlan(ru.pr, &plan, planId, userId); err != nil {
+		return model.RequirementEvaluationResponse{}, err
+	}
+	if departmentId == nil {
+		departmentId = plan.User.DepartmentID
+	}
+	if entryYear == nil {
+		entryYear = plan.User.EntryYear
+	}
+	if departmentId == nil || entryYear == nil {
+		return model.RequirementEvaluationResponse{}, errors.New("department and entry year are required to evaluate requirements")
+	}
+
+	var set model.RequirementSet
+	if err := ru.rr.GetApplicableRequirementSet(&set, *departmentId, *entryYear); err != nil {
+		return model.RequirementEvaluationResponse{}, err
+	}
+	return evaluateRequirements(plan, set), nil
+}
+
+func evaluateRequirements(plan model.Plan, set model.RequirementSet) model.RequirementEvaluationResponse {
+	taken := make(map[string]bool, len(plan.Courses))
+	takenCatalog := make(map[uint]bool, len(plan.Courses))
+	var totalCredits uint
+	for _, course := range plan.Courses {
+		taken[normalizeText(course.Name)] = true
+		if course.CatalogCourseID != nil {
+			takenCatalog[*course.CatalogCourseID] = true
+		}
+		totalCredits += course.Credits
+	}
+
+	res := model.RequirementEvaluationResponse{
+		PlanID:           plan.ID,
+		RequirementSetID: set.ID,
+		Name:             set.Name,
+		Satisfied:        true,
+		TotalCredits:     totalCredits,
+		SatisfiedRules:   []model.RequirementResultResponse{},
+		MissingRules:     []model.RequirementResultResponse{},
+	}
+
+	for _, rule := range set.Rules {
+		result := model.RequirementResultResponse{
+			RuleID:         rule.ID,
+			Type:           rule.Type,
+			Name:           rule.Name,
+			Category:       rule.Category,
+			MatchedCourses: []string{},
+			MissingCourses: []string{},
+		}
+		var satisfied bool
+
+		switch rule.Type {
+		case model.RequirementTypeMinCredits:
+			var earned uint
+			for _, course := range plan.Courses {
+				if rule.Category != nil && (course.Category == nil || *course.Category != *rule.Category) {
+					continue
+				}
+				earned += course.Credits
+				result.MatchedCourses = append(result.MatchedCourses, course.Name)
+			}
+			result.RequiredCredits = rule.MinCredits
+			result.EarnedCredits = earned
+			satisfied = earned >= rule.MinCredits
+		case model.RequirementTypeMandatory, model.RequirementTypeChooseN:
+			for _, course := range rule.Courses {
+				var matched bool
+				if course.CatalogCourseID != nil {
+					matched = takenCatalog[*course.CatalogCourseID]
+				} else {
+					matched = taken[normalizeText(course.CourseName)]
+				}
+				if matched {
+					result.MatchedCourses = append(result.MatchedCourses, course.CourseName)
+				} else {
+					result.MissingCourses = append(result.MissingCourses, course.CourseName)
+				}
+			}
+			if rule.Type == model.RequirementTypeMandatory {
+				satisfied = len(result.MissingCourses) == 0
+			} else {
+				result.RequiredCount = rule.MinCount
+				satisfied = uint(len(result.MatchedCourses)) >= rule.MinCount
+			}
+		}
+
+		if satisfied {
+			res.SatisfiedRules = append(res.SatisfiedRules, result)
+		} else {
+			res.Satisfied = false
+			res.MissingRules = append(res.MissingRules, result)
+		}
+	}
+	return res
+}
+
+func toRequirementSetResponse(set model.RequirementSet) model.RequirementSetResponse {
+	rules := make([]model.RequirementRuleResponse, 0, len(set.Rules))
+	for _, rule := range set.Rules {
+		courses := make([]string, 0, len(rule.Courses))
+		for _, course := range rule.Courses {
+			courses = append(courses, course.CourseName)
+		}
+		rules = append(rules, model.RequirementRuleResponse{
+			ID:         rule.ID,
+			Type:       rule.Type,
+			Name:       rule.Name,
+			Category:   rule.Category,
+			MinCredits: rule.MinCredits,
+			MinCount:   rule.MinCount,
+			Courses:    courses,
+		})
+	}
+	return model.RequirementSetResponse{
+		ID:           set.ID,
+		Name:         set.Name,
+		DepartmentID: set.DepartmentID,
+		EntryYear:    set.EntryYear,
+		Rules:        rules,
+		CreatedAt:    set.CreatedAt,
+		UpdatedAt:    set.UpdatedAt,
+	}
+}
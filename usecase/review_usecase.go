@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"backend/validator"
+	"errors"
+	"fmt"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+type IReviewUsecase interface {
+	GetReviewsByCatalogCourseID(catalogCourseId uint, sort string, offset int, limit int) ([]model.CourseReviewResponse, error)
+	GetReviewSummary(catalogCourseId uint) (model.CourseReviewSummaryResponse, error)
+	GetReviewsByUserID(userId uint) ([]model.CourseReviewResponse, error)
+	CreateReview(review *model.CourseReview) (model.CourseReviewResponse, error)
+	UpdateReview(userId uint, reviewId uint, review *model.CourseReview) (model.CourseReviewResponse, error)
+	DeleteReviewByID(userId uint, reviewId uint) error
+}
+
+type reviewUsecase struct {
+	rr  repository.IReviewRepository
+	ctr repository.ICatalogRepository
+	mr  repository.IModerationRepository
+	rv  validator.IReviewValidator
+	m   IModerator
+}
+
+func NewReviewUsecase(rr repository.IReviewRepository, ctr repository.ICatalogRepository, mr repository.IModerationRepository, rv validator.IReviewValidator, m IModerator) IReviewUsecase {
+	return &reviewUsecase{rr: rr, ctr: ctr, mr: mr, rv: rv, m: m}
+}
+
+// 並び順の指定とSQLの対応。同じ値の場合は新しい順
+var reviewOrders = map[string]string{
+	"newest":          "created_at desc",
+	"oldest":          "created_at asc",
+	"rating_desc":     "rating desc, created_at desc",
+	"rating_asc":      "rating asc, created_at desc",
+	"difficulty_desc": "difficulty desc, created_at desc",
+	"difficulty_asc":  "difficulty asc, created_at desc",
+	"workload_desc":   "workload desc, created_at desc",
+	"workload_asc":    "workload asc, created_at desc",
+}
+
+func (ru *reviewUsecase) GetReviewsByCatalogCourseID(catalogCourseId uint, sort string, offset int, limit int) ([]model.CourseReviewResponse, error) {
+	if sort == "" {
+		sort = "newest"
+	}
+	order, ok := reviewOrders[sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort: %s", sort)
+	}
+	var reviews []model.CourseReview
+	if err := ru.rr.GetReviewsByCatalogCourseID(&reviews, catalogCourseId, order, offset, limit); err != nil {
+		return nil, err
+	}
+	res := make([]model.CourseReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		res = append(res, toCourseReviewResponse(review))
+	}
+	return res, nil
+}
+
+func (ru *reviewUsecase) GetReviewSummary(catalogCourseId uint) (model.CourseReviewSummaryResponse, error) {
+	var summary model.CourseReviewSummary
+	if err := ru.rr.GetReviewSummary(&summary, catalogCourseId); err != nil {
+		return model.CourseReviewSummaryResponse{}, err
+	}
+	return model.CourseReviewSummaryResponse{
+		CatalogCourseID:             catalogCourseId,
+		ReviewCount:                 summary.ReviewCount,
+		AverageRating:               roundScore(summary.AverageRating),
+		AverageDifficulty:           roundScore(summary.AverageDifficulty),
+		AverageWorkload:             roundScore(summary.AverageWorkload),
+		AverageAttendanceStrictness: roundScore(summary.AverageAttendanceStrictness),
+	}, nil
+}
+
+func (ru *reviewUsecase) GetReviewsByUserID(userId uint) ([]model.CourseReviewResponse, error) {
+	var reviews []model.CourseReview
+	if err := ru.rr.GetReviewsByUserID(&reviews, userId); err != nil {
+		return nil, err
+	}
+	res := make([]model.CourseReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		res = append(res, toCourseReviewResponse(review))
+	}
+	return res, nil
+}
+
+func (ru *reviewUsecase) CreateReview(review *model.CourseReview) (model.CourseReviewResponse, error) {
+	if review == nil {
+		return model.CourseReviewResponse{}, errors.New("review is nil")
+	}
+	if err := ru.rv.ReviewValidate(*review); err != nil {
+		return model.CourseReviewResponse{}, err
+	}
+	var course model.CatalogCourse
+	if err := ru.ctr.GetCatalogCourseByID(&course, review.CatalogCourseID); err != nil {
+		return model.CourseReviewResponse{}, err
+	}
+	if err := ru.checkDuplicate(*review, 0); err != nil {
+		return model.CourseReviewResponse{}, err
+	}
+	held, err := ru.moderate(*review)
+	if err != nil {
+		return model.CourseReviewResponse{}, err
+	}
+	review.ID = 0
+	review.Status = model.ReviewStatusVisible
+	if held {
+		review.Status = model.ReviewStatusPending
+	}
+	if err := ru.rr.CreateReview(review); err != nil {
+		return model.CourseReviewResponse{}, toReviewSaveError(err)
+	}
+	if held {
+		if err := ru.recordHold(*review); err != nil {
+			return model.CourseReviewResponse{}, err
+		}
+	}
+	return toCourseReviewResponse(*review), nil
+}
+
+// 対象の科目と投稿者は変更できない
+func (ru *reviewUsecase) UpdateReview(userId uint, reviewId uint, review *model.CourseReview) (model.CourseReviewResponse, error) {
+	current, err := ru.ownReview(userId, reviewId)
+	if err != nil {
+		return model.CourseReviewResponse{}, err
+	}
+	review.CatalogCourseID = current.CatalogCourseID
+	review.UserID = current.UserID
+	if err := ru.rv.ReviewValidate(*review); err != nil {
+		return model.CourseReviewResponse{}, err
+	}
+	if err := ru.checkDuplicate(*review, reviewId); err != nil {
+		return model.CourseReviewResponse{}, err
+	}
+	held, err := ru.moderate(*review)
+	if err != nil {
+		return model.CourseReviewResponse{}, err
+	}
+	// 非表示にされたレビューは編集しても非表示のまま
+	held = held && current.Status == model.ReviewStatusVisible
+	review.Status = current.Status
+	if held {
+		review.Status = model.ReviewStatusPending
+	}
+	if err := ru.rr.UpdateReview(review, reviewId); err != nil {
+		return model.CourseReviewResponse{}, toReviewSaveError(err)
+	}
+	if held {
+		if err := ru.recordHold(*review); err != nil {
+			return model.CourseReviewResponse{}, err
+		}
+	}
+	return toCourseReviewResponse(*review), nil
+}
+
+func (ru *reviewUsecase) DeleteReviewByID(userId uint, reviewId uint) error {
+	if _, err := ru.ownReview(userId, reviewId); err != nil {
+		return err
+	}
+	return ru.rr.DeleteReviewByID(reviewId)
+}
+
+func (ru *reviewUsecase) ownReview(userId uint, reviewId uint) (model.CourseReview, error) {
+	var review model.CourseReview
+	if err := ru.rr.GetReviewByID(&review, reviewId); err != nil {
+		return model.CourseReview{}, err
+	}
+	if review.UserID != userId {
+		return model.CourseReview{}, ErrForbidden
+	}
+	return review, nil
+}
+
+func (ru *reviewUsecase) checkDuplicate(review model.CourseReview, excludeId uint) error {
+	exists, err := ru.rr.ExistsReview(review.UserID, review.CatalogCourseID, review.AcademicYear, review.TermKind, excludeId)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("review for this course and term: %w", ErrAlreadyExists)
+	}
+	return nil
+}
+
+// 同時の投稿で一意制約に違反した場合も重複として扱う
+func toReviewSaveError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return fmt.Errorf("review for this course and term: %w", ErrAlreadyExists)
+	}
+	return err
+}
+
+// NGワードに該当した場合は保存はするが確認待ちにする
+func (ru *reviewUsecase) moderate(review model.CourseReview) (bool, error) {
+	if review.Content == "" {
+		return false, nil
+	}
+	userId := review.UserID
+	err := ru.m.Moderate(ModerationTarget{Kind: ModerationKindReview, UserID: &userId, Text: review.Content})
+	if errors.Is(err, ErrContentHeld) {
+		return true, nil
+	}
+	return false, err
+}
+
+func (ru *reviewUsecase) recordHold(review model.CourseReview) error {
+	reviewId := review.ID
+	return ru.mr.CreateAction(&model.ModerationAction{
+		ReviewID: &reviewId,
+		Action:   model.ModerationActionHold,
+		Content:  review.Content,
+	})
+}
+
+// 平均は小数第1位に丸める
+func roundScore(v *float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	rounded := math.Round(*v*10) / 10
+	return &rounded
+}
+
+func toCourseReviewResponse(review model.CourseReview) model.CourseReviewResponse {
+	return model.CourseReviewResponse{
+		ID:                   review.ID,
+		CatalogCourseID:      review.CatalogCourseID,
+		UserID:               review.UserID,
+		AcademicYear:         review.AcademicYear,
+		TermKind:             review.TermKind,
+		Rating:               review.Rating,
+		Difficulty:           review.Difficulty,
+		Workload:             review.Workload,
+		AttendanceStrictness: review.AttendanceStrictness,
+		Content:              review.Content,
+		Status:               review.Status,
+		CreatedAt:            review.CreatedAt,
+		UpdatedAt:            review.UpdatedAt,
+	}
+}
@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"backend/validator"
+	"errors"
+	"fmt"
+)
+
+type ITermUsecase interface {
+	GetAcademicTerms(universityId uint, academicYear uint) ([]model.AcademicTermResponse, error)
+	CreateAcademicTerm(term *model.AcademicTerm) (model.AcademicTermResponse, error)
+	UpdateAcademicTerm(term *model.AcademicTerm, academicTermId uint) (model.AcademicTermResponse, error)
+	DeleteAcademicTermByID(academicTermId uint) error
+	GetPlanTerms(userId uint, planId uint) (model.PlanTermsResponse, error)
+	CreatePlanTerms(userId uint, planId uint, terms []model.PlanTerm) ([]model.PlanTermResponse, error)
+	UpdatePlanTerm(userId uint, planId uint, planTermId uint, term *model.PlanTerm) (model.PlanTermResponse, error)
+	DeletePlanTermByID(userId uint, planId uint, planTermId uint) error
+}
+
+type termUsecase struct {
+	tr repository.ITermRepository
+	pr repository.IPlanRepository
+	tv validator.ITermValidator
+}
+
+func NewTermUsecase(tr repository.ITermRepository, pr repository.IPlanRepository, tv validator.ITermValidator) ITermUsecase {
+	return &termUsecase{tr: tr, pr: pr, tv: tv}
+}
+
+// 学期の種別ごとの並び順。集中講義は同じ学年の後期の後に置く
+var termKindOrder = map[string]int{
+	model.TermKindFirst:     1,
+	model.TermKindSecond:    2,
+	model.TermKindIntensive: 3,
+}
+
+var termKindLabel = map[string]string{
+	model.TermKindFirst:     "前期",
+	model.TermKindSecond:    "後期",
+	model.TermKindIntensive: "集中講義",
+}
+
+func (tu *termUsecase) GetAcademicTerms(universityId uint, academicYear uint) ([]model.AcademicTermResponse, error) {
+	var terms []model.AcademicTerm
+	if err := tu.tr.GetAcademicTerms(&terms, universityId, academicYear); err != nil {
+		return nil, err
+	}
+	resTerms := make([]model.AcademicTermResponse, 0, len(terms))
+	for _, term := range terms {
+		resTerms = append(resTerms, toAcademicTermResponse(term))
+	}
+	return resTerms, nil
+}
+
+func (tu *termUsecase) CreateAcademicTerm(term *model.AcademicTerm) (model.AcademicTermResponse, error) {
+	if term == nil {
+		return model.AcademicTermResponse{}, errors.New("academic term is nil")
+	}
+	if err := tu.tv.AcademicTermValidate(*term); err != nil {
+		return model.AcademicTermResponse{}, err
+	}
+	if err := tu.tr.CreateAcademicTerm(term); err != nil {
+		return model.AcademicTermResponse{}, err
+	}
+	return toAcademicTermResponse(*term), nil
+}
+
+func (tu *termUsecase) UpdateAcademicTerm(term *model.AcademicTerm, academicTermId uint) (model.AcademicTermResponse, error) {
+	if term == nil {
+		return model.AcademicTermResponse{}, errors.New("academic term is nil")
+	}
+	if err := tu.tv.AcademicTermValidate(*term); err != nil {
+		return model.AcademicTermResponse{}, err
+	}
+	if err := tu.tr.UpdateAcademicTerm(term, academicTermId); err != nil {
+		return model.AcademicTermResponse{}, err
+	}
+	return toAcademicTermResponse(*term), nil
+}
+
+func (tu *termUsecase) DeleteAcademicTermByID(academicTermId uint) error {
+	return tu.tr.DeleteAcademicTermByID(academicTermId)
+}
+
+func (tu *termUsecase) GetPlanTerms(userId uint, planId uint) (model.PlanTermsResponse, error) {
+	var plan model.Plan
+	if err := getVisiblePlan(tu.pr, &plan, planId, userId); err != nil {
+		return model.PlanTermsResponse{}, err
+	}
+	return toPlanTermsResponse(plan), nil
+}
+
+func (tu *termUsecase) CreatePlanTerms(userId uint, planId uint, terms []model.PlanTerm) ([]model.PlanTermResponse, error) {
+	if err := tu.checkPlanOwner(userId, planId); err != nil {
+		return nil, err
+	}
+	if len(terms) == 0 {
+		return nil, errors.New("terms is required")
+	}
+	for i := range terms {
+		if err := tu.preparePlanTerm(&terms[i], planId); err != nil {
+			return nil, err
+		}
+	}
+	if err := tu.tr.CreatePlanTerms(&terms); err != nil {
+		return nil, err
+	}
+	resTerms := make([]model.PlanTermResponse, 0, len(terms))
+	for _, term := range terms {
+		resTerms = append(resTerms, toPlanTermResponse(term, []model.Course{}))
+	}
+	return resTerms, nil
+}
+
+func (tu *termUsecase) UpdatePlanTerm(userId uint, planId uint, planTermId uint, term *model.PlanTerm) (model.PlanTermResponse, error) {
+	if term == nil {
+		return model.PlanTermResponse{}, errors.New("plan term is nil")
+	}
+	if err := tu.checkPlanTerm(userId, planId, planTermId); err != nil {
+		return model.PlanTermResponse{}, err
+	}
+	if err := tu.preparePlanTerm(term, planId); err != nil {
+		return model.PlanTermResponse{}, err
+	}
+	if err := tu.tr.UpdatePlanTerm(term, planTermId); err != nil {
+		return model.PlanTermResponse{}, err
+	}
+	return toPlanTermResponse(*term, []model.Course{}), nil
+}
+
+func (tu *termUsecase) DeletePlanTermByID(userId uint, planId uint, planTermId uint) error {
+	if err := tu.checkPlanTerm(userId, planId, planTermId); err != nil {
+		return err
+	}
+	return tu.tr.DeletePlanTermByID(planTermId)
+}
+
+func (tu *termUsecase) preparePlanTerm(term *model.PlanTerm, planId uint) error {
+	if err := tu.tv.PlanTermValidate(*term); err != nil {
+		return err
+	}
+	if term.AcademicTermID != nil {
+		var academicTerm model.AcademicTerm
+		if err := tu.tr.GetAcademicTermByID(&academicTerm, *term.AcademicTermID); err != nil {
+			return fmt.Errorf("academic term %d does not exist", *term.AcademicTermID)
+		}
+		if academicTerm.Kind != term.Kind {
+			return errors.New("academic term kind does not match")
+		}
+		term.AcademicTerm = &academicTerm
+	}
+	term.ID = 0
+	term.PlanID = planId
+	term.Sequence = planTermSequence(term.Grade, term.Kind)
+	return nil
+}
+
+func (tu *termUsecase) checkPlanOwner(userId uint, planId uint) error {
+	var plan model.Plan
+	if err := tu.pr.GetPlanByID(&plan, planId); err != nil {
+		return err
+	}
+	if plan.UserID != userId {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (tu *termUsecase) checkPlanTerm(userId uint, planId uint, planTermId uint) error {
+	if err := tu.checkPlanOwner(userId, planId); err != nil {
+		return err
+	}
+	var current model.PlanTerm
+	if err := tu.tr.GetPlanTermByID(&current, planTermId); err != nil {
+		return err
+	}
+	if current.PlanID != planId {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func planTermSequence(grade uint, kind string) int {
+	return (int(grade)-1)*len(termKindOrder) + termKindOrder[kind]
+}
+
+func planTermName(grade uint, kind string) string {
+	return fmt.Sprintf("%d年%s", grade, termKindLabel[kind])
+}
+
+// 計画の科目を学期ごとにまとめる。学期に割り当てられていない科目はUnassignedに入れる
+func toPlanTermsResponse(plan model.Plan) model.PlanTermsResponse {
+	coursesByTerm := map[uint][]model.Course{}
+	unassigned := []model.CourseResponse{}
+	for _, course := range plan.Courses {
+		if course.PlanTermID == nil {
+			unassigned = append(unassigned, toCourseResponse(course))
+			continue
+		}
+		coursesByTerm[*course.PlanTermID] = append(coursesByTerm[*course.PlanTermID], course)
+	}
+
+	terms := make([]model.PlanTermResponse, 0, len(plan.Terms))
+	for _, term := range plan.Terms {
+		terms = append(terms, toPlanTermResponse(term, coursesByTerm[term.ID]))
+	}
+	return model.PlanTermsResponse{
+		PlanID:     plan.ID,
+		Terms:      terms,
+		Unassigned: unassigned,
+	}
+}
+
+func toPlanTermResponse(term model.PlanTerm, courses []model.Course) model.PlanTermResponse {
+	resCourses := make([]model.CourseResponse, 0, len(courses))
+	var credits uint
+	for _, course := range courses {
+		resCourses = append(resCourses, toCourseResponse(course))
+		credits += course.Credits
+	}
+	res := model.PlanTermResponse{
+		ID:             term.ID,
+		PlanID:         term.PlanID,
+		Grade:          term.Grade,
+		Kind:           term.Kind,
+		Name:           planTermName(term.Grade, term.Kind),
+		Sequence:       term.Sequence,
+		AcademicTermID: term.AcademicTermID,
+		Credits:        credits,
+		Courses:        resCourses,
+	}
+	if term.AcademicTerm != nil {
+		academicTerm := toAcademicTermResponse(*term.AcademicTerm)
+		res.AcademicTerm = &academicTerm
+	}
+	return res
+}
+
+func toAcademicTermResponse(term model.AcademicTerm) model.AcademicTermResponse {
+	return model.AcademicTermResponse{
+		ID:                  term.ID,
+		UniversityID:        term.UniversityID,
+		AcademicYear:        term.AcademicYear,
+		Kind:                term.Kind,
+		Name:                term.Name,
+		StartDate:           term.StartDate,
+		EndDate:             term.EndDate,
+		RegistrationStartAt: term.RegistrationStartAt,
+		RegistrationEndAt:   term.RegistrationEndAt,
+	}
+}
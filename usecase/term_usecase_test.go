@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"backend/model"
+	"testing"
+)
+
+func TestPlanTermSequence(t *testing.T) {
+	tests := []struct {
+		grade    uint
+		kind     string
+		want     int
+		wantName string
+	}{
+		{1, model.TermKindFirst, 1, "1年前期"},
+		{1, model.TermKindSecond, 2, "1年後期"},
+		{1, model.TermKindIntensive, 3, "1年集中講義"},
+		{2, model.TermKindFirst, 4, "2年前期"}, // 1年の集中講義の次
+		{4, model.TermKindSecond, 11, "4年後期"},
+		{6, model.TermKindIntensive, 18, "6年集中講義"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantName, func(t *testing.T) {
+			if got := planTermSequence(tt.grade, tt.kind); got != tt.want {
+				t.Fatalf("planTermSequence(%d, %q) = %d, want %d", tt.grade, tt.kind, got, tt.want)
+			}
+			if got := planTermName(tt.grade, tt.kind); got != tt.wantName {
+				t.Fatalf("planTermName(%d, %q) = %q, want %q", tt.grade, tt.kind, got, tt.wantName)
+			}
+		})
+	}
+}
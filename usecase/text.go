@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"strings"
+
+	"golang.org/x/text/width"
+)
+
+// 全角・半角、大文字・小文字と前後の空白の違いを吸収する。
+// 科目名の比較、メンションのハンドル、NGワードの照合で共通に使う
+func normalizeText(text string) string {
+	return strings.ToLower(strings.TrimSpace(width.Fold.String(text)))
+}
@@ -0,0 +1,27 @@
+package usecase
+
+import "testing"
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"全角英数字は半角にする", "Ｔａｒｏ１２３", "taro123"},
+		{"大文字は小文字にする", "Linear Algebra", "linear algebra"},
+		{"前後の空白を除く", "  線形代数 \n", "線形代数"},
+		{"全角の空白も除く", "　線形代数　", "線形代数"},
+		{"半角カナは全角にする", "ｶﾀｶﾅ", "カタカナ"},
+		{"全角の記号", "＿ＡＢＣ！", "_abc!"},
+		{"間の空白は残す", "線形 代数", "線形 代数"},
+		{"空", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeText(tt.text); got != tt.want {
+				t.Fatalf("normalizeText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
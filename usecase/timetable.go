@@ -0,0 +1,376 @@
+package usecase
+
+import (
+	"backend/model"
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/text/width"
+)
+
+// 週の時間割。HTML・SVG・PNGで共通に使う
+type timetable struct {
+	Title       string
+	Subtitle    string
+	Days        []timetableDay
+	Rows        []timetableRow
+	Unscheduled []timetableCourse // 曜日時限のない集中講義など
+}
+
+type timetableDay struct {
+	DayOfWeek int
+	Label     string
+}
+
+type timetableRow struct {
+	Period int
+	Cells  [][]timetableCourse // Daysと同じ順
+}
+
+type timetableCourse struct {
+	Name      string
+	Room      string
+	Color     string
+	TextColor string
+}
+
+// 色が指定されていない科目に順に割り当てる
+var timetablePalette = []string{"#A7C7E7", "#F7C59F", "#B5E2B0", "#F4A7B9", "#D7BDE2", "#F9E79F", "#AED6F1", "#F5CBA7"}
+
+// 表に載せる時限の上限。科目の時限の検証(SlotValidate)と揃える
+const timetableMaxPeriod = 10
+
+// 曜日・時限が表の範囲内か
+func isTimetableSlot(slot model.CourseSlot) bool {
+	return slot.DayOfWeek >= 0 && slot.DayOfWeek <= 6 && slot.Period >= 1 && slot.Period <= timetableMaxPeriod
+}
+
+// 月〜金は常に表示し、土日は科目がある場合のみ表示する。時限は5限までは常に表示する。
+// 範囲外の曜日・時限のコマは表に載せず、表に載るコマがない科目は時間割外に回す
+func buildTimetable(title string, subtitle string, courses []model.Course) timetable {
+	t := timetable{Title: title, Subtitle: subtitle, Unscheduled: []timetableCourse{}}
+
+	usedDays := map[int]bool{}
+	maxPeriod := 5
+	for _, course := range courses {
+		for _, slot := range course.Slots {
+			if !isTimetableSlot(slot) {
+				continue
+			}
+			usedDays[slot.DayOfWeek] = true
+			if slot.Period > maxPeriod {
+				maxPeriod = slot.Period
+			}
+		}
+	}
+	for _, day := range []int{1, 2, 3, 4, 5, 6, 0} {
+		if day >= 1 && day <= 5 || usedDays[day] {
+			t.Days = append(t.Days, timetableDay{DayOfWeek: day, Label: string([]rune(weekdayKanji)[day])})
+		}
+	}
+	dayIndex := map[int]int{}
+	for i, day := range t.Days {
+		dayIndex[day.DayOfWeek] = i
+	}
+	for period := 1; period <= maxPeriod; period++ {
+		t.Rows = append(t.Rows, timetableRow{Period: period, Cells: make([][]timetableCourse, len(t.Days))})
+	}
+
+	for i, course := range courses {
+		c := timetableCourse{Name: course.Name, Room: stringValue(course.Room), Color: timetablePalette[i%len(timetablePalette)]}
+		if course.Color != nil && isHexColor(*course.Color) {
+			c.Color = strings.ToUpper(*course.Color)
+		}
+		c.TextColor = textColorFor(c.Color)
+		placed := false
+		for _, slot := range course.Slots {
+			if !isTimetableSlot(slot) {
+				continue
+			}
+			row := &t.Rows[slot.Period-1]
+			row.Cells[dayIndex[slot.DayOfWeek]] = append(row.Cells[dayIndex[slot.DayOfWeek]], c)
+			placed = true
+		}
+		if !placed {
+			t.Unscheduled = append(t.Unscheduled, c)
+		}
+	}
+	for _, row := range t.Rows {
+		for _, cell := range row.Cells {
+			sort.SliceStable(cell, func(i, j int) bool { return cell[i].Name < cell[j].Name })
+		}
+	}
+	return t
+}
+
+func isHexColor(v string) bool {
+	if len(v) != 7 || v[0] != '#' {
+		return false
+	}
+	_, err := strconv.ParseUint(v[1:], 16, 32)
+	return err == nil
+}
+
+func parseHexColor(v string) color.RGBA {
+	n, _ := strconv.ParseUint(strings.TrimPrefix(v, "#"), 16, 32)
+	return color.RGBA{R: uint8(n >> 16), G: uint8(n >> 8), B: uint8(n), A: 0xff}
+}
+
+// 背景色の明るさに応じて読みやすい文字色を選ぶ
+func textColorFor(background string) string {
+	c := parseHexColor(background)
+	if 299*int(c.R)+587*int(c.G)+114*int(c.B) > 140000 {
+		return "#222222"
+	}
+	return "#FFFFFF"
+}
+
+// 描画の寸法 (px)
+const (
+	timetableMargin       = 20
+	timetableTitleHeight  = 40
+	timetableHeaderHeight = 32
+	timetablePeriodWidth  = 48
+	timetableDayWidth     = 150
+	timetableRowHeight    = 84
+	timetableFootLine     = 22
+	timetableTitleSize    = 18
+	timetableNameSize     = 13
+	timetableRoomSize     = 11
+)
+
+// SVGとPNGで共通の図形
+type timetableDrawing struct {
+	width  int
+	height int
+	rects  []timetableRect
+	texts  []timetableText
+}
+
+type timetableRect struct {
+	x, y, w, h int
+	fill       string
+}
+
+type timetableText struct {
+	x, y   int // yはベースライン
+	text   string
+	size   int
+	color  string
+	center bool
+}
+
+// measureは文字列の描画幅を返す。SVGは概算、PNGは実際のフォントで測る
+func layoutTimetable(t timetable, measure func(text string, size int) int) timetableDrawing {
+	gridTop := timetableMargin + timetableTitleHeight
+	bodyTop := gridTop + timetableHeaderHeight
+	gridWidth := timetablePeriodWidth + len(t.Days)*timetableDayWidth
+	gridHeight := timetableHeaderHeight + len(t.Rows)*timetableRowHeight
+
+	d := timetableDrawing{
+		width:  timetableMargin*2 + gridWidth,
+		height: gridTop + gridHeight + timetableMargin,
+	}
+
+	var foot []string
+	if len(t.Unscheduled) > 0 {
+		names := make([]string, 0, len(t.Unscheduled))
+		for _, c := range t.Unscheduled {
+			names = append(names, c.Name)
+		}
+		foot = wrapText("時間割外: "+strings.Join(names, "、"), gridWidth, timetableNameSize, measure, 0)
+		d.height += len(foot)*timetableFootLine + 8
+	}
+
+	d.rects = append(d.rects, timetableRect{0, 0, d.width, d.height, "#FFFFFF"})
+	title := t.Title
+	if t.Subtitle != "" {
+		title += "  " + t.Subtitle
+	}
+	d.texts = append(d.texts, timetableText{timetableMargin, timetableMargin + timetableTitleSize + 4, title, timetableTitleSize, "#222222", false})
+
+	// 見出し
+	d.rects = append(d.rects, timetableRect{timetableMargin, gridTop, gridWidth, timetableHeaderHeight, "#F0F0F0"})
+	for i, day := range t.Days {
+		x := timetableMargin + timetablePeriodWidth + i*timetableDayWidth
+		d.texts = append(d.texts, timetableText{x + timetableDayWidth/2, gridTop + 21, day.Label, timetableNameSize, "#222222", true})
+	}
+	for i, row := range t.Rows {
+		y := bodyTop + i*timetableRowHeight
+		d.rects = append(d.rects, timetableRect{timetableMargin, y, timetablePeriodWidth, timetableRowHeight, "#F0F0F0"})
+		d.texts = append(d.texts, timetableText{timetableMargin + timetablePeriodWidth/2, y + timetableRowHeight/2 + 5, strconv.Itoa(row.Period), timetableNameSize, "#222222", true})
+	}
+
+	// 科目。同じコマに複数ある場合は縦に分ける
+	for i, row := range t.Rows {
+		for j, cell := range row.Cells {
+			if len(cell) == 0 {
+				continue
+			}
+			x := timetableMargin + timetablePeriodWidth + j*timetableDayWidth + 3
+			y := bodyTop + i*timetableRowHeight + 3
+			w := timetableDayWidth - 6
+			h := (timetableRowHeight - 6 - 2*(len(cell)-1)) / len(cell)
+			for k, c := range cell {
+				top := y + k*(h+2)
+				d.rects = append(d.rects, timetableRect{x, top, w, h, c.Color})
+
+				maxLines := (h - 8) / (timetableNameSize + 3)
+				if c.Room != "" {
+					maxLines--
+				}
+				if maxLines < 1 {
+					maxLines = 1
+				}
+				baseline := top + 4 + timetableNameSize
+				for _, line := range wrapText(c.Name, w-10, timetableNameSize, measure, maxLines) {
+					d.texts = append(d.texts, timetableText{x + 5, baseline, line, timetableNameSize, c.TextColor, false})
+					baseline += timetableNameSize + 3
+				}
+				if c.Room != "" && baseline <= top+h {
+					room := wrapText(c.Room, w-10, timetableRoomSize, measure, 1)
+					d.texts = append(d.texts, timetableText{x + 5, baseline, room[0], timetableRoomSize, c.TextColor, false})
+				}
+			}
+		}
+	}
+
+	// 罫線
+	for i := 0; i <= len(t.Rows); i++ {
+		y := bodyTop + i*timetableRowHeight
+		d.rects = append(d.rects, timetableRect{timetableMargin, y, gridWidth, 1, "#999999"})
+	}
+	d.rects = append(d.rects, timetableRect{timetableMargin, gridTop, gridWidth, 1, "#999999"})
+	for i := 0; i <= len(t.Days); i++ {
+		x := timetableMargin + timetablePeriodWidth + i*timetableDayWidth
+		d.rects = append(d.rects, timetableRect{x, gridTop, 1, gridHeight + 1, "#999999"})
+	}
+	d.rects = append(d.rects, timetableRect{timetableMargin, gridTop, 1, gridHeight + 1, "#999999"})
+
+	footTop := gridTop + gridHeight + 8
+	for i, line := range foot {
+		d.texts = append(d.texts, timetableText{timetableMargin, footTop + (i+1)*timetableFootLine - 6, line, timetableNameSize, "#222222", false})
+	}
+	return d
+}
+
+// 幅に収まるよう1文字単位で折り返す。maxLinesを超える分は末尾を「…」にする (0は無制限)
+func wrapText(text string, maxWidth int, size int, measure func(string, int) int, maxLines int) []string {
+	lines := []string{}
+	current := []rune{}
+	for _, r := range text {
+		next := append(current, r)
+		if len(current) > 0 && measure(string(next), size) > maxWidth {
+			lines = append(lines, string(current))
+			current = []rune{r}
+			continue
+		}
+		current = next
+	}
+	if len(current) > 0 || len(lines) == 0 {
+		lines = append(lines, string(current))
+	}
+	if maxLines > 0 && len(lines) > maxLines {
+		last := []rune(lines[maxLines-1])
+		for len(last) > 0 && measure(string(last)+"…", size) > maxWidth {
+			last = last[:len(last)-1]
+		}
+		lines = append(lines[:maxLines-1], string(last)+"…")
+	}
+	return lines
+}
+
+// 全角文字は1em、それ以外は0.55emとして幅を見積もる
+func estimateTextWidth(text string, size int) int {
+	total := 0.0
+	for _, r := range text {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			total += float64(size)
+		default:
+			total += float64(size) * 0.55
+		}
+	}
+	return int(total + 0.5)
+}
+
+func renderTimetableSVG(d timetableDrawing) []byte {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Hiragino Sans, Noto Sans JP, Yu Gothic, sans-serif">`+"\n",
+		d.width, d.height, d.width, d.height)
+	for _, r := range d.rects {
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", r.x, r.y, r.w, r.h, r.fill)
+	}
+	for _, t := range d.texts {
+		anchor := ""
+		if t.center {
+			anchor = ` text-anchor="middle"`
+		}
+		fmt.Fprintf(b, `<text x="%d" y="%d" font-size="%d" fill="%s"%s>%s</text>`+"\n", t.x, t.y, t.size, t.color, anchor, xmlEscape(t.text))
+	}
+	b.WriteString("</svg>\n")
+	return []byte(b.String())
+}
+
+// 文字の大きさごとのフォント
+type timetableFaces struct {
+	faces map[int]font.Face
+}
+
+func newTimetableFaces(f *opentype.Font) (*timetableFaces, error) {
+	faces := &timetableFaces{faces: map[int]font.Face{}}
+	for _, size := range []int{timetableTitleSize, timetableNameSize, timetableRoomSize} {
+		face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: float64(size), DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			faces.close()
+			return nil, err
+		}
+		faces.faces[size] = face
+	}
+	return faces, nil
+}
+
+func (f *timetableFaces) measure(text string, size int) int {
+	return font.MeasureString(f.faces[size], text).Ceil()
+}
+
+func (f *timetableFaces) close() {
+	for _, face := range f.faces {
+		face.Close()
+	}
+}
+
+func renderTimetablePNG(d timetableDrawing, faces *timetableFaces) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, d.width, d.height))
+	for _, r := range d.rects {
+		draw.Draw(img, image.Rect(r.x, r.y, r.x+r.w, r.y+r.h), image.NewUniform(parseHexColor(r.fill)), image.Point{}, draw.Src)
+	}
+	for _, t := range d.texts {
+		x := t.x
+		if t.center {
+			x -= faces.measure(t.text, t.size) / 2
+		}
+		drawer := &font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(parseHexColor(t.color)),
+			Face: faces.faces[t.size],
+			Dot:  fixed.P(x, t.y),
+		}
+		drawer.DrawString(t.text)
+	}
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
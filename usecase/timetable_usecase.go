@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+
+	"golang.org/x/image/font/opentype"
+	"gorm.io/gorm"
+)
+
+type ITimetableUsecase interface {
+	GetTimetableHTML(userId uint, planId uint, planTermId *uint) ([]byte, error)
+	GetTimetableSVG(userId uint, planId uint, planTermId *uint) ([]byte, error)
+	GetTimetablePNG(userId uint, planId uint, planTermId *uint) ([]byte, error)
+}
+
+type timetableUsecase struct {
+	pr   repository.IPlanRepository
+	font *opentype.Font
+}
+
+func NewTimetableUsecase(pr repository.IPlanRepository, font *opentype.Font) ITimetableUsecase {
+	return &timetableUsecase{pr: pr, font: font}
+}
+
+// PNGに日本語を描くため、TIMETABLE_FONT_PATHの日本語フォント(TTF/OTF)を読み込む。
+// 指定がない・読めない場合はエラーを返し、起動時に気付けるようにする
+func LoadTimetableFont() (*opentype.Font, error) {
+	path := os.Getenv("TIMETABLE_FONT_PATH")
+	if path == "" {
+		return nil, errors.New("TIMETABLE_FONT_PATH is not set")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read timetable font: %w", err)
+	}
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse timetable font: %w", err)
+	}
+	return f, nil
+}
+
+func (tu *timetableUsecase) GetTimetableHTML(userId uint, planId uint, planTermId *uint) ([]byte, error) {
+	t, err := tu.timetable(userId, planId, planTermId)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err := timetableHTML.Execute(buf, t); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (tu *timetableUsecase) GetTimetableSVG(userId uint, planId uint, planTermId *uint) ([]byte, error) {
+	t, err := tu.timetable(userId, planId, planTermId)
+	if err != nil {
+		return nil, err
+	}
+	return renderTimetableSVG(layoutTimetable(t, estimateTextWidth)), nil
+}
+
+func (tu *timetableUsecase) GetTimetablePNG(userId uint, planId uint, planTermId *uint) ([]byte, error) {
+	t, err := tu.timetable(userId, planId, planTermId)
+	if err != nil {
+		return nil, err
+	}
+	faces, err := newTimetableFaces(tu.font)
+	if err != nil {
+		return nil, err
+	}
+	defer faces.close()
+	return renderTimetablePNG(layoutTimetable(t, faces.measure), faces)
+}
+
+// 学期を指定した場合はその学期の科目だけを、指定しない場合は計画の全科目を表にする
+func (tu *timetableUsecase) timetable(userId uint, planId uint, planTermId *uint) (timetable, error) {
+	var plan model.Plan
+	if err := getVisiblePlan(tu.pr, &plan, planId, userId); err != nil {
+		return timetable{}, err
+	}
+
+	subtitle := ""
+	courses := plan.Courses
+	if planTermId != nil {
+		found := false
+		for _, term := range plan.Terms {
+			if term.ID == *planTermId {
+				subtitle = planTermName(term.Grade, term.Kind)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return timetable{}, fmt.Errorf("plan term %d: %w", *planTermId, gorm.ErrRecordNotFound)
+		}
+		courses = []model.Course{}
+		for _, course := range plan.Courses {
+			if course.PlanTermID != nil && *course.PlanTermID == *planTermId {
+				courses = append(courses, course)
+			}
+		}
+	}
+	return buildTimetable(plan.Title, subtitle, courses), nil
+}
+
+var timetableHTML = template.Must(template.New("timetable").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}{{if .Subtitle}} {{.Subtitle}}{{end}}</title>
+<style>
+@page { size: A4 landscape; margin: 10mm; }
+body { font-family: "Hiragino Sans", "Noto Sans JP", "Yu Gothic", sans-serif; margin: 16px; color: #222; }
+h1 { font-size: 18px; margin: 0 0 12px; }
+h1 small { font-size: 14px; color: #666; margin-left: 8px; }
+table { border-collapse: collapse; width: 100%; table-layout: fixed; }
+th, td { border: 1px solid #999; vertical-align: top; }
+th { background: #f0f0f0; font-size: 13px; padding: 4px; }
+th.period { width: 40px; }
+td { height: 72px; padding: 2px; }
+.course { border-radius: 4px; padding: 4px; margin-bottom: 2px; font-size: 12px; -webkit-print-color-adjust: exact; print-color-adjust: exact; }
+.course .room { font-size: 11px; opacity: 0.8; }
+.unscheduled { margin-top: 12px; font-size: 12px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}{{if .Subtitle}}<small>{{.Subtitle}}</small>{{end}}</h1>
+<table>
+<thead>
+<tr><th class="period"></th>{{range .Days}}<th>{{.Label}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{range .Rows}}<tr><th class="period">{{.Period}}</th>{{range .Cells}}<td>{{range .}}<div class="course" style="background-color: {{.Color}}; color: {{.TextColor}}"><div>{{.Name}}</div>{{if .Room}}<div class="room">{{.Room}}</div>{{end}}</div>{{end}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+{{if .Unscheduled}}<div class="unscheduled">時間割外: {{range $i, $c := .Unscheduled}}{{if $i}}、{{end}}{{$c.Name}}{{end}}</div>{{end}}
+</body>
+</html>
+`))
@@ -9,6 +9,7 @@ import (
 
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -16,6 +17,7 @@ import (
 
 	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type IUserUsecase interface {
@@ -23,6 +25,7 @@ type IUserUsecase interface {
 	Login(user model.User) (string, error)
 	GetGoogleAuthURL() string
 	GoogleCallback(code string) (string, error)
+	UpdateHandle(userId uint, handle string) (model.UserResponse, error)
 }
 
 type userUsecase struct {
@@ -75,6 +78,7 @@ func (uu *userUsecase) Login(user model.User) (string, error) {
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id": storedUser.ID,
+		"role":    storedUser.Role,
 		"exp":     time.Now().Add(time.Hour * 12).Unix(),
 	})
 	tokenString, err := token.SignedString([]byte(os.Getenv("SECRET")))
@@ -135,8 +139,40 @@ func (uu *userUsecase) GoogleCallback(code string) (string, error) {
 	// JWTトークンを生成
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id": user.ID,
+		"role":    user.Role,
 		"exp":     time.Now().Add(time.Hour * 12).Unix(),
 	})
 
 	return jwtToken.SignedString([]byte(os.Getenv("SECRET")))
 }
+
+func (uu *userUsecase) UpdateHandle(userId uint, handle string) (model.UserResponse, error) {
+	if err := uu.uv.HandleValidate(handle); err != nil {
+		return model.UserResponse{}, err
+	}
+	// 全角・大文字違いで同じに見えるハンドルを作れないよう正規化して保存する
+	handle = normalizeText(handle)
+	exists, err := uu.ur.ExistsHandle(handle, userId)
+	if err != nil {
+		return model.UserResponse{}, err
+	}
+	if exists {
+		return model.UserResponse{}, fmt.Errorf("handle %q: %w", handle, ErrAlreadyExists)
+	}
+	// 確認後に他のユーザーが同じハンドルを保存した場合は一意制約で弾かれる
+	if err := uu.ur.UpdateHandle(userId, handle); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return model.UserResponse{}, fmt.Errorf("handle %q: %w", handle, ErrAlreadyExists)
+		}
+		return model.UserResponse{}, err
+	}
+	user := model.User{}
+	if err := uu.ur.GetUserByID(&user, userId); err != nil {
+		return model.UserResponse{}, err
+	}
+	return model.UserResponse{
+		ID:     user.ID,
+		Email:  user.Email,
+		Handle: user.Handle,
+	}, nil
+}
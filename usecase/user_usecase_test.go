@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/validator"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestUpdateHandle(t *testing.T) {
+	tests := []struct {
+		name       string
+		handle     string
+		taken      bool
+		updateErr  error
+		wantErr    error
+		wantHandle string
+	}{
+		{"全角・大文字は正規化して保存する", "Ｔａｒｏ_01", false, nil, nil, "taro_01"},
+		{"使用中のハンドル", "taro", true, nil, ErrAlreadyExists, ""},
+		{"確認後に他のユーザーが保存したハンドル", "taro", false, gorm.ErrDuplicatedKey, ErrAlreadyExists, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ur := &fakeUserRepository{users: map[uint]model.User{1: {ID: 1}}, taken: tt.taken, updateErr: tt.updateErr}
+			uu := NewUserUsecase(ur, validator.NewUserValidator(), nil)
+			res, err := uu.UpdateHandle(1, tt.handle)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (res.Handle == nil || *res.Handle != tt.wantHandle) {
+				t.Fatalf("handle = %v, want %q", res.Handle, tt.wantHandle)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/repository"
+	"backend/validator"
+	"backend/webhook"
+	"encoding/json"
+	"errors"
+	"log"
+	"slices"
+	"sync"
+	"time"
+)
+
+// 計画に関するイベントをWebhookの送信待ちに登録する。
+// 登録の失敗で元の操作を失敗させないよう、エラーはログに出力するだけにする
+type IWebhookPublisher interface {
+	Publish(planId uint, eventType string, data interface{})
+}
+
+type IWebhookUsecase interface {
+	IWebhookPublisher
+	GetWebhooks(userId uint) ([]model.WebhookResponse, error)
+	CreateWebhook(userId uint, isAdmin bool, req model.WebhookRequest) (model.WebhookResponse, error)
+	UpdateWebhook(userId uint, isAdmin bool, webhookId uint, req model.WebhookRequest) (model.WebhookResponse, error)
+	DeleteWebhook(userId uint, webhookId uint) error
+	GetDeliveries(userId uint, webhookId uint, offset int, limit int) ([]model.WebhookDeliveryResponse, error)
+	Redeliver(userId uint, webhookId uint, deliveryId uint) (model.WebhookDeliveryResponse, error)
+	DeliverDue() error
+}
+
+type webhookUsecase struct {
+	wr     repository.IWebhookRepository
+	wv     validator.IWebhookValidator
+	sender *webhook.Sender
+
+	mu      sync.Mutex
+	running bool // 登録直後の送信を実行中
+	again   bool // 実行中に新しい送信が登録された
+}
+
+func NewWebhookUsecase(wr repository.IWebhookRepository, wv validator.IWebhookValidator, sender *webhook.Sender) IWebhookUsecase {
+	return &webhookUsecase{wr: wr, wv: wv, sender: sender}
+}
+
+const (
+	webhookMaxAttempts    = 8                // 初回を含めた送信回数の上限
+	webhookRetryBaseDelay = 30 * time.Second // 1回目の再送までの待ち時間。以降は2倍ずつ延ばす
+	webhookLease          = time.Minute      // 送信中の送信を他のインスタンスが取らないようにする時間
+	webhookDeliveryBatch  = 100              // 1回の実行で送る件数の上限
+)
+
+// Webhookの全件を対象にする設定を一般ユーザーが指定した場合に返す
+var ErrWebhookAllPlansForbidden = errors.New("only admins can subscribe to all plans")
+
+func (wu *webhookUsecase) Publish(planId uint, eventType string, data interface{}) {
+	webhooks := []model.Webhook{}
+	if err := wu.wr.GetSubscribedWebhooks(&webhooks, planId); err != nil {
+		log.Printf("enqueue webhook %s on plan %d: %v", eventType, planId, err)
+		return
+	}
+	webhooks = slices.DeleteFunc(webhooks, func(w model.Webhook) bool {
+		return !slices.Contains(w.Events, eventType)
+	})
+	if len(webhooks) == 0 {
+		return
+	}
+	now := time.Now()
+	payload, err := json.Marshal(model.WebhookPayload{Type: eventType, PlanID: planId, CreatedAt: now, Data: data})
+	if err != nil {
+		log.Printf("enqueue webhook %s on plan %d: %v", eventType, planId, err)
+		return
+	}
+	deliveries := make([]model.WebhookDelivery, 0, len(webhooks))
+	for _, w := range webhooks {
+		deliveries = append(deliveries, model.WebhookDelivery{
+			WebhookID:     w.ID,
+			EventType:     eventType,
+			Payload:       string(payload),
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: now,
+		})
+	}
+	if err := wu.wr.CreateDeliveries(&deliveries); err != nil {
+		log.Printf("enqueue webhook %s on plan %d: %v", eventType, planId, err)
+		return
+	}
+	wu.deliverSoon()
+}
+
+func (wu *webhookUsecase) GetWebhooks(userId uint) ([]model.WebhookResponse, error) {
+	webhooks := []model.Webhook{}
+	if err := wu.wr.GetWebhooksByUserID(&webhooks, userId); err != nil {
+		return nil, err
+	}
+	res := make([]model.WebhookResponse, 0, len(webhooks))
+	for _, w := range webhooks {
+		res = append(res, toWebhookResponse(w))
+	}
+	return res, nil
+}
+
+// 署名用のシークレットはこのときだけ返す
+func (wu *webhookUsecase) CreateWebhook(userId uint, isAdmin bool, req model.WebhookRequest) (model.WebhookResponse, error) {
+	if err := wu.checkRequest(isAdmin, req); err != nil {
+		return model.WebhookResponse{}, err
+	}
+	secret, err := generateToken()
+	if err != nil {
+		return model.WebhookResponse{}, err
+	}
+	w := model.Webhook{
+		UserID:   userId,
+		URL:      req.URL,
+		Secret:   secret,
+		Events:   slices.Compact(slices.Sorted(slices.Values(req.Events))),
+		AllPlans: req.AllPlans,
+		Active:   req.Active == nil || *req.Active,
+	}
+	if err := wu.wr.CreateWebhook(&w); err != nil {
+		return model.WebhookResponse{}, err
+	}
+	res := toWebhookResponse(w)
+	res.Secret = secret
+	return res, nil
+}
+
+func (wu *webhookUsecase) UpdateWebhook(userId uint, isAdmin bool, webhookId uint, req model.WebhookRequest) (model.WebhookResponse, error) {
+	if err := wu.checkRequest(isAdmin, req); err != nil {
+		return model.WebhookResponse{}, err
+	}
+	var w model.Webhook
+	if err := wu.getOwnWebhook(&w, userId, webhookId); err != nil {
+		return model.WebhookResponse{}, err
+	}
+	w.URL = req.URL
+	w.Events = slices.Compact(slices.Sorted(slices.Values(req.Events)))
+	w.AllPlans = req.AllPlans
+	w.Active = req.Active == nil || *req.Active
+	if err := wu.wr.UpdateWebhook(&w); err != nil {
+		return model.WebhookResponse{}, err
+	}
+	return toWebhookResponse(w), nil
+}
+
+func (wu *webhookUsecase) DeleteWebhook(userId uint, webhookId uint) error {
+	var w model.Webhook
+	if err := wu.getOwnWebhook(&w, userId, webhookId); err != nil {
+		return err
+	}
+	return wu.wr.DeleteWebhook(webhookId)
+}
+
+// 新しい順に返す
+func (wu *webhookUsecase) GetDeliveries(userId uint, webhookId uint, offset int, limit int) ([]model.WebhookDeliveryResponse, error) {
+	var w model.Webhook
+	if err := wu.getOwnWebhook(&w, userId, webhookId); err != nil {
+		return nil, err
+	}
+	deliveries := []model.WebhookDelivery{}
+	if err := wu.wr.GetDeliveries(&deliveries, webhookId, offset, limit); err != nil {
+		return nil, err
+	}
+	res := make([]model.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		res = append(res, toWebhookDeliveryResponse(delivery))
+	}
+	return res, nil
+}
+
+// 元の送信の記録は残し、同じ本文で新しい送信として登録する
+func (wu *webhookUsecase) Redeliver(userId uint, webhookId uint, deliveryId uint) (model.WebhookDeliveryResponse, error) {
+	var w model.Webhook
+	if err := wu.getOwnWebhook(&w, userId, webhookId); err != nil {
+		return model.WebhookDeliveryResponse{}, err
+	}
+	var original model.WebhookDelivery
+	if err := wu.wr.GetDelivery(&original, webhookId, deliveryId); err != nil {
+		return model.WebhookDeliveryResponse{}, err
+	}
+	deliveries := []model.WebhookDelivery{{
+		WebhookID:      webhookId,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         model.WebhookDeliveryPending,
+		NextAttemptAt:  time.Now(),
+		RedeliveryOfID: &original.ID,
+	}}
+	if err := wu.wr.CreateDeliveries(&deliveries); err != nil {
+		return model.WebhookDeliveryResponse{}, err
+	}
+	wu.deliverSoon()
+	return toWebhookDeliveryResponse(deliveries[0]), nil
+}
+
+// 送信予定を過ぎたものを送る。失敗した場合は待ち時間を倍にしながら上限の回数まで再送する
+func (wu *webhookUsecase) DeliverDue() error {
+	now := time.Now()
+	deliveries := []model.WebhookDelivery{}
+	if err := wu.wr.GetDueDeliveries(&deliveries, now, webhookDeliveryBatch); err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		claimed, err := wu.wr.ClaimDelivery(delivery, now.Add(webhookLease))
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+		wu.deliver(&delivery)
+		if err := wu.wr.SaveDeliveryResult(&delivery); err != nil {
+			log.Printf("save webhook delivery %d: %v", delivery.ID, err)
+		}
+	}
+	return nil
+}
+
+// 定期実行を待たずにバックグラウンドで送る。実行中に呼ばれた場合は終わった後にもう一度実行する
+func (wu *webhookUsecase) deliverSoon() {
+	wu.mu.Lock()
+	defer wu.mu.Unlock()
+	if wu.running {
+		wu.again = true
+		return
+	}
+	wu.running = true
+	go func() {
+		for {
+			if err := wu.DeliverDue(); err != nil {
+				log.Printf("deliver webhooks: %v", err)
+			}
+			wu.mu.Lock()
+			if !wu.again {
+				wu.running = false
+				wu.mu.Unlock()
+				return
+			}
+			wu.again = false
+			wu.mu.Unlock()
+		}
+	}()
+}
+
+func (wu *webhookUsecase) deliver(delivery *model.WebhookDelivery) {
+	result, err := wu.sender.Send(delivery.Webhook.URL, delivery.Webhook.Secret, delivery.EventType, delivery.ID, []byte(delivery.Payload))
+	delivery.Attempts++
+	delivery.ResponseStatus = nil
+	delivery.ResponseBody = nil
+	delivery.Error = nil
+	if result.StatusCode != 0 {
+		delivery.ResponseStatus = &result.StatusCode
+		delivery.ResponseBody = &result.Body
+	}
+	if err == nil {
+		now := time.Now()
+		delivery.Status = model.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+		return
+	}
+	message := err.Error()
+	delivery.Error = &message
+	if delivery.Attempts >= webhookMaxAttempts {
+		delivery.Status = model.WebhookDeliveryFailed
+		return
+	}
+	delivery.NextAttemptAt = time.Now().Add(webhookRetryDelay(delivery.Attempts))
+}
+
+// attempts回失敗した後の待ち時間
+func webhookRetryDelay(attempts int) time.Duration {
+	return webhookRetryBaseDelay << (attempts - 1)
+}
+
+func (wu *webhookUsecase) checkRequest(isAdmin bool, req model.WebhookRequest) error {
+	if err := wu.wv.WebhookValidate(req); err != nil {
+		return err
+	}
+	if req.AllPlans && !isAdmin {
+		return ErrWebhookAllPlansForbidden
+	}
+	return nil
+}
+
+func (wu *webhookUsecase) getOwnWebhook(w *model.Webhook, userId uint, webhookId uint) error {
+	if err := wu.wr.GetWebhookByID(w, webhookId); err != nil {
+		return err
+	}
+	if w.UserID != userId {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func toWebhookResponse(w model.Webhook) model.WebhookResponse {
+	return model.WebhookResponse{
+		ID:        w.ID,
+		URL:       w.URL,
+		Events:    w.Events,
+		AllPlans:  w.AllPlans,
+		Active:    w.Active,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryResponse(delivery model.WebhookDelivery) model.WebhookDeliveryResponse {
+	res := model.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventType:      delivery.EventType,
+		Payload:        json.RawMessage(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		ResponseBody:   delivery.ResponseBody,
+		Error:          delivery.Error,
+		RedeliveryOfID: delivery.RedeliveryOfID,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == model.WebhookDeliveryPending {
+		res.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return res
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"backend/model"
+	"backend/webhook"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDeliverDueRetriesWithBackoff(t *testing.T) {
+	statuses := []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}
+	var signatures []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signatures = append(signatures, r.Header.Get(webhook.HeaderSignature))
+		w.WriteHeader(statuses[0])
+		statuses = statuses[1:]
+	}))
+	defer server.Close()
+
+	wr := &fakeWebhookRepository{due: []model.WebhookDelivery{{
+		ID:            7,
+		WebhookID:     3,
+		EventType:     model.WebhookEventPlanUpdated,
+		Payload:       `{"type":"plan.updated"}`,
+		Status:        model.WebhookDeliveryPending,
+		NextAttemptAt: time.Now(),
+		Webhook:       model.Webhook{ID: 3, URL: server.URL, Secret: "secret"},
+	}}}
+	wu := NewWebhookUsecase(wr, nil, webhook.NewSender(server.Client())).(*webhookUsecase)
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		before := time.Now()
+		if err := wu.DeliverDue(); err != nil {
+			t.Fatalf("DeliverDue: %v", err)
+		}
+		saved := wr.saved[len(wr.saved)-1]
+		if saved.Status != model.WebhookDeliveryPending || saved.Attempts != attempt {
+			t.Fatalf("attempt %d: status=%s attempts=%d", attempt, saved.Status, saved.Attempts)
+		}
+		if saved.ResponseStatus == nil || *saved.ResponseStatus < 500 || saved.Error == nil {
+			t.Fatalf("attempt %d: response_status=%v error=%v", attempt, saved.ResponseStatus, saved.Error)
+		}
+		wantDelay := webhookRetryBaseDelay << (attempt - 1)
+		if delay := saved.NextAttemptAt.Sub(before); delay < wantDelay || delay > wantDelay+time.Second {
+			t.Fatalf("attempt %d: next attempt in %v, want %v", attempt, delay, wantDelay)
+		}
+		// 再送の予定時刻までは送らない
+		if err := wu.DeliverDue(); err != nil {
+			t.Fatalf("DeliverDue: %v", err)
+		}
+		if len(signatures) != attempt {
+			t.Fatalf("sent %d times before the retry time", len(signatures))
+		}
+		wr.due[0].NextAttemptAt = time.Now()
+	}
+
+	if err := wu.DeliverDue(); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+	saved := wr.saved[len(wr.saved)-1]
+	if saved.Status != model.WebhookDeliverySucceeded || saved.Attempts != 3 || saved.DeliveredAt == nil {
+		t.Fatalf("status=%s attempts=%d delivered_at=%v", saved.Status, saved.Attempts, saved.DeliveredAt)
+	}
+	if saved.ResponseStatus == nil || *saved.ResponseStatus != http.StatusOK || saved.Error != nil {
+		t.Fatalf("response_status=%v error=%v", saved.ResponseStatus, saved.Error)
+	}
+	for _, signature := range signatures {
+		if err := webhook.Verify("secret", signature, []byte(`{"type":"plan.updated"}`), time.Minute); err != nil {
+			t.Fatalf("Verify(%q): %v", signature, err)
+		}
+	}
+}
+
+func TestDeliverDueGivesUpAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	wr := &fakeWebhookRepository{due: []model.WebhookDelivery{{
+		ID:            1,
+		Status:        model.WebhookDeliveryPending,
+		Attempts:      webhookMaxAttempts - 1,
+		NextAttemptAt: time.Now(),
+		Webhook:       model.Webhook{URL: server.URL, Secret: "secret"},
+	}}}
+	wu := NewWebhookUsecase(wr, nil, webhook.NewSender(server.Client())).(*webhookUsecase)
+	if err := wu.DeliverDue(); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+	saved := wr.saved[0]
+	if saved.Status != model.WebhookDeliveryFailed || saved.Attempts != webhookMaxAttempts {
+		t.Fatalf("status=%s attempts=%d", saved.Status, saved.Attempts)
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := webhookRetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("webhookRetryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package validator
+
+import (
+	"backend/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type IAnswerValidator interface {
+	AnswerValidate(answer model.Answer) error
+	VoteValidate(vote model.AnswerVote) error
+}
+
+type AnswerValidator struct{}
+
+func NewAnswerValidator() IAnswerValidator {
+	return &AnswerValidator{}
+}
+
+func (av *AnswerValidator) AnswerValidate(answer model.Answer) error {
+	return validation.ValidateStruct(&answer,
+		validation.Field(
+			&answer.Content,
+			validation.Required.Error("content is required"),
+			validation.RuneLength(1, 2000).Error("limited max 2000 characters"),
+		),
+	)
+}
+
+func (av *AnswerValidator) VoteValidate(vote model.AnswerVote) error {
+	return validation.ValidateStruct(&vote,
+		validation.Field(
+			&vote.Value,
+			validation.Required.Error("value is required"),
+			validation.In(1, -1).Error("value must be 1 or -1"),
+		),
+	)
+}
@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"backend/model"
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type ICalendarValidator interface {
+	PeriodTimeValidate(period model.PeriodTime) error
+	HolidayValidate(holiday model.AcademicHoliday) error
+}
+
+type CalendarValidator struct{}
+
+func NewCalendarValidator() ICalendarValidator {
+	return &CalendarValidator{}
+}
+
+var clockPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+func (cv *CalendarValidator) PeriodTimeValidate(period model.PeriodTime) error {
+	return validation.ValidateStruct(&period,
+		validation.Field(
+			&period.Period,
+			validation.Required.Error("Period is required"),
+			validation.Min(1).Error("Period must be between 1 and 10"),
+			validation.Max(10).Error("Period must be between 1 and 10"),
+		),
+		validation.Field(
+			&period.StartTime,
+			validation.Required.Error("StartTime is required"),
+			validation.Match(clockPattern).Error("StartTime must be HH:MM"),
+		),
+		validation.Field(
+			&period.EndTime,
+			validation.Required.Error("EndTime is required"),
+			validation.Match(clockPattern).Error("EndTime must be HH:MM"),
+			validation.By(func(value interface{}) error {
+				// HH:MM 形式なので文字列の比較で前後を判定できる
+				if value.(string) <= period.StartTime {
+					return validation.NewError("validation_end_time", "EndTime must be after StartTime")
+				}
+				return nil
+			}),
+		),
+	)
+}
+
+func (cv *CalendarValidator) HolidayValidate(holiday model.AcademicHoliday) error {
+	return validation.ValidateStruct(&holiday,
+		validation.Field(
+			&holiday.UniversityID,
+			validation.Required.Error("UniversityID is required"),
+		),
+		validation.Field(
+			&holiday.Date,
+			validation.Required.Error("Date is required"),
+		),
+		validation.Field(
+			&holiday.Name,
+			validation.Required.Error("Name is required"),
+			validation.Length(1, 30).Error("limited max 30 characters"),
+		),
+	)
+}
@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"backend/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type ICatalogValidator interface {
+	CatalogCourseValidate(course model.CatalogCourse) error
+}
+
+type CatalogValidator struct{}
+
+func NewCatalogValidator() ICatalogValidator {
+	return &CatalogValidator{}
+}
+
+func (cv *CatalogValidator) CatalogCourseValidate(course model.CatalogCourse) error {
+	if err := validation.ValidateStruct(&course,
+		validation.Field(
+			&course.UniversityID,
+			validation.Required.Error("UniversityID is required"),
+		),
+		validation.Field(
+			&course.Code,
+			validation.Required.Error("Code is required"),
+			validation.Length(1, 30).Error("limited max 30 characters"),
+		),
+		validation.Field(
+			&course.Name,
+			validation.Required.Error("Name is required"),
+			validation.Length(1, 100).Error("limited max 100 characters"),
+		),
+		validation.Field(
+			&course.Instructor,
+			validation.Length(0, 100).Error("limited max 100 characters"),
+		),
+		validation.Field(
+			&course.Credits,
+			validation.Max(uint(20)).Error("Credits is not valid"),
+		),
+	); err != nil {
+		return err
+	}
+
+	for _, slot := range course.Slots {
+		if err := SlotValidate(slot.DayOfWeek, slot.Period); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 曜日は0(日)〜6(土)、時限は1〜10
+func SlotValidate(dayOfWeek int, period int) error {
+	return validation.Errors{
+		"day_of_week": validation.Validate(dayOfWeek, validation.Min(0), validation.Max(6).Error("DayOfWeek must be between 0 and 6")),
+		"period":      validation.Validate(period, validation.Min(1).Error("Period must be between 1 and 10"), validation.Max(10).Error("Period must be between 1 and 10")),
+	}.Filter()
+}
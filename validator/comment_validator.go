@@ -0,0 +1,31 @@
+package validator
+
+import (
+	"backend/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type ICommentValidator interface {
+	CommentValidate(comment model.Comment) error
+}
+
+type CommentValidator struct{}
+
+func NewCommentValidator() ICommentValidator {
+	return &CommentValidator{}
+}
+
+func (cv *CommentValidator) CommentValidate(comment model.Comment) error {
+	return validation.ValidateStruct(&comment,
+		validation.Field(
+			&comment.Content,
+			validation.Required.Error("content is required"),
+			validation.RuneLength(1, 1000).Error("limited max 1000 characters"),
+		),
+		validation.Field(
+			&comment.PlanID,
+			validation.Required.Error("plan_id is required"),
+		),
+	)
+}
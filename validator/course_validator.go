@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"backend/model"
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type ICourseValidator interface {
+	CourseValidate(course model.Course) error
+	CourseUpdateValidate(course model.Course) error
+}
+
+type CourseValidator struct{}
+
+func NewCourseValidator() ICourseValidator {
+	return &CourseValidator{}
+}
+
+func (cv *CourseValidator) CourseValidate(course model.Course) error {
+	return courseValidate(course, true)
+}
+
+// 部分的な更新のため、名前は省略できる
+func (cv *CourseValidator) CourseUpdateValidate(course model.Course) error {
+	return courseValidate(course, false)
+}
+
+func courseValidate(course model.Course, requireName bool) error {
+	nameRules := []validation.Rule{validation.RuneLength(1, 100).Error("limited max 100 characters")}
+	if requireName {
+		nameRules = append([]validation.Rule{validation.Required.Error("Name is required")}, nameRules...)
+	}
+	if err := validation.ValidateStruct(&course,
+		validation.Field(&course.Name, nameRules...),
+		validation.Field(
+			&course.Credits,
+			validation.Max(uint(30)).Error("Credits must be 30 or less"),
+		),
+		validation.Field(
+			&course.Room,
+			validation.RuneLength(0, 50).Error("limited max 50 characters"),
+		),
+		validation.Field(
+			&course.Color,
+			validation.Match(colorPattern).Error("Color must be in #RRGGBB format"),
+		),
+	); err != nil {
+		return err
+	}
+	for _, slot := range course.Slots {
+		if err := SlotValidate(slot.DayOfWeek, slot.Period); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 時間割の表示色は #RRGGBB 形式
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
@@ -0,0 +1,36 @@
+package validator
+
+import (
+	"backend/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type IDigestValidator interface {
+	DigestSettingValidate(req model.DigestSettingRequest) error
+}
+
+type DigestValidator struct{}
+
+func NewDigestValidator() IDigestValidator {
+	return &DigestValidator{}
+}
+
+func (dv *DigestValidator) DigestSettingValidate(req model.DigestSettingRequest) error {
+	return validation.ValidateStruct(&req,
+		validation.Field(
+			&req.Frequency,
+			validation.Required.Error("frequency is required"),
+			validation.In(
+				model.DigestFrequencyOff,
+				model.DigestFrequencyDaily,
+				model.DigestFrequencyWeekly,
+			).Error("frequency must be one of off, daily, weekly"),
+		),
+		validation.Field(
+			&req.Locale,
+			validation.Required.Error("locale is required"),
+			validation.In(model.DigestLocaleJa, model.DigestLocaleEn).Error("locale must be one of ja, en"),
+		),
+	)
+}
@@ -0,0 +1,34 @@
+package validator
+
+import (
+	"backend/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type IFavoriteValidator interface {
+	CollectionValidate(collection model.FavoriteCollection) error
+	NoteValidate(note *string) error
+}
+
+type FavoriteValidator struct{}
+
+func NewFavoriteValidator() IFavoriteValidator {
+	return &FavoriteValidator{}
+}
+
+func (fv *FavoriteValidator) CollectionValidate(collection model.FavoriteCollection) error {
+	return validation.ValidateStruct(&collection,
+		validation.Field(
+			&collection.Name,
+			validation.Required.Error("name is required"),
+			validation.RuneLength(1, 50).Error("limited max 50 characters"),
+		),
+	)
+}
+
+func (fv *FavoriteValidator) NoteValidate(note *string) error {
+	return validation.Validate(note,
+		validation.RuneLength(0, 1000).Error("note is limited max 1000 characters"),
+	)
+}
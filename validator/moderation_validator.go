@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"backend/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type IModerationValidator interface {
+	ReportValidate(req model.ReportRequest) error
+	NGWordValidate(word model.NGWord) error
+}
+
+type ModerationValidator struct{}
+
+func NewModerationValidator() IModerationValidator {
+	return &ModerationValidator{}
+}
+
+func (mv *ModerationValidator) ReportValidate(req model.ReportRequest) error {
+	return validation.ValidateStruct(&req,
+		validation.Field(
+			&req.Reason,
+			validation.Required.Error("reason is required"),
+			validation.In(
+				model.ReportReasonSpam,
+				model.ReportReasonHarassment,
+				model.ReportReasonInappropriate,
+				model.ReportReasonPersonalInfo,
+				model.ReportReasonOther,
+			).Error("reason must be one of spam, harassment, inappropriate, personal_info, other"),
+		),
+		validation.Field(
+			&req.Detail,
+			validation.RuneLength(0, 500).Error("limited max 500 characters"),
+		),
+	)
+}
+
+func (mv *ModerationValidator) NGWordValidate(word model.NGWord) error {
+	return validation.ValidateStruct(&word,
+		validation.Field(
+			&word.Word,
+			validation.Required.Error("word is required"),
+			validation.RuneLength(1, 50).Error("limited max 50 characters"),
+		),
+	)
+}
@@ -28,5 +28,10 @@ func (plv *PlanValidator) PlanValidate(plan model.Plan) error {
 			validation.Required.Error("Content is required"),
 			validation.Length(1, 100).Error("limited max 100 characters"),
 		),
+		validation.Field(
+			&plan.Visibility,
+			validation.In(model.PlanVisibilityPublic, model.PlanVisibilityPrivate).
+				Error("Visibility must be one of public, private"),
+		),
 	)
 }
@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"backend/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type IRequirementValidator interface {
+	RequirementSetValidate(set model.RequirementSet) error
+}
+
+type RequirementValidator struct{}
+
+func NewRequirementValidator() IRequirementValidator {
+	return &RequirementValidator{}
+}
+
+func (rv *RequirementValidator) RequirementSetValidate(set model.RequirementSet) error {
+	if err := validation.ValidateStruct(&set,
+		validation.Field(
+			&set.Name,
+			validation.Required.Error("Name is required"),
+			validation.Length(1, 50).Error("limited max 50 characters"),
+		),
+		validation.Field(
+			&set.DepartmentID,
+			validation.Required.Error("DepartmentID is required"),
+		),
+		validation.Field(
+			&set.EntryYear,
+			validation.Required.Error("EntryYear is required"),
+			validation.Min(uint(1900)).Error("EntryYear is not valid"),
+		),
+		validation.Field(
+			&set.Rules,
+			validation.Required.Error("Rules is required"),
+		),
+	); err != nil {
+		return err
+	}
+
+	for _, rule := range set.Rules {
+		if err := validation.ValidateStruct(&rule,
+			validation.Field(
+				&rule.Type,
+				validation.Required.Error("Type is required"),
+				validation.In(
+					model.RequirementTypeMinCredits,
+					model.RequirementTypeMandatory,
+					model.RequirementTypeChooseN,
+				).Error("Type must be one of min_credits, mandatory, choose_n"),
+			),
+			validation.Field(
+				&rule.Name,
+				validation.Required.Error("Name is required"),
+				validation.Length(1, 50).Error("limited max 50 characters"),
+			),
+			validation.Field(
+				&rule.MinCredits,
+				validation.When(rule.Type == model.RequirementTypeMinCredits,
+					validation.Required.Error("MinCredits is required")),
+			),
+			validation.Field(
+				&rule.MinCount,
+				validation.When(rule.Type == model.RequirementTypeChooseN,
+					validation.Required.Error("MinCount is required"),
+					validation.Max(uint(len(rule.Courses))).Error("MinCount exceeds the number of courses")),
+			),
+			validation.Field(
+				&rule.Courses,
+				validation.When(rule.Type != model.RequirementTypeMinCredits,
+					validation.Required.Error("Courses is required")),
+			),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"backend/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type IReviewValidator interface {
+	ReviewValidate(review model.CourseReview) error
+}
+
+type ReviewValidator struct{}
+
+func NewReviewValidator() IReviewValidator {
+	return &ReviewValidator{}
+}
+
+func (rv *ReviewValidator) ReviewValidate(review model.CourseReview) error {
+	return validation.ValidateStruct(&review,
+		validation.Field(
+			&review.CatalogCourseID,
+			validation.Required.Error("CatalogCourseID is required"),
+		),
+		validation.Field(
+			&review.AcademicYear,
+			validation.Required.Error("AcademicYear is required"),
+			validation.Min(uint(1900)).Error("AcademicYear is not valid"),
+		),
+		validation.Field(
+			&review.TermKind,
+			validation.Required.Error("TermKind is required"),
+			validation.In(model.TermKindFirst, model.TermKindSecond, model.TermKindIntensive).
+				Error("TermKind must be one of first, second, intensive"),
+		),
+		validation.Field(&review.Rating, scoreRules("Rating")...),
+		validation.Field(&review.Difficulty, scoreRules("Difficulty")...),
+		validation.Field(&review.Workload, scoreRules("Workload")...),
+		validation.Field(&review.AttendanceStrictness, scoreRules("AttendanceStrictness")...),
+		validation.Field(
+			&review.Content,
+			validation.RuneLength(0, 2000).Error("limited max 2000 characters"),
+		),
+	)
+}
+
+// 評価は1〜5の5段階
+func scoreRules(name string) []validation.Rule {
+	return []validation.Rule{
+		validation.Required.Error(name + " is required"),
+		validation.Min(1).Error(name + " must be between 1 and 5"),
+		validation.Max(5).Error(name + " must be between 1 and 5"),
+	}
+}
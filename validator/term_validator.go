@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"backend/model"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type ITermValidator interface {
+	AcademicTermValidate(term model.AcademicTerm) error
+	PlanTermValidate(term model.PlanTerm) error
+}
+
+type TermValidator struct{}
+
+func NewTermValidator() ITermValidator {
+	return &TermValidator{}
+}
+
+func (tv *TermValidator) AcademicTermValidate(term model.AcademicTerm) error {
+	return validation.ValidateStruct(&term,
+		validation.Field(
+			&term.UniversityID,
+			validation.Required.Error("UniversityID is required"),
+		),
+		validation.Field(
+			&term.AcademicYear,
+			validation.Required.Error("AcademicYear is required"),
+			validation.Min(uint(1900)).Error("AcademicYear is not valid"),
+		),
+		validation.Field(
+			&term.Kind,
+			validation.Required.Error("Kind is required"),
+			validation.In(model.TermKindFirst, model.TermKindSecond, model.TermKindIntensive).
+				Error("Kind must be one of first, second, intensive"),
+		),
+		validation.Field(
+			&term.Name,
+			validation.Required.Error("Name is required"),
+			validation.Length(1, 20).Error("limited max 20 characters"),
+		),
+		validation.Field(
+			&term.StartDate,
+			validation.Required.Error("StartDate is required"),
+		),
+		validation.Field(
+			&term.EndDate,
+			validation.Required.Error("EndDate is required"),
+			validation.Min(term.StartDate).Error("EndDate must be after StartDate"),
+		),
+		validation.Field(
+			&term.RegistrationEndAt,
+			validation.When(term.RegistrationStartAt != nil,
+				validation.Required.Error("RegistrationEndAt is required"),
+				validation.By(func(value interface{}) error {
+					if end, ok := value.(*time.Time); ok && end != nil && end.Before(*term.RegistrationStartAt) {
+						return validation.NewError("validation_registration_end", "RegistrationEndAt must be after RegistrationStartAt")
+					}
+					return nil
+				}),
+			),
+		),
+	)
+}
+
+func (tv *TermValidator) PlanTermValidate(term model.PlanTerm) error {
+	return validation.ValidateStruct(&term,
+		validation.Field(
+			&term.Grade,
+			validation.Required.Error("Grade is required"),
+			validation.Max(uint(6)).Error("Grade must be between 1 and 6"),
+		),
+		validation.Field(
+			&term.Kind,
+			validation.Required.Error("Kind is required"),
+			validation.In(model.TermKindFirst, model.TermKindSecond, model.TermKindIntensive).
+				Error("Kind must be one of first, second, intensive"),
+		),
+	)
+}
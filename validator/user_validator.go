@@ -2,6 +2,7 @@ package validator
 
 import (
 	"backend/model"
+	"regexp"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -9,8 +10,12 @@ import (
 
 type IUserValidator interface {
 	UserValidate(user model.User) error
+	HandleValidate(handle string) error
 }
 
+// 日本語を含む文字・数字・アンダースコア
+var handlePattern = regexp.MustCompile(`^[\p{L}\p{N}_]+$`)
+
 type UserValidator struct{}
 
 func NewUserValidator() IUserValidator {
@@ -32,3 +37,11 @@ func (uv *UserValidator) UserValidate(user model.User) error {
 		),
 	)
 }
+
+func (uv *UserValidator) HandleValidate(handle string) error {
+	return validation.Validate(handle,
+		validation.Required.Error("handle is required"),
+		validation.RuneLength(2, 20).Error("handle must be between 2 and 20 characters"),
+		validation.Match(handlePattern).Error("handle may contain only letters, numbers and underscores"),
+	)
+}
@@ -0,0 +1,42 @@
+package validator
+
+import (
+	"backend/model"
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+type IWebhookValidator interface {
+	WebhookValidate(req model.WebhookRequest) error
+}
+
+type WebhookValidator struct{}
+
+func NewWebhookValidator() IWebhookValidator {
+	return &WebhookValidator{}
+}
+
+var webhookURLPattern = regexp.MustCompile(`^https?://`)
+
+func (wv *WebhookValidator) WebhookValidate(req model.WebhookRequest) error {
+	eventTypes := make([]interface{}, 0, len(model.WebhookEventTypes))
+	for _, t := range model.WebhookEventTypes {
+		eventTypes = append(eventTypes, t)
+	}
+	return validation.ValidateStruct(&req,
+		validation.Field(
+			&req.URL,
+			validation.Required.Error("url is required"),
+			validation.RuneLength(1, 2000).Error("limited max 2000 characters"),
+			is.URL.Error("url is invalid"),
+			validation.Match(webhookURLPattern).Error("url must start with http:// or https://"),
+		),
+		validation.Field(
+			&req.Events,
+			validation.Required.Error("events is required"),
+			validation.Each(validation.In(eventTypes...).Error("events must be plan.created, plan.updated, comment.created or favorite.toggled")),
+		),
+	)
+}
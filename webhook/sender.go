@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// 受信側の応答として保存する本文の上限
+const maxResponseBody = 1024
+
+// 内部ネットワークへの送信を拒否した場合に返す
+var ErrPrivateAddress = errors.New("webhook to private address is not allowed")
+
+type Sender struct {
+	client *http.Client
+}
+
+// テストではhttptestのサーバーに送れるクライアントを渡す
+func NewSender(client *http.Client) *Sender {
+	return &Sender{client: client}
+}
+
+type Result struct {
+	StatusCode int
+	Body       string
+}
+
+// 2xx以外の応答もエラーにする。その場合もResultには応答を入れて返す
+func (s *Sender) Send(url string, secret string, eventType string, deliveryId uint, body []byte) (Result, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ClassPlanner-Webhook/1.0")
+	req.Header.Set(HeaderEvent, eventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(deliveryId), 10))
+	req.Header.Set(HeaderSignature, Sign(secret, time.Now(), body))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer res.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(res.Body, maxResponseBody))
+	result := Result{StatusCode: res.StatusCode, Body: string(respBody)}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return result, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return result, nil
+}
+
+// リダイレクトには従わない。GO_ENV=dev以外ではループバックやプライベートアドレスへの接続を拒否する
+func NewHTTPClientFromEnv() *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if os.Getenv("GO_ENV") != "dev" {
+		dialer.Control = rejectPrivateAddress
+	}
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// 名前解決後のアドレスで判定するため、DNSで内部アドレスを返すURLも拒否できる
+func rejectPrivateAddress(network string, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return ErrPrivateAddress
+	}
+	return nil
+}
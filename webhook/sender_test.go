@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 受け取ったリクエストを記録し、statusesの順に応答する
+type recordingServer struct {
+	*httptest.Server
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newRecordingServer(t *testing.T, statuses ...int) *recordingServer {
+	s := &recordingServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.requests = append(s.requests, r)
+		s.bodies = append(s.bodies, body)
+		status := s.statuses[0]
+		if len(s.statuses) > 1 {
+			s.statuses = s.statuses[1:]
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, "status "+strconv.Itoa(status))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestSendSignsRequest(t *testing.T) {
+	server := newRecordingServer(t, http.StatusOK)
+	body := []byte(`{"type":"plan.updated","plan_id":1}`)
+
+	result, err := NewSender(server.Client()).Send(server.URL, "secret", "plan.updated", 42, body)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.StatusCode != http.StatusOK || result.Body != "status 200" {
+		t.Fatalf("result = %+v", result)
+	}
+	req := server.requests[0]
+	if got := req.Header.Get(HeaderEvent); got != "plan.updated" {
+		t.Errorf("%s = %q", HeaderEvent, got)
+	}
+	if got := req.Header.Get(HeaderDelivery); got != "42" {
+		t.Errorf("%s = %q", HeaderDelivery, got)
+	}
+	if string(server.bodies[0]) != string(body) {
+		t.Errorf("body = %s", server.bodies[0])
+	}
+
+	header := req.Header.Get(HeaderSignature)
+	ts, v1, ok := strings.Cut(header, ",")
+	if !ok || !strings.HasPrefix(ts, "t=") || !strings.HasPrefix(v1, "v1=") {
+		t.Fatalf("%s = %q", HeaderSignature, header)
+	}
+	want := Sign("secret", time.Unix(mustParseInt(t, strings.TrimPrefix(ts, "t=")), 0), body)
+	if header != want {
+		t.Errorf("%s = %q, want %q", HeaderSignature, header, want)
+	}
+	if err := Verify("secret", header, body, time.Minute); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	if err := Verify("other", header, body, time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify with wrong secret = %v", err)
+	}
+}
+
+func TestSendNon2xx(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"2xxは成功", http.StatusNoContent, false},
+		{"リダイレクトには従わない", http.StatusFound, true},
+		{"4xxはエラー", http.StatusGone, true},
+		{"5xxはエラー", http.StatusServiceUnavailable, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newRecordingServer(t, tt.status)
+			client := server.Client()
+			client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			}
+			result, err := NewSender(client).Send(server.URL, "secret", "plan.updated", 1, []byte(`{}`))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result.StatusCode != tt.status {
+				t.Fatalf("StatusCode = %d, want %d", result.StatusCode, tt.status)
+			}
+		})
+	}
+}
+
+func TestHTTPClientFromEnvRejectsPrivateAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		goEnv   string
+		wantErr error
+	}{
+		{"本番ではループバックを拒否する", "", ErrPrivateAddress},
+		{"GO_ENV=devではループバックに送れる", "dev", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GO_ENV", tt.goEnv)
+			server := newRecordingServer(t, http.StatusOK)
+			_, err := NewSender(NewHTTPClientFromEnv()).Send(server.URL, "secret", "plan.updated", 1, []byte(`{}`))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && len(server.requests) > 0 {
+				t.Fatalf("request reached the server")
+			}
+		})
+	}
+}
+
+func mustParseInt(t *testing.T, s string) int64 {
+	t.Helper()
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		t.Fatalf("parse %q: %v", s, err)
+	}
+	return v
+}
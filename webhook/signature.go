@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 受信側に送るヘッダー
+const (
+	HeaderSignature = "X-ClassPlanner-Signature" // t=送信時刻(UNIX秒),v1=署名
+	HeaderEvent     = "X-ClassPlanner-Event"
+	HeaderDelivery  = "X-ClassPlanner-Delivery" // 自動の再送では同じ。受信側で重複を除くのに使う
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// "送信時刻.本文" をHMAC-SHA256で署名する。時刻を含めるのはリプレイ攻撃を防ぐため
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + hex.EncodeToString(signature(secret, t, body))
+}
+
+// 受信側での検証用。送信時刻がtoleranceより古い場合もエラーにする
+func Verify(secret string, header string, body []byte, tolerance time.Duration) error {
+	var t, v1 string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			v1 = value
+		}
+	}
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	mac, err := hex.DecodeString(v1)
+	if err != nil || !hmac.Equal(mac, signature(secret, t, body)) {
+		return ErrInvalidSignature
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp out of tolerance", ErrInvalidSignature)
+	}
+	return nil
+}
+
+func signature(secret string, t string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t + "."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}